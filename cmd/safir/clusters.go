@@ -0,0 +1,184 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+var clustersResource = resource{
+	summary: "Manage clusters",
+	commands: map[string]command{
+		"list":   {usage: "list", summary: "List clusters", run: clusterList},
+		"show":   {usage: "show <cluster-id>", summary: "Show a cluster", run: clusterShow},
+		"create": {usage: "create --name <name> [--description <text>]", summary: "Create a cluster", run: clusterCreate},
+		"update": {usage: "update <cluster-id> [--name <name>] [--description <text>]", summary: "Update a cluster", run: clusterUpdate},
+		"delete": {usage: "delete [--cascade [--dry-run]] <cluster-id>...", summary: "Delete clusters", run: clusterDelete},
+	},
+}
+
+func clusterTable(clusters ...optimization.Cluster) table {
+	t := table{headers: []string{"ID", "NAME", "DESCRIPTION", "CREATED AT", "UPDATED AT"}}
+	for _, c := range clusters {
+		t.rows = append(t.rows, []string{c.ID, c.Name, c.Description, c.CreatedAt, c.UpdatedAt})
+	}
+	return t
+}
+
+func clusterFields(c *optimization.Cluster) table {
+	return fields(
+		"id", c.ID,
+		"name", c.Name,
+		"description", c.Description,
+		"created_at", c.CreatedAt,
+		"updated_at", c.UpdatedAt,
+	)
+}
+
+func clusterList(a *app, fs *flag.FlagSet, args []string) error {
+	if _, err := a.parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	client, err := a.getClient()
+	if err != nil {
+		return err
+	}
+
+	clusters, err := client.ListClusters()
+	if err != nil {
+		return err
+	}
+
+	return a.print(clusters, clusterTable(clusters...))
+}
+
+func clusterShow(a *app, fs *flag.FlagSet, args []string) error {
+	positional, err := a.parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.getClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := client.GetCluster(positional[0])
+	if err != nil {
+		return err
+	}
+
+	return a.print(cluster, clusterFields(cluster))
+}
+
+func clusterCreate(a *app, fs *flag.FlagSet, args []string) error {
+	req := &optimization.ClusterCreate{}
+	fs.StringVar(&req.Name, "name", "", "cluster name (required)")
+	fs.StringVar(&req.Description, "description", "", "cluster description")
+	if _, err := a.parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "name"); err != nil {
+		return err
+	}
+
+	client, err := a.getClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := client.CreateCluster(req)
+	if err != nil {
+		return err
+	}
+
+	return a.print(cluster, clusterFields(cluster))
+}
+
+func clusterUpdate(a *app, fs *flag.FlagSet, args []string) error {
+	req := &optimization.ClusterUpdate{}
+	var name, description string
+	fs.StringVar(&name, "name", "", "new cluster name")
+	fs.StringVar(&description, "description", "", "new cluster description (empty to clear)")
+	positional, err := a.parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if isSet(fs, "name") {
+		req.Name = &name
+	}
+	if isSet(fs, "description") {
+		req.Description = &description
+	}
+
+	client, err := a.getClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := client.UpdateCluster(positional[0], req)
+	if err != nil {
+		return err
+	}
+
+	return a.print(cluster, clusterFields(cluster))
+}
+
+func clusterDelete(a *app, fs *flag.FlagSet, args []string) error {
+	var opts optimization.CascadeDeleteOptions
+	cascade := fs.Bool("cascade", false, "also delete the policies, hosts and excluded VMs of the cluster")
+	fs.BoolVar(&opts.DryRun, "dry-run", false, "with --cascade, only list what would be deleted")
+	positional, err := a.parseArgs(fs, args, 1, -1)
+	if err != nil {
+		return err
+	}
+	if opts.DryRun && !*cascade {
+		fmt.Fprintln(a.stderr, "--dry-run requires --cascade")
+		fs.Usage()
+		return errUsage
+	}
+
+	client, err := a.getClient()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range positional {
+		if !*cascade {
+			if err := client.DeleteCluster(id); err != nil {
+				return err
+			}
+			a.printDeleted("cluster", id)
+			continue
+		}
+
+		result, err := client.DeleteClusterCascade(id, opts)
+		if result != nil {
+			if printErr := a.print(result, cascadeTable(result)); printErr != nil {
+				return printErr
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func cascadeTable(result *optimization.CascadeDeleteResult) table {
+	t := table{headers: []string{"KIND", "ID", "NAME", "STATUS"}}
+	for _, item := range result.Items {
+		status := "deleted"
+		switch {
+		case result.DryRun:
+			status = "would delete"
+		case item.Err != nil:
+			status = item.Err.Error()
+		}
+		t.rows = append(t.rows, []string{string(item.Kind), item.ID, item.Name, status})
+	}
+	return t
+}
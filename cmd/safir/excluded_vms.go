@@ -0,0 +1,118 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+var excludedVMsResource = resource{
+	summary: "Manage VMs excluded from optimization",
+	commands: map[string]command{
+		"list":   {usage: "list <cluster-id>", summary: "List the excluded VMs of a cluster", run: excludedVMList},
+		"show":   {usage: "show <cluster-id> <vm-id>", summary: "Show an excluded VM", run: excludedVMShow},
+		"create": {usage: "create <cluster-id> --vm-name <name>", summary: "Exclude a VM from optimization", run: excludedVMCreate},
+		"delete": {usage: "delete <cluster-id> <vm-id>...", summary: "Remove VMs from the excluded list", run: excludedVMDelete},
+	},
+}
+
+func excludedVMTable(vms ...optimization.ClusterExcludedVM) table {
+	t := table{headers: []string{"ID", "CLUSTER ID", "VM NAME", "CREATED AT", "UPDATED AT"}}
+	for _, vm := range vms {
+		t.rows = append(t.rows, []string{vm.ID, vm.ClusterID, vm.VMName, vm.CreatedAt, vm.UpdatedAt})
+	}
+	return t
+}
+
+func excludedVMFields(vm *optimization.ClusterExcludedVM) table {
+	return fields(
+		"id", vm.ID,
+		"cluster_id", vm.ClusterID,
+		"vm_name", vm.VMName,
+		"created_at", vm.CreatedAt,
+		"updated_at", vm.UpdatedAt,
+	)
+}
+
+func excludedVMList(a *app, fs *flag.FlagSet, args []string) error {
+	positional, err := a.parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.getClient()
+	if err != nil {
+		return err
+	}
+
+	vms, err := client.ListClusterExcludedVMs(positional[0])
+	if err != nil {
+		return err
+	}
+
+	return a.print(vms, excludedVMTable(vms...))
+}
+
+func excludedVMShow(a *app, fs *flag.FlagSet, args []string) error {
+	positional, err := a.parseArgs(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.getClient()
+	if err != nil {
+		return err
+	}
+
+	vm, err := client.GetClusterExcludedVM(positional[0], positional[1])
+	if err != nil {
+		return err
+	}
+
+	return a.print(vm, excludedVMFields(vm))
+}
+
+func excludedVMCreate(a *app, fs *flag.FlagSet, args []string) error {
+	req := &optimization.ClusterExcludedVMCreate{}
+	fs.StringVar(&req.VMName, "vm-name", "", "name of the VM to exclude (required)")
+	positional, err := a.parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "vm-name"); err != nil {
+		return err
+	}
+
+	client, err := a.getClient()
+	if err != nil {
+		return err
+	}
+
+	vm, err := client.CreateClusterExcludedVM(positional[0], req)
+	if err != nil {
+		return err
+	}
+
+	return a.print(vm, excludedVMFields(vm))
+}
+
+func excludedVMDelete(a *app, fs *flag.FlagSet, args []string) error {
+	positional, err := a.parseArgs(fs, args, 2, -1)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.getClient()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range positional[1:] {
+		if err := client.DeleteClusterExcludedVM(positional[0], id); err != nil {
+			return err
+		}
+		a.printDeleted("excluded VM", id)
+	}
+
+	return nil
+}
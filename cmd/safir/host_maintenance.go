@@ -0,0 +1,160 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+var hostMaintenanceResource = resource{
+	summary: "Manage host maintenance policies",
+	commands: map[string]command{
+		"list":   {usage: "list [--cluster-id <id>]", summary: "List host maintenance policies", run: hostMaintenanceList},
+		"show":   {usage: "show <policy-id>", summary: "Show a host maintenance policy", run: hostMaintenanceShow},
+		"create": {usage: "create --cluster-id <id> --name <name> [--enabled=false]", summary: "Create a host maintenance policy", run: hostMaintenanceCreate},
+		"update": {usage: "update <policy-id> [--cluster-id <id>] [--name <name>] [--enabled=true|false]", summary: "Update a host maintenance policy", run: hostMaintenanceUpdate},
+		"delete": {usage: "delete <policy-id>...", summary: "Delete host maintenance policies", run: hostMaintenanceDelete},
+	},
+}
+
+func hostMaintenanceTable(policies ...optimization.HostMaintenancePolicy) table {
+	t := table{headers: []string{"ID", "CLUSTER ID", "NAME", "ENABLED", "CREATED AT", "UPDATED AT"}}
+	for _, p := range policies {
+		t.rows = append(t.rows, []string{p.ID, p.ClusterID, p.Name, formatBool(p.Enabled), p.CreatedAt, p.UpdatedAt})
+	}
+	return t
+}
+
+func hostMaintenanceFields(p *optimization.HostMaintenancePolicy) table {
+	return fields(
+		"id", p.ID,
+		"cluster_id", p.ClusterID,
+		"name", p.Name,
+		"enabled", formatBool(p.Enabled),
+		"created_at", p.CreatedAt,
+		"updated_at", p.UpdatedAt,
+	)
+}
+
+func hostMaintenanceList(a *app, fs *flag.FlagSet, args []string) error {
+	clusterID := fs.String("cluster-id", "", "only list policies of this cluster")
+	if _, err := a.parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	client, err := a.getClient()
+	if err != nil {
+		return err
+	}
+
+	var filter *string
+	if isSet(fs, "cluster-id") {
+		filter = clusterID
+	}
+
+	policies, err := client.ListHostMaintenancePolicies(filter)
+	if err != nil {
+		return err
+	}
+
+	return a.print(policies, hostMaintenanceTable(policies...))
+}
+
+func hostMaintenanceShow(a *app, fs *flag.FlagSet, args []string) error {
+	positional, err := a.parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.getClient()
+	if err != nil {
+		return err
+	}
+
+	policy, err := client.GetHostMaintenancePolicy(positional[0])
+	if err != nil {
+		return err
+	}
+
+	return a.print(policy, hostMaintenanceFields(policy))
+}
+
+func hostMaintenanceCreate(a *app, fs *flag.FlagSet, args []string) error {
+	req := &optimization.HostMaintenancePolicyCreate{}
+	fs.StringVar(&req.ClusterID, "cluster-id", "", "cluster the policy applies to (required)")
+	fs.StringVar(&req.Name, "name", "", "policy name (required)")
+	fs.BoolVar(&req.Enabled, "enabled", true, "whether the policy is active")
+	if _, err := a.parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "cluster-id", "name"); err != nil {
+		return err
+	}
+
+	client, err := a.getClient()
+	if err != nil {
+		return err
+	}
+
+	policy, err := client.CreateHostMaintenancePolicy(req)
+	if err != nil {
+		return err
+	}
+
+	return a.print(policy, hostMaintenanceFields(policy))
+}
+
+func hostMaintenanceUpdate(a *app, fs *flag.FlagSet, args []string) error {
+	req := &optimization.HostMaintenancePolicyUpdate{}
+	var clusterID, name string
+	var enabled bool
+	fs.StringVar(&clusterID, "cluster-id", "", "new cluster ID")
+	fs.StringVar(&name, "name", "", "new policy name")
+	fs.BoolVar(&enabled, "enabled", false, "whether the policy is active")
+	positional, err := a.parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if isSet(fs, "cluster-id") {
+		req.ClusterID = &clusterID
+	}
+	if isSet(fs, "name") {
+		req.Name = &name
+	}
+	if isSet(fs, "enabled") {
+		req.Enabled = &enabled
+	}
+
+	client, err := a.getClient()
+	if err != nil {
+		return err
+	}
+
+	policy, err := client.UpdateHostMaintenancePolicy(positional[0], req)
+	if err != nil {
+		return err
+	}
+
+	return a.print(policy, hostMaintenanceFields(policy))
+}
+
+func hostMaintenanceDelete(a *app, fs *flag.FlagSet, args []string) error {
+	positional, err := a.parseArgs(fs, args, 1, -1)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.getClient()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range positional {
+		if err := client.DeleteHostMaintenancePolicy(id); err != nil {
+			return err
+		}
+		a.printDeleted("host maintenance policy", id)
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+var hostsResource = resource{
+	summary: "Manage cluster hosts",
+	commands: map[string]command{
+		"list":   {usage: "list <cluster-id>", summary: "List the hosts of a cluster", run: hostList},
+		"show":   {usage: "show <cluster-id> <host-id>", summary: "Show a cluster host", run: hostShow},
+		"create": {usage: "create <cluster-id> --hostname <name> [--enabled=false]", summary: "Add a host to a cluster", run: hostCreate},
+		"update": {usage: "update <cluster-id> <host-id> [--hostname <name>] [--enabled=true|false]", summary: "Update a cluster host", run: hostUpdate},
+		"delete": {usage: "delete <cluster-id> <host-id>...", summary: "Remove hosts from a cluster", run: hostDelete},
+	},
+}
+
+func hostTable(hosts ...optimization.ClusterHost) table {
+	t := table{headers: []string{"ID", "CLUSTER ID", "HOSTNAME", "ENABLED", "CREATED AT", "UPDATED AT"}}
+	for _, h := range hosts {
+		t.rows = append(t.rows, []string{h.ID, h.ClusterID, h.Hostname, formatBool(h.Enabled), h.CreatedAt, h.UpdatedAt})
+	}
+	return t
+}
+
+func hostFields(h *optimization.ClusterHost) table {
+	return fields(
+		"id", h.ID,
+		"cluster_id", h.ClusterID,
+		"hostname", h.Hostname,
+		"enabled", formatBool(h.Enabled),
+		"created_at", h.CreatedAt,
+		"updated_at", h.UpdatedAt,
+	)
+}
+
+func hostList(a *app, fs *flag.FlagSet, args []string) error {
+	positional, err := a.parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.getClient()
+	if err != nil {
+		return err
+	}
+
+	hosts, err := client.ListClusterHosts(positional[0])
+	if err != nil {
+		return err
+	}
+
+	return a.print(hosts, hostTable(hosts...))
+}
+
+func hostShow(a *app, fs *flag.FlagSet, args []string) error {
+	positional, err := a.parseArgs(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.getClient()
+	if err != nil {
+		return err
+	}
+
+	host, err := client.GetClusterHost(positional[0], positional[1])
+	if err != nil {
+		return err
+	}
+
+	return a.print(host, hostFields(host))
+}
+
+func hostCreate(a *app, fs *flag.FlagSet, args []string) error {
+	req := &optimization.ClusterHostCreate{}
+	fs.StringVar(&req.Hostname, "hostname", "", "hypervisor hostname (required)")
+	fs.BoolVar(&req.Enabled, "enabled", true, "whether the host takes part in optimization")
+	positional, err := a.parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "hostname"); err != nil {
+		return err
+	}
+
+	client, err := a.getClient()
+	if err != nil {
+		return err
+	}
+
+	host, err := client.CreateClusterHost(positional[0], req)
+	if err != nil {
+		return err
+	}
+
+	return a.print(host, hostFields(host))
+}
+
+func hostUpdate(a *app, fs *flag.FlagSet, args []string) error {
+	req := &optimization.ClusterHostUpdate{}
+	var hostname string
+	var enabled bool
+	fs.StringVar(&hostname, "hostname", "", "new hypervisor hostname")
+	fs.BoolVar(&enabled, "enabled", false, "whether the host takes part in optimization")
+	positional, err := a.parseArgs(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+	if isSet(fs, "hostname") {
+		req.Hostname = &hostname
+	}
+	if isSet(fs, "enabled") {
+		req.Enabled = &enabled
+	}
+
+	client, err := a.getClient()
+	if err != nil {
+		return err
+	}
+
+	host, err := client.UpdateClusterHost(positional[0], positional[1], req)
+	if err != nil {
+		return err
+	}
+
+	return a.print(host, hostFields(host))
+}
+
+func hostDelete(a *app, fs *flag.FlagSet, args []string) error {
+	positional, err := a.parseArgs(fs, args, 2, -1)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.getClient()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range positional[1:] {
+		if err := client.DeleteClusterHost(positional[0], id); err != nil {
+			return err
+		}
+		a.printDeleted("host", id)
+	}
+
+	return nil
+}
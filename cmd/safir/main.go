@@ -0,0 +1,288 @@
+// Command safir is a command-line client for the Safir Optimization API.
+//
+// Usage:
+//
+//	safir [global flags] <resource> <action> [flags] [arguments]
+//
+// Credentials are read from the OS_* environment variables or, when
+// --os-cloud or OS_CLOUD is set, from clouds.yaml. With --endpoint and
+// --token the Safir API is called directly without contacting Keystone.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+// errUsage is returned for invalid command lines; the usage has already been printed
+var errUsage = errors.New("invalid usage")
+
+// app holds the global options and the lazily created client
+type app struct {
+	stdout io.Writer
+	stderr io.Writer
+
+	format   string
+	cloud    string
+	endpoint string
+	token    string
+
+	client *optimization.Client
+}
+
+// command is one action on a resource
+type command struct {
+	usage   string
+	summary string
+	run     func(a *app, fs *flag.FlagSet, args []string) error
+}
+
+// resource groups the actions available on one resource type
+type resource struct {
+	summary  string
+	commands map[string]command
+}
+
+var resources = map[string]resource{
+	"clusters":               clustersResource,
+	"hosts":                  hostsResource,
+	"excluded-vms":           excludedVMsResource,
+	"host-maintenance":       hostMaintenanceResource,
+	"workload-balancing":     workloadBalancingResource,
+	"workload-consolidation": workloadConsolidationResource,
+}
+
+func main() {
+	a := &app{stdout: os.Stdout, stderr: os.Stderr}
+	if err := a.run(os.Args[1:]); err != nil {
+		if errors.Is(err, errUsage) {
+			os.Exit(2)
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run parses the global flags and dispatches to the resource action
+func (a *app) run(args []string) error {
+	fs := flag.NewFlagSet("safir", flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.StringVar(&a.format, "format", "table", "output format: table, json or yaml")
+	fs.StringVar(&a.format, "f", "table", "shorthand for --format")
+	fs.StringVar(&a.cloud, "os-cloud", common.GetEnvOrDefault("OS_CLOUD", ""), "cloud name in clouds.yaml (env OS_CLOUD)")
+	fs.StringVar(&a.endpoint, "endpoint", common.GetEnvOrDefault("SAFIR_ENDPOINT", ""), "Safir Optimization endpoint, skips catalog lookup (env SAFIR_ENDPOINT)")
+	fs.StringVar(&a.token, "token", "", "Keystone token to use with --endpoint (default env OS_TOKEN)")
+	fs.Usage = func() { a.usage(fs) }
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return errUsage
+	}
+
+	switch a.format {
+	case "table", "json", "yaml":
+	default:
+		fmt.Fprintf(a.stderr, "unknown output format %q\n", a.format)
+		return errUsage
+	}
+
+	rest := fs.Args()
+	if len(rest) == 0 || rest[0] == "help" {
+		a.usage(fs)
+		if len(rest) == 0 {
+			return errUsage
+		}
+		return nil
+	}
+
+	res, ok := resources[rest[0]]
+	if !ok {
+		fmt.Fprintf(a.stderr, "unknown resource %q\n\n", rest[0])
+		a.usage(fs)
+		return errUsage
+	}
+
+	if len(rest) < 2 {
+		a.resourceUsage(rest[0], res)
+		return errUsage
+	}
+
+	cmd, ok := res.commands[rest[1]]
+	if !ok {
+		fmt.Fprintf(a.stderr, "unknown action %q for %s\n\n", rest[1], rest[0])
+		a.resourceUsage(rest[0], res)
+		return errUsage
+	}
+
+	return cmd.run(a, a.newFlagSet(rest[0], rest[1], cmd.usage), rest[2:])
+}
+
+// usage prints the global help
+func (a *app) usage(fs *flag.FlagSet) {
+	fmt.Fprintln(a.stderr, "Usage: safir [global flags] <resource> <action> [flags] [arguments]")
+	fmt.Fprintln(a.stderr)
+	fmt.Fprintln(a.stderr, "Resources:")
+	for _, name := range sortedKeys(resources) {
+		fmt.Fprintf(a.stderr, "  %-24s %s\n", name, resources[name].summary)
+	}
+	fmt.Fprintln(a.stderr)
+	fmt.Fprintln(a.stderr, "Global flags:")
+	fs.PrintDefaults()
+}
+
+// resourceUsage prints the actions of a resource
+func (a *app) resourceUsage(name string, res resource) {
+	fmt.Fprintf(a.stderr, "Usage: safir %s <action>\n\nActions:\n", name)
+	for _, action := range sortedKeys(res.commands) {
+		cmd := res.commands[action]
+		fmt.Fprintf(a.stderr, "  %s\n    \t%s\n", cmd.usage, cmd.summary)
+	}
+}
+
+// getClient creates the Optimization client on first use
+func (a *app) getClient() (*optimization.Client, error) {
+	if a.client != nil {
+		return a.client, nil
+	}
+
+	token := a.token
+	if token == "" {
+		token = common.GetEnvOrDefault("OS_TOKEN", "")
+	}
+
+	if a.endpoint != "" && token != "" {
+		a.client = optimization.NewClientWithToken(a.endpoint, token)
+		return a.client, nil
+	}
+
+	var authOpts *common.AuthOptions
+	var err error
+	if a.cloud != "" {
+		authOpts, err = common.AuthOptionsFromCloud(a.cloud)
+	} else {
+		authOpts, err = common.AuthOptionsFromEnv()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load credentials: %w", err)
+	}
+
+	auth, err := common.NewAuthenticator(authOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.endpoint != "" {
+		token, err := auth.GetToken()
+		if err != nil {
+			return nil, err
+		}
+		a.client = optimization.NewClientWithToken(a.endpoint, token)
+		return a.client, nil
+	}
+
+	a.client, err = optimization.NewClientWithAuthenticator(auth)
+	if err != nil {
+		return nil, err
+	}
+	return a.client, nil
+}
+
+// parseFlags parses flags that may be interleaved with positional arguments
+// and returns the positional arguments
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+
+		rest := fs.Args()
+
+		// "--" ends the flags, everything after it is positional
+		if n := len(args) - len(rest); n > 0 && args[n-1] == "--" {
+			return append(positional, rest...), nil
+		}
+
+		if len(rest) == 0 {
+			return positional, nil
+		}
+
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// newFlagSet creates the flag set of an action
+func (a *app) newFlagSet(resourceName, action, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(resourceName+" "+action, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(a.stderr, "Usage: safir %s %s\n", resourceName, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseArgs parses an action's command line and checks the number of
+// positional arguments. max < 0 means unbounded.
+func (a *app) parseArgs(fs *flag.FlagSet, args []string, min, max int) ([]string, error) {
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return nil, errUsage
+	}
+
+	if len(positional) < min || (max >= 0 && len(positional) > max) {
+		fs.Usage()
+		return nil, errUsage
+	}
+
+	return positional, nil
+}
+
+// isSet reports whether a flag was given on the command line
+func isSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// requireFlags prints the usage if any of the named flags is missing
+func requireFlags(fs *flag.FlagSet, names ...string) error {
+	var missing []string
+	for _, name := range names {
+		if !isSet(fs, name) {
+			missing = append(missing, "--"+name)
+		}
+	}
+
+	if len(missing) > 0 {
+		fmt.Fprintf(fs.Output(), "missing required flags: %s\n", strings.Join(missing, ", "))
+		fs.Usage()
+		return errUsage
+	}
+
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
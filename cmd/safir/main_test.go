@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/overwatch144/golang-safirclient/fakesafir"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+// runCLI runs the command line against the fake server and returns its
+// standard output
+func runCLI(t *testing.T, srv *fakesafir.Server, args ...string) (string, error) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	a := &app{stdout: &stdout, stderr: &stderr}
+	global := []string{"--endpoint", srv.OptimizationEndpoint(), "--token", srv.IssueToken()}
+	err := a.run(append(global, args...))
+	if err != nil && stderr.Len() > 0 {
+		t.Logf("stderr: %s", stderr.String())
+	}
+	return stdout.String(), err
+}
+
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		args       []string
+		positional []string
+		name       string
+	}{
+		{[]string{"a", "b"}, []string{"a", "b"}, ""},
+		{[]string{"--name", "x", "a"}, []string{"a"}, "x"},
+		{[]string{"a", "--name", "x", "b"}, []string{"a", "b"}, "x"},
+		{[]string{"a", "--", "--name", "-b"}, []string{"a", "--name", "-b"}, ""},
+		{[]string{"--name", "x", "--", "-a"}, []string{"-a"}, "x"},
+	}
+
+	for _, test := range tests {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		name := fs.String("name", "", "")
+
+		positional, err := parseFlags(fs, test.args)
+		if err != nil {
+			t.Errorf("parseFlags(%q): %v", test.args, err)
+			continue
+		}
+		if !reflect.DeepEqual(positional, test.positional) || *name != test.name {
+			t.Errorf("parseFlags(%q) = %q with --name %q, want %q with %q", test.args, positional, *name, test.positional, test.name)
+		}
+	}
+}
+
+func TestClusterCommands(t *testing.T) {
+	srv := fakesafir.NewServer()
+	defer srv.Close()
+
+	out, err := runCLI(t, srv, "-f", "json", "clusters", "create", "--name", "prod", "--description", "production")
+	if err != nil {
+		t.Fatalf("clusters create: %v", err)
+	}
+	var cluster optimization.Cluster
+	if err := json.Unmarshal([]byte(out), &cluster); err != nil || cluster.ID == "" || cluster.Name != "prod" {
+		t.Fatalf("clusters create printed %q (%v)", out, err)
+	}
+
+	if _, err := runCLI(t, srv, "hosts", "create", cluster.ID, "--hostname", "compute-01"); err != nil {
+		t.Fatalf("hosts create: %v", err)
+	}
+
+	out, err = runCLI(t, srv, "clusters", "list")
+	if err != nil {
+		t.Fatalf("clusters list: %v", err)
+	}
+	if !strings.Contains(out, "NAME") || !strings.Contains(out, cluster.ID) || !strings.Contains(out, "production") {
+		t.Errorf("clusters list printed:\n%s", out)
+	}
+
+	out, err = runCLI(t, srv, "-f", "yaml", "clusters", "update", cluster.ID, "--description", "")
+	if err != nil {
+		t.Fatalf("clusters update: %v", err)
+	}
+	if !strings.Contains(out, "name: prod") || strings.Contains(out, "production") {
+		t.Errorf("clusters update printed:\n%s", out)
+	}
+
+	// A dry run never deletes, and is only offered with --cascade
+	if _, err := runCLI(t, srv, "clusters", "delete", "--dry-run", cluster.ID); !errors.Is(err, errUsage) {
+		t.Errorf("clusters delete --dry-run = %v, want a usage error", err)
+	}
+	out, err = runCLI(t, srv, "clusters", "delete", "--cascade", "--dry-run", cluster.ID)
+	if err != nil {
+		t.Fatalf("clusters delete --cascade --dry-run: %v", err)
+	}
+	if !strings.Contains(out, "would delete") || srv.Count("clusters") != 1 || srv.Count("hosts") != 1 {
+		t.Errorf("dry run left %d clusters and %d hosts, printed:\n%s", srv.Count("clusters"), srv.Count("hosts"), out)
+	}
+
+	if _, err := runCLI(t, srv, "clusters", "delete", "--cascade", cluster.ID); err != nil {
+		t.Fatalf("clusters delete --cascade: %v", err)
+	}
+	if srv.Count("clusters") != 0 || srv.Count("hosts") != 0 {
+		t.Errorf("%d clusters and %d hosts left after a cascading delete", srv.Count("clusters"), srv.Count("hosts"))
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	srv := fakesafir.NewServer()
+	defer srv.Close()
+
+	for _, args := range [][]string{
+		{},
+		{"unknown"},
+		{"clusters"},
+		{"clusters", "unknown"},
+		{"clusters", "show"},
+		{"clusters", "create"},
+		{"-f", "xml", "clusters", "list"},
+	} {
+		if _, err := runCLI(t, srv, args...); !errors.Is(err, errUsage) {
+			t.Errorf("safir %q = %v, want a usage error", args, err)
+		}
+	}
+
+	if srv.Requests() != 0 {
+		t.Errorf("usage errors sent %d requests", srv.Requests())
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"sigs.k8s.io/yaml"
+)
+
+// table is the tabular representation of a result
+type table struct {
+	headers []string
+	rows    [][]string
+}
+
+// print renders v in the selected output format. The table is used for the
+// table format only.
+func (a *app) print(v interface{}, t table) error {
+	switch a.format {
+	case "json":
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(a.stdout, string(data))
+		return err
+	case "yaml":
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = a.stdout.Write(data)
+		return err
+	}
+
+	w := tabwriter.NewWriter(a.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(t.headers, "\t"))
+	for _, row := range t.rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+// fields builds a two-column Field/Value table from name/value pairs
+func fields(pairs ...string) table {
+	t := table{headers: []string{"FIELD", "VALUE"}}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		t.rows = append(t.rows, []string{pairs[i], pairs[i+1]})
+	}
+	return t
+}
+
+// printDeleted reports a successful delete
+func (a *app) printDeleted(kind, id string) {
+	if a.format == "table" {
+		fmt.Fprintf(a.stdout, "Deleted %s %s\n", kind, id)
+	}
+}
+
+func formatBool(b bool) string {
+	return strconv.FormatBool(b)
+}
+
+func formatInt(i int) string {
+	return strconv.Itoa(i)
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"flag"
+	"strings"
+
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+var workloadBalancingResource = resource{
+	summary: "Manage workload balancing policies",
+	commands: map[string]command{
+		"list":   {usage: "list [--cluster-id <id>]", summary: "List workload balancing policies", run: workloadBalancingList},
+		"show":   {usage: "show <policy-id>", summary: "Show a workload balancing policy", run: workloadBalancingShow},
+		"create": {usage: "create --cluster-id <id> --name <name> --mode <mode> --period <seconds> [flags]", summary: "Create a workload balancing policy", run: workloadBalancingCreate},
+		"update": {usage: "update <policy-id> [flags]", summary: "Update a workload balancing policy", run: workloadBalancingUpdate},
+		"delete": {usage: "delete <policy-id>...", summary: "Delete workload balancing policies", run: workloadBalancingDelete},
+	},
+}
+
+func workloadBalancingTable(policies ...optimization.WorkloadBalancingPolicy) table {
+	t := table{headers: []string{"ID", "CLUSTER ID", "NAME", "MODE", "CPU", "MEMORY", "PERIOD", "ENABLED"}}
+	for _, p := range policies {
+		t.rows = append(t.rows, []string{p.ID, p.ClusterID, p.Name, string(p.BalancingMode),
+			formatBool(p.CPUBalancing), formatBool(p.MemoryBalancing), formatInt(p.Period), formatBool(p.Enabled)})
+	}
+	return t
+}
+
+func workloadBalancingFields(p *optimization.WorkloadBalancingPolicy) table {
+	return fields(
+		"id", p.ID,
+		"cluster_id", p.ClusterID,
+		"name", p.Name,
+		"balancing_mode", string(p.BalancingMode),
+		"cpu_balancing", formatBool(p.CPUBalancing),
+		"memory_balancing", formatBool(p.MemoryBalancing),
+		"period", formatInt(p.Period),
+		"enabled", formatBool(p.Enabled),
+		"created_at", p.CreatedAt,
+		"updated_at", p.UpdatedAt,
+	)
+}
+
+func workloadBalancingList(a *app, fs *flag.FlagSet, args []string) error {
+	clusterID := fs.String("cluster-id", "", "only list policies of this cluster")
+	if _, err := a.parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	client, err := a.getClient()
+	if err != nil {
+		return err
+	}
+
+	var filter *string
+	if isSet(fs, "cluster-id") {
+		filter = clusterID
+	}
+
+	policies, err := client.ListWorkloadBalancingPolicies(filter)
+	if err != nil {
+		return err
+	}
+
+	return a.print(policies, workloadBalancingTable(policies...))
+}
+
+func workloadBalancingShow(a *app, fs *flag.FlagSet, args []string) error {
+	positional, err := a.parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.getClient()
+	if err != nil {
+		return err
+	}
+
+	policy, err := client.GetWorkloadBalancingPolicy(positional[0])
+	if err != nil {
+		return err
+	}
+
+	return a.print(policy, workloadBalancingFields(policy))
+}
+
+func workloadBalancingCreate(a *app, fs *flag.FlagSet, args []string) error {
+	req := &optimization.WorkloadBalancingPolicyCreate{}
+	fs.StringVar(&req.ClusterID, "cluster-id", "", "cluster the policy applies to (required)")
+	fs.StringVar(&req.Name, "name", "", "policy name (required)")
+	fs.Var((*balancingModeFlag)(&req.BalancingMode), "mode", "balancing `mode`: "+balancingModeList()+" (required)")
+	fs.BoolVar(&req.CPUBalancing, "cpu", true, "balance CPU usage")
+	fs.BoolVar(&req.MemoryBalancing, "memory", true, "balance memory usage")
+	fs.IntVar(&req.Period, "period", 0, "evaluation period in seconds (required)")
+	fs.BoolVar(&req.Enabled, "enabled", true, "whether the policy is active")
+	if _, err := a.parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "cluster-id", "name", "mode", "period"); err != nil {
+		return err
+	}
+
+	client, err := a.getClient()
+	if err != nil {
+		return err
+	}
+
+	policy, err := client.CreateWorkloadBalancingPolicy(req)
+	if err != nil {
+		return err
+	}
+
+	return a.print(policy, workloadBalancingFields(policy))
+}
+
+func workloadBalancingUpdate(a *app, fs *flag.FlagSet, args []string) error {
+	req := &optimization.WorkloadBalancingPolicyUpdate{}
+	var clusterID, name string
+	var period int
+	var mode optimization.BalancingMode
+	var cpu, memory, enabled bool
+	fs.StringVar(&clusterID, "cluster-id", "", "new cluster ID")
+	fs.StringVar(&name, "name", "", "new policy name")
+	fs.Var((*balancingModeFlag)(&mode), "mode", "new balancing `mode`: "+balancingModeList())
+	fs.BoolVar(&cpu, "cpu", false, "balance CPU usage")
+	fs.BoolVar(&memory, "memory", false, "balance memory usage")
+	fs.IntVar(&period, "period", 0, "new evaluation period in seconds")
+	fs.BoolVar(&enabled, "enabled", false, "whether the policy is active")
+	positional, err := a.parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if isSet(fs, "cluster-id") {
+		req.ClusterID = &clusterID
+	}
+	if isSet(fs, "name") {
+		req.Name = &name
+	}
+	if isSet(fs, "mode") {
+		req.BalancingMode = &mode
+	}
+	if isSet(fs, "period") {
+		req.Period = &period
+	}
+	if isSet(fs, "cpu") {
+		req.CPUBalancing = &cpu
+	}
+	if isSet(fs, "memory") {
+		req.MemoryBalancing = &memory
+	}
+	if isSet(fs, "enabled") {
+		req.Enabled = &enabled
+	}
+
+	client, err := a.getClient()
+	if err != nil {
+		return err
+	}
+
+	policy, err := client.UpdateWorkloadBalancingPolicy(positional[0], req)
+	if err != nil {
+		return err
+	}
+
+	return a.print(policy, workloadBalancingFields(policy))
+}
+
+func workloadBalancingDelete(a *app, fs *flag.FlagSet, args []string) error {
+	positional, err := a.parseArgs(fs, args, 1, -1)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.getClient()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range positional {
+		if err := client.DeleteWorkloadBalancingPolicy(id); err != nil {
+			return err
+		}
+		a.printDeleted("workload balancing policy", id)
+	}
+
+	return nil
+}
+
+// balancingModeFlag is a flag.Value accepting the supported balancing modes
+type balancingModeFlag optimization.BalancingMode
+
+func (f *balancingModeFlag) String() string {
+	return string(*f)
+}
+
+func (f *balancingModeFlag) Set(s string) error {
+	mode, err := optimization.ParseBalancingMode(s)
+	if err != nil {
+		return err
+	}
+	*f = balancingModeFlag(mode)
+	return nil
+}
+
+func balancingModeList() string {
+	var names []string
+	for _, mode := range optimization.BalancingModes() {
+		names = append(names, string(mode))
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+var workloadConsolidationResource = resource{
+	summary: "Manage workload consolidation policies",
+	commands: map[string]command{
+		"list":   {usage: "list [--cluster-id <id>]", summary: "List workload consolidation policies", run: workloadConsolidationList},
+		"show":   {usage: "show <policy-id>", summary: "Show a workload consolidation policy", run: workloadConsolidationShow},
+		"create": {usage: "create --cluster-id <id> --name <name> --period <seconds> [--enabled=false]", summary: "Create a workload consolidation policy", run: workloadConsolidationCreate},
+		"update": {usage: "update <policy-id> [--cluster-id <id>] [--name <name>] [--period <seconds>] [--enabled=true|false]", summary: "Update a workload consolidation policy", run: workloadConsolidationUpdate},
+		"delete": {usage: "delete <policy-id>...", summary: "Delete workload consolidation policies", run: workloadConsolidationDelete},
+	},
+}
+
+func workloadConsolidationTable(policies ...optimization.WorkloadConsolidationPolicy) table {
+	t := table{headers: []string{"ID", "CLUSTER ID", "NAME", "PERIOD", "ENABLED", "CREATED AT", "UPDATED AT"}}
+	for _, p := range policies {
+		t.rows = append(t.rows, []string{p.ID, p.ClusterID, p.Name, formatInt(p.Period), formatBool(p.Enabled), p.CreatedAt, p.UpdatedAt})
+	}
+	return t
+}
+
+func workloadConsolidationFields(p *optimization.WorkloadConsolidationPolicy) table {
+	return fields(
+		"id", p.ID,
+		"cluster_id", p.ClusterID,
+		"name", p.Name,
+		"period", formatInt(p.Period),
+		"enabled", formatBool(p.Enabled),
+		"created_at", p.CreatedAt,
+		"updated_at", p.UpdatedAt,
+	)
+}
+
+func workloadConsolidationList(a *app, fs *flag.FlagSet, args []string) error {
+	clusterID := fs.String("cluster-id", "", "only list policies of this cluster")
+	if _, err := a.parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	client, err := a.getClient()
+	if err != nil {
+		return err
+	}
+
+	var filter *string
+	if isSet(fs, "cluster-id") {
+		filter = clusterID
+	}
+
+	policies, err := client.ListWorkloadConsolidationPolicies(filter)
+	if err != nil {
+		return err
+	}
+
+	return a.print(policies, workloadConsolidationTable(policies...))
+}
+
+func workloadConsolidationShow(a *app, fs *flag.FlagSet, args []string) error {
+	positional, err := a.parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.getClient()
+	if err != nil {
+		return err
+	}
+
+	policy, err := client.GetWorkloadConsolidationPolicy(positional[0])
+	if err != nil {
+		return err
+	}
+
+	return a.print(policy, workloadConsolidationFields(policy))
+}
+
+func workloadConsolidationCreate(a *app, fs *flag.FlagSet, args []string) error {
+	req := &optimization.WorkloadConsolidationPolicyCreate{}
+	fs.StringVar(&req.ClusterID, "cluster-id", "", "cluster the policy applies to (required)")
+	fs.StringVar(&req.Name, "name", "", "policy name (required)")
+	fs.IntVar(&req.Period, "period", 0, "evaluation period in seconds (required)")
+	fs.BoolVar(&req.Enabled, "enabled", true, "whether the policy is active")
+	if _, err := a.parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "cluster-id", "name", "period"); err != nil {
+		return err
+	}
+
+	client, err := a.getClient()
+	if err != nil {
+		return err
+	}
+
+	policy, err := client.CreateWorkloadConsolidationPolicy(req)
+	if err != nil {
+		return err
+	}
+
+	return a.print(policy, workloadConsolidationFields(policy))
+}
+
+func workloadConsolidationUpdate(a *app, fs *flag.FlagSet, args []string) error {
+	req := &optimization.WorkloadConsolidationPolicyUpdate{}
+	var clusterID, name string
+	var period int
+	var enabled bool
+	fs.StringVar(&clusterID, "cluster-id", "", "new cluster ID")
+	fs.StringVar(&name, "name", "", "new policy name")
+	fs.IntVar(&period, "period", 0, "new evaluation period in seconds")
+	fs.BoolVar(&enabled, "enabled", false, "whether the policy is active")
+	positional, err := a.parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if isSet(fs, "cluster-id") {
+		req.ClusterID = &clusterID
+	}
+	if isSet(fs, "name") {
+		req.Name = &name
+	}
+	if isSet(fs, "period") {
+		req.Period = &period
+	}
+	if isSet(fs, "enabled") {
+		req.Enabled = &enabled
+	}
+
+	client, err := a.getClient()
+	if err != nil {
+		return err
+	}
+
+	policy, err := client.UpdateWorkloadConsolidationPolicy(positional[0], req)
+	if err != nil {
+		return err
+	}
+
+	return a.print(policy, workloadConsolidationFields(policy))
+}
+
+func workloadConsolidationDelete(a *app, fs *flag.FlagSet, args []string) error {
+	positional, err := a.parseArgs(fs, args, 1, -1)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.getClient()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range positional {
+		if err := client.DeleteWorkloadConsolidationPolicy(id); err != nil {
+			return err
+		}
+		a.printDeleted("workload consolidation policy", id)
+	}
+
+	return nil
+}
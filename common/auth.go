@@ -20,6 +20,11 @@ type Authenticator struct {
 	endpoints   map[ServiceType]string
 	mutex       sync.RWMutex
 	autoReauth  bool
+
+	// authStarted and authFinished bound the local time at which the
+	// current token was issued, for measuring clock skew
+	authStarted  time.Time
+	authFinished time.Time
 }
 
 // NewAuthenticator creates a new authenticator instance
@@ -72,11 +77,14 @@ func (a *Authenticator) Authenticate() error {
 
 	// Create authenticated client with context
 	ctx := context.Background()
+	started := time.Now()
 	provider, err := openstack.AuthenticatedClient(ctx, authOpts)
 	if err != nil {
 		return fmt.Errorf("failed to authenticate: %w", err)
 	}
 
+	a.authStarted = started
+	a.authFinished = time.Now()
 	a.provider = provider
 	a.token = provider.TokenID
 
@@ -0,0 +1,255 @@
+package common
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL is how long a cached response is served without asking
+// the server again
+const DefaultCacheTTL = 30 * time.Second
+
+// CacheConfig configures a ResponseCache
+type CacheConfig struct {
+	// DefaultTTL applies to resources without an entry in TTLs (default 30s)
+	DefaultTTL time.Duration
+	// TTLs overrides the TTL per resource. The resource is the last
+	// collection in the request path, e.g. "clusters" for /clusters and
+	// /clusters/{id}, "hosts" for /clusters/{id}/hosts. A zero TTL disables
+	// caching for that resource.
+	TTLs map[string]time.Duration
+}
+
+// CacheStats are the counters of a ResponseCache
+type CacheStats struct {
+	// Hits were served from the cache without a request
+	Hits int64
+	// Misses were fetched from the server
+	Misses int64
+	// Revalidations were expired entries confirmed by a 304 Not Modified
+	Revalidations int64
+	// Invalidations counts the entries dropped because of writes
+	Invalidations int64
+	// Entries is the current number of cached responses
+	Entries int
+}
+
+// ResponseCache is a read-through cache for GET responses. Entries expire
+// after their TTL; entries with an ETag are then revalidated with
+// If-None-Match. Successful writes through the same client invalidate the
+// affected entries. Entries are kept apart per endpoint and per Keystone user
+// and project (or token), so a cache can be shared by clients of different
+// endpoints and credentials.
+type ResponseCache struct {
+	mutex   sync.Mutex
+	config  CacheConfig
+	entries map[cacheKey]*cacheEntry
+	stats   CacheStats
+}
+
+// cacheKey identifies a cached response by the scope of the client that
+// fetched it, see BaseClient.cacheScope, and the request path
+type cacheKey struct {
+	scope string
+	path  string
+}
+
+type cacheEntry struct {
+	status  int
+	header  http.Header
+	body    []byte
+	etag    string
+	expires time.Time
+}
+
+// NewResponseCache creates an empty cache
+func NewResponseCache(config CacheConfig) *ResponseCache {
+	if config.DefaultTTL <= 0 {
+		config.DefaultTTL = DefaultCacheTTL
+	}
+
+	return &ResponseCache{
+		config:  config,
+		entries: make(map[cacheKey]*cacheEntry),
+	}
+}
+
+// Stats returns a snapshot of the cache counters
+func (c *ResponseCache) Stats() CacheStats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	stats := c.stats
+	stats.Entries = len(c.entries)
+	return stats
+}
+
+// Flush drops every cached response
+func (c *ResponseCache) Flush() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.stats.Invalidations += int64(len(c.entries))
+	c.entries = make(map[cacheKey]*cacheEntry)
+}
+
+// ttl returns the TTL for a path; zero means the path is not cached
+func (c *ResponseCache) ttl(path string) time.Duration {
+	if ttl, ok := c.config.TTLs[cacheResource(path)]; ok {
+		return ttl
+	}
+	return c.config.DefaultTTL
+}
+
+// lookup returns the entry for a key and whether it is still fresh
+func (c *ResponseCache) lookup(key cacheKey) (*cacheEntry, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	if time.Now().Before(entry.expires) {
+		c.stats.Hits++
+		return entry, true
+	}
+
+	// Without an ETag an expired entry is of no use
+	if entry.etag == "" {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry, false
+}
+
+func (c *ResponseCache) miss() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.stats.Misses++
+}
+
+func (c *ResponseCache) store(key cacheKey, resp *http.Response, body []byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries[key] = &cacheEntry{
+		status:  resp.StatusCode,
+		header:  resp.Header.Clone(),
+		body:    body,
+		etag:    resp.Header.Get("ETag"),
+		expires: time.Now().Add(c.ttl(key.path)),
+	}
+}
+
+func (c *ResponseCache) revalidated(path string, entry *cacheEntry) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.stats.Revalidations++
+	entry.expires = time.Now().Add(c.ttl(path))
+}
+
+// invalidate drops the entries a write to path may have changed. Creates
+// and updates affect the top-level collection of the path, e.g. a new host
+// invalidates /clusters and everything below it. Deletes may cascade on the
+// server and flush the whole cache. Entries of every scope are affected.
+func (c *ResponseCache) invalidate(method, path string) {
+	if method == http.MethodDelete {
+		c.Flush()
+		return
+	}
+
+	root := "/" + strings.SplitN(strings.TrimPrefix(stripQuery(path), "/"), "/", 2)[0]
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for key := range c.entries {
+		keyPath := stripQuery(key.path)
+		if keyPath == root || strings.HasPrefix(keyPath, root+"/") {
+			delete(c.entries, key)
+			c.stats.Invalidations++
+		}
+	}
+}
+
+// response rebuilds an http.Response from a cached entry
+func (e *cacheEntry) response() *http.Response {
+	return &http.Response{
+		StatusCode:    e.status,
+		Status:        http.StatusText(e.status),
+		Header:        e.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+	}
+}
+
+// cacheResource returns the last collection name of a path: the segments at
+// even positions are collections, the ones in between IDs
+func cacheResource(path string) string {
+	segments := strings.Split(strings.Trim(stripQuery(path), "/"), "/")
+	return segments[(len(segments)-1)&^1]
+}
+
+func stripQuery(path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		return path[:i]
+	}
+	return path
+}
+
+// cachedGet serves a GET request of a client with the given scope from the
+// cache, fetching or revalidating it through fetch when needed. Only 2xx
+// responses are stored.
+func (c *ResponseCache) cachedGet(scope, path string, header http.Header, fetch func(header http.Header) (*http.Response, error)) (*http.Response, error) {
+	if c.ttl(path) <= 0 {
+		return fetch(header)
+	}
+
+	key := cacheKey{scope: scope, path: path}
+	entry, fresh := c.lookup(key)
+	if fresh {
+		return entry.response(), nil
+	}
+
+	if entry != nil {
+		header = header.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+		header.Set("If-None-Match", entry.etag)
+	}
+
+	resp, err := fetch(header)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		resp.Body.Close()
+		c.revalidated(path, entry)
+		return entry.response(), nil
+	}
+
+	c.miss()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	c.store(key, resp, body)
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
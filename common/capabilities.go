@@ -0,0 +1,207 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// Feature is a family of resources a Safir deployment may offer
+type Feature string
+
+const (
+	FeatureWorkloadBalancing     Feature = "workload-balancing"
+	FeatureWorkloadConsolidation Feature = "workload-consolidation"
+	FeatureHostMaintenance       Feature = "host-maintenance"
+	FeatureTrials                Feature = "trials"
+	FeatureMigration             Feature = "migration"
+	FeatureCloudWatcher          Feature = "cloud-watcher"
+)
+
+// String returns the string representation of Feature
+func (f Feature) String() string {
+	return string(f)
+}
+
+// rootFeatures are the features a service lists as resources in its root
+// document
+var rootFeatures = map[ServiceType][]Feature{
+	ServiceTypeOptimization: {
+		FeatureWorkloadBalancing,
+		FeatureWorkloadConsolidation,
+		FeatureHostMaintenance,
+		FeatureTrials,
+	},
+}
+
+// baseFeatures are offered by every deployment of a service; they are
+// assumed when its root document lists none of its rootFeatures
+var baseFeatures = map[ServiceType][]Feature{
+	ServiceTypeOptimization: {
+		FeatureWorkloadBalancing,
+		FeatureWorkloadConsolidation,
+		FeatureHostMaintenance,
+	},
+}
+
+// catalogFeatures are offered as services of their own, found in the
+// service catalog
+var catalogFeatures = map[Feature]ServiceType{
+	FeatureMigration:    ServiceTypeMigration,
+	FeatureCloudWatcher: ServiceTypeCloudWatcher,
+}
+
+// ErrNotSupported is matched by errors.Is for operations the deployment
+// does not offer, see NotSupportedError and UnsupportedVersionError
+var ErrNotSupported = errors.New("not supported by this Safir deployment")
+
+// NotSupportedError reports that an operation needs a feature the
+// deployment does not offer
+type NotSupportedError struct {
+	Operation string
+	Feature   Feature
+}
+
+func (e *NotSupportedError) Error() string {
+	return fmt.Sprintf("%s requires %s, which is not available on this Safir deployment", e.Operation, e.Feature)
+}
+
+// Is makes errors.Is(err, ErrNotSupported) true
+func (e *NotSupportedError) Is(target error) bool {
+	return target == ErrNotSupported
+}
+
+// Is makes errors.Is(err, ErrNotSupported) true
+func (e *UnsupportedVersionError) Is(target error) bool {
+	return target == ErrNotSupported
+}
+
+// IsNotSupported checks if the error is a NotSupportedError or an
+// UnsupportedVersionError
+func IsNotSupported(err error) bool {
+	return errors.Is(err, ErrNotSupported)
+}
+
+// Capabilities describes what a Safir deployment offers
+type Capabilities struct {
+	// Version describes the API version the client talks; nil if the
+	// server has no version document
+	Version *VersionInfo
+	// Features are the available resource families
+	Features []Feature
+}
+
+// Supports reports whether the feature is available
+func (c *Capabilities) Supports(feature Feature) bool {
+	for _, f := range c.Features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// capabilityState caches the capabilities of a client, shared by its copies
+type capabilityState struct {
+	mutex        sync.Mutex
+	capabilities *Capabilities
+}
+
+// Capabilities inspects the version document, the service root and the
+// service catalog to report what the deployment offers. The result is
+// cached for RequireFeature.
+func (c *BaseClient) Capabilities() (*Capabilities, error) {
+	return c.discoverCapabilities(context.Background())
+}
+
+func (c *BaseClient) discoverCapabilities(ctx context.Context) (*Capabilities, error) {
+	capabilities := &Capabilities{}
+
+	version, err := c.getVersion(ctx)
+	if err != nil && !IsNotFound(err) {
+		return nil, fmt.Errorf("capability discovery: %w", err)
+	}
+	capabilities.Version = version
+
+	resources, err := c.rootResources(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("capability discovery: %w", err)
+	}
+	// A root document is only authoritative if it lists a known feature;
+	// other links, such as a self link, say nothing about the resources
+	for _, feature := range rootFeatures[c.serviceType] {
+		if resources[string(feature)] {
+			capabilities.Features = append(capabilities.Features, feature)
+		}
+	}
+	if len(capabilities.Features) == 0 {
+		capabilities.Features = append(capabilities.Features, baseFeatures[c.serviceType]...)
+	}
+
+	if auth, ok := c.authenticator.(*Authenticator); ok {
+		for _, feature := range []Feature{FeatureMigration, FeatureCloudWatcher} {
+			if _, err := auth.GetEndpoint(catalogFeatures[feature]); err == nil {
+				capabilities.Features = append(capabilities.Features, feature)
+			}
+		}
+	}
+
+	c.capabilities.mutex.Lock()
+	c.capabilities.capabilities = capabilities
+	c.capabilities.mutex.Unlock()
+
+	return capabilities, nil
+}
+
+// rootResources returns the resources listed with links in the service root
+// document; none if the server has no root document
+func (c *BaseClient) rootResources(ctx context.Context) (map[string]bool, error) {
+	resp, err := c.WithoutCache().DoRequestWithContext(ctx, http.MethodGet, "/", nil)
+	if IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var root map[string]json.RawMessage
+	if err := c.ParseResponse(resp, &root); err != nil {
+		return nil, err
+	}
+
+	resources := make(map[string]bool)
+	for key, value := range root {
+		var links []Link
+		if json.Unmarshal(value, &links) == nil && len(links) > 0 {
+			resources[key] = true
+		}
+	}
+	return resources, nil
+}
+
+// RequireFeature fails with a NotSupportedError if the deployment does not
+// offer the feature. Capabilities are discovered on first use and cached,
+// so request methods can call it first to fail fast. If discovery fails,
+// e.g. with a 401 or 5xx on the root document, the feature is assumed and
+// discovery is tried again on the next call, leaving the request itself to
+// report the problem.
+func (c *BaseClient) RequireFeature(ctx context.Context, operation string, feature Feature) error {
+	c.capabilities.mutex.Lock()
+	capabilities := c.capabilities.capabilities
+	c.capabilities.mutex.Unlock()
+
+	if capabilities == nil {
+		var err error
+		if capabilities, err = c.discoverCapabilities(ctx); err != nil {
+			return nil
+		}
+	}
+
+	if !capabilities.Supports(feature) {
+		return &NotSupportedError{Operation: operation, Feature: feature}
+	}
+	return nil
+}
@@ -2,6 +2,9 @@ package common
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -14,11 +17,19 @@ import (
 
 // BaseClient represents the base HTTP client for Safir services
 type BaseClient struct {
-	endpoint      string
-	authenticator interface{ GetToken() (string, error) }
-	httpClient    *http.Client
-	apiVersion    string
-	serviceType   ServiceType
+	endpoint           string
+	authenticator      interface{ GetToken() (string, error) }
+	httpClient         *http.Client
+	apiVersion         string
+	serviceType        ServiceType
+	rateLimiter        *RateLimiter
+	concurrencyLimiter *ConcurrencyLimiter
+	rateLimitRetries   int
+	cache              *ResponseCache
+	version            *versionState
+	capabilities       *capabilityState
+	maxResponseSize    int64
+	compression        CompressionConfig
 }
 
 // BaseClientConfig holds base client configuration
@@ -28,6 +39,35 @@ type BaseClientConfig struct {
 	ServiceType   ServiceType
 	APIVersion    string
 	Timeout       time.Duration
+
+	// RateLimiter throttles outgoing requests (optional, may be shared)
+	RateLimiter *RateLimiter
+	// ConcurrencyLimiter bounds the requests in flight (optional, may be shared)
+	ConcurrencyLimiter *ConcurrencyLimiter
+	// RateLimitRetries is how many times a request answered with 429 is
+	// retried after honoring its Retry-After header
+	RateLimitRetries int
+
+	// Cache serves repeated GET requests from memory (optional, may be shared)
+	Cache *ResponseCache
+
+	// MaxMicroversion is the highest microversion the client implements.
+	// When above BaseMicroversion, the highest version both sides implement
+	// is negotiated before the first request; otherwise no version is
+	// negotiated.
+	MaxMicroversion Microversion
+	// Microversion pins the microversion instead of negotiating it
+	Microversion Microversion
+
+	// MaxResponseSize fails responses with larger bodies with a
+	// ResponseTooLargeError (0 for no limit)
+	MaxResponseSize int64
+
+	// Compression configures compressed responses and request bodies
+	Compression CompressionConfig
+	// Transport performs the requests (optional, defaults to
+	// http.DefaultTransport)
+	Transport http.RoundTripper
 }
 
 // NewBaseClient creates a new base client
@@ -47,15 +87,101 @@ func NewBaseClient(config BaseClientConfig) *BaseClient {
 		endpoint:      fullEndpoint,
 		authenticator: config.Authenticator,
 		httpClient: &http.Client{
-			Timeout: config.Timeout,
+			Timeout:   config.Timeout,
+			Transport: config.Transport,
 		},
-		apiVersion:  config.APIVersion,
-		serviceType: config.ServiceType,
+		apiVersion:         config.APIVersion,
+		serviceType:        config.ServiceType,
+		rateLimiter:        config.RateLimiter,
+		concurrencyLimiter: config.ConcurrencyLimiter,
+		rateLimitRetries:   config.RateLimitRetries,
+		cache:              config.Cache,
+		version:            newVersionState(config.MaxMicroversion, config.Microversion),
+		capabilities:       &capabilityState{},
+		maxResponseSize:    config.MaxResponseSize,
+		compression:        config.Compression,
 	}
 }
 
 // DoRequest performs an HTTP request with automatic token handling
 func (c *BaseClient) DoRequest(method, path string, body interface{}) (*http.Response, error) {
+	return c.DoRequestWithContext(context.Background(), method, path, body)
+}
+
+// DoRequestWithContext performs an HTTP request bound to the given context.
+// Configured rate and concurrency limits are applied, and 429 responses are
+// retried up to RateLimitRetries times after their Retry-After delay.
+func (c *BaseClient) DoRequestWithContext(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
+	return c.DoRequestWithHeaders(ctx, method, path, body, nil)
+}
+
+// DoRequestWithHeaders is DoRequestWithContext with additional request
+// headers, e.g. If-Match for conditional updates. With a cache configured,
+// GET requests are served through it and successful writes invalidate it.
+func (c *BaseClient) DoRequestWithHeaders(ctx context.Context, method, path string, body interface{}, header http.Header) (*http.Response, error) {
+	if err := c.negotiateVersion(ctx); err != nil {
+		return nil, err
+	}
+
+	if c.cache == nil {
+		return c.doWithRetries(ctx, method, path, body, header)
+	}
+
+	if method == http.MethodGet {
+		return c.cache.cachedGet(c.cacheScope(), path, header, func(header http.Header) (*http.Response, error) {
+			return c.doWithRetries(ctx, method, path, body, header)
+		})
+	}
+
+	resp, err := c.doWithRetries(ctx, method, path, body, header)
+	if err == nil {
+		c.cache.invalidate(method, path)
+	}
+	return resp, err
+}
+
+// doWithRetries performs a request, retrying 429 responses
+func (c *BaseClient) doWithRetries(ctx context.Context, method, path string, body interface{}, header http.Header) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := c.doRequest(ctx, method, path, body, header)
+		if !IsTooManyRequests(err) {
+			return resp, err
+		}
+
+		delay := err.(*APIError).RetryAfter
+		if delay <= 0 {
+			delay = DefaultRetryAfter
+		}
+
+		// Make every request sharing the limiter back off, not only this one
+		if c.rateLimiter != nil {
+			c.rateLimiter.Pause(delay)
+		}
+
+		if attempt >= c.rateLimitRetries {
+			return nil, err
+		}
+
+		if c.rateLimiter == nil {
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+		}
+	}
+}
+
+// doRequest performs a single HTTP request, re-authenticating once on 401
+func (c *BaseClient) doRequest(ctx context.Context, method, path string, body interface{}, header http.Header) (*http.Response, error) {
+	if c.rateLimiter != nil {
+		if err := c.rateLimiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("rate limiter: %w", err)
+		}
+	}
+
 	// Get current valid token
 	token, err := c.authenticator.GetToken()
 	if err != nil {
@@ -63,18 +189,23 @@ func (c *BaseClient) DoRequest(method, path string, body interface{}) (*http.Res
 	}
 
 	var bodyReader io.Reader
+	var contentEncoding string
 	if body != nil {
 		jsonData, err := json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
+		jsonData, contentEncoding, err = c.compression.encodeRequest(jsonData)
+		if err != nil {
+			return nil, err
+		}
 		bodyReader = bytes.NewBuffer(jsonData)
 	}
 
 	// Build full URL
 	url := c.endpoint + path
 
-	req, err := http.NewRequest(method, url, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -83,13 +214,44 @@ func (c *BaseClient) DoRequest(method, path string, body interface{}) (*http.Res
 	req.Header.Set("X-Auth-Token", token)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept-Encoding", c.compression.acceptEncoding())
 	req.Header.Set("User-Agent", "golang-safirclient/1.0")
+	if contentEncoding != "" {
+		req.Header.Set("Content-Encoding", contentEncoding)
+	}
+	if c.version != nil {
+		if version := c.version.header(c.serviceType); version != "" {
+			req.Header.Set(APIVersionHeader, version)
+		}
+	}
+	for key, values := range header {
+		req.Header[http.CanonicalHeaderKey(key)] = values
+	}
+
+	if c.concurrencyLimiter != nil {
+		if err := c.concurrencyLimiter.Acquire(ctx); err != nil {
+			return nil, fmt.Errorf("concurrency limiter: %w", err)
+		}
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		if c.concurrencyLimiter != nil {
+			c.concurrencyLimiter.Release()
+		}
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
+	// Hold the slot until the caller is done with the body
+	if c.concurrencyLimiter != nil {
+		resp.Body = &releaseOnClose{body: resp.Body, release: c.concurrencyLimiter.Release}
+	}
+
+	if err := decodeResponse(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
 	// Handle authentication errors
 	if resp.StatusCode == http.StatusUnauthorized {
 		// Try to re-authenticate if using full authenticator
@@ -99,42 +261,49 @@ func (c *BaseClient) DoRequest(method, path string, body interface{}) (*http.Res
 				return nil, &AuthError{Message: fmt.Sprintf("re-authentication failed: %v", err)}
 			}
 			// Retry the request with new token
-			return c.DoRequest(method, path, body)
+			return c.doRequest(ctx, method, path, body, header)
 		}
 		defer resp.Body.Close()
 		return nil, &AuthError{Message: "authentication failed: token expired or invalid"}
 	}
 
+	// Answer to If-None-Match, handled by the cache
+	if resp.StatusCode == http.StatusNotModified {
+		return resp, nil
+	}
+
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		defer resp.Body.Close()
-		bodyBytes, _ := io.ReadAll(resp.Body)
+		bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, MaxErrorBodySize))
 		return nil, &APIError{
 			StatusCode: resp.StatusCode,
 			Message:    string(bodyBytes),
 			URL:        req.URL.String(),
 			Method:     method,
+			RetryAfter: ParseRetryAfter(resp.Header.Get("Retry-After")),
 		}
 	}
 
-	return resp, nil
+	return c.limitResponse(resp)
 }
 
-// ParseResponse parses JSON response into the provided interface
+// ParseResponse parses JSON response into the provided interface. The body
+// is decoded as it is read; errors quote at most MaxErrorBodySize bytes of
+// it. Use DecodeList to process large lists item by item.
 func (c *BaseClient) ParseResponse(resp *http.Response, v interface{}) error {
 	defer resp.Body.Close()
 
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
+	body := &excerptReader{r: resp.Body}
+	decoder := json.NewDecoder(body)
+	if err := decoder.Decode(v); err != nil {
+		if err == io.EOF {
+			// Empty response is valid for some operations (like DELETE)
+			return nil
+		}
+		return body.parseError(err)
 	}
-
-	if len(bodyBytes) == 0 {
-		// Empty response is valid for some operations (like DELETE)
-		return nil
-	}
-
-	if err := json.Unmarshal(bodyBytes, v); err != nil {
-		return fmt.Errorf("failed to parse response: %w (body: %s)", err, string(bodyBytes))
+	if decoder.More() {
+		return body.parseError(errTrailingData)
 	}
 
 	return nil
@@ -160,43 +329,84 @@ func (c *BaseClient) GetAPIVersion() string {
 	return c.apiVersion
 }
 
-// GetServiceType returns the service type
-func (c *BaseClient) GetServiceType() ServiceType {
-	return c.serviceType
+// SetRateLimiter sets the rate limiter used by this client (nil disables it)
+func (c *BaseClient) SetRateLimiter(limiter *RateLimiter) {
+	c.rateLimiter = limiter
 }
 
-// Ping checks if the service API is accessible
-func (c *BaseClient) Ping() error {
-	resp, err := c.DoRequest(http.MethodGet, "/", nil)
-	if err != nil {
-		return fmt.Errorf("ping failed: %w", err)
-	}
-	resp.Body.Close()
-	return nil
+// SetConcurrencyLimiter sets the concurrency limiter used by this client (nil disables it)
+func (c *BaseClient) SetConcurrencyLimiter(limiter *ConcurrencyLimiter) {
+	c.concurrencyLimiter = limiter
 }
 
-// GetVersion returns the API version information
-func (c *BaseClient) GetVersion() (map[string]interface{}, error) {
-	// Remove /v1 from endpoint for version query
-	baseEndpoint := strings.TrimSuffix(c.endpoint, "/"+c.apiVersion)
+// SetRateLimitRetries sets how many times a 429 response is retried
+func (c *BaseClient) SetRateLimitRetries(retries int) {
+	c.rateLimitRetries = retries
+}
 
-	req, err := http.NewRequest(http.MethodGet, baseEndpoint+"/", nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+// SetMaxResponseSize sets the maximum size of response bodies (0 for no limit)
+func (c *BaseClient) SetMaxResponseSize(size int64) {
+	c.maxResponseSize = size
+}
+
+// SetCompression sets how responses and request bodies are compressed
+func (c *BaseClient) SetCompression(compression CompressionConfig) {
+	c.compression = compression
+}
+
+// SetCache sets the response cache used by this client (nil disables it).
+// Cached responses are read in full before they are decoded, so lists are no
+// longer streamed, see DecodeList.
+func (c *BaseClient) SetCache(cache *ResponseCache) {
+	c.cache = cache
+}
+
+// WithoutCache returns a copy of the client that bypasses the response cache
+func (c *BaseClient) WithoutCache() *BaseClient {
+	if c.cache == nil {
+		return c
 	}
+	uncached := *c
+	uncached.cache = nil
+	return &uncached
+}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+// cacheScope identifies whose view of the API a cached response is: the
+// endpoint together with the Keystone user and project, or with a hash of
+// the token when the client was given one
+func (c *BaseClient) cacheScope() string {
+	if auth, ok := c.authenticator.(interface{ GetAuthInfo() AuthInfo }); ok {
+		info := auth.GetAuthInfo()
+		return strings.Join([]string{c.endpoint, info.UserID, info.Username, info.DomainID, info.DomainName, info.ProjectID, info.ProjectName}, "\x00")
 	}
-	defer resp.Body.Close()
 
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	token, _ := c.authenticator.GetToken()
+	sum := sha256.Sum256([]byte(token))
+	return c.endpoint + "\x00" + hex.EncodeToString(sum[:])
+}
+
+// CacheStats returns the counters of the response cache, if any
+func (c *BaseClient) CacheStats() CacheStats {
+	if c.cache == nil {
+		return CacheStats{}
 	}
+	return c.cache.Stats()
+}
 
-	return result, nil
+// GetServiceType returns the service type
+func (c *BaseClient) GetServiceType() ServiceType {
+	return c.serviceType
+}
+
+// Ping checks if the service API is accessible. It always asks the server,
+// bypassing the response cache.
+func (c *BaseClient) Ping() error {
+	resp, err := c.WithoutCache().DoRequest(http.MethodGet, "/", nil)
+	if err != nil {
+		return fmt.Errorf("ping failed: %w", err)
+	}
+	resp.Body.Close()
+	return nil
 }
 
 // BuildAuthOptions builds AuthOptions from ClientOptions
@@ -0,0 +1,125 @@
+package common
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultMinCompressedRequestSize is the smallest request body compressed
+// when CompressionConfig.GzipRequests is set
+const DefaultMinCompressedRequestSize = 1024
+
+// CompressionConfig configures compressed transfers. Responses are
+// requested with gzip or deflate compression and decoded by the client, so
+// that this works with any transport.
+type CompressionConfig struct {
+	// DisableResponses asks for uncompressed responses
+	DisableResponses bool
+	// GzipRequests compresses request bodies of at least MinRequestSize
+	// bytes; the server must accept Content-Encoding: gzip
+	GzipRequests bool
+	// MinRequestSize defaults to DefaultMinCompressedRequestSize
+	MinRequestSize int
+}
+
+// acceptEncoding is the Accept-Encoding header of requests
+func (c CompressionConfig) acceptEncoding() string {
+	if c.DisableResponses {
+		return "identity"
+	}
+	return "gzip, deflate"
+}
+
+// encodeRequest compresses a request body if configured, returning the
+// body to send and its Content-Encoding, "" for none
+func (c CompressionConfig) encodeRequest(body []byte) ([]byte, string, error) {
+	minSize := c.MinRequestSize
+	if minSize <= 0 {
+		minSize = DefaultMinCompressedRequestSize
+	}
+	if !c.GzipRequests || len(body) < minSize {
+		return body, "", nil
+	}
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(body); err != nil {
+		return nil, "", fmt.Errorf("failed to compress request body: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to compress request body: %w", err)
+	}
+	return buf.Bytes(), "gzip", nil
+}
+
+// decodeResponse replaces the body of a compressed response with its
+// decompressed content
+func decodeResponse(resp *http.Response) error {
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	switch encoding {
+	case "", "identity":
+		return nil
+	case "gzip", "x-gzip", "deflate":
+	default:
+		return fmt.Errorf("unsupported response Content-Encoding %q", encoding)
+	}
+
+	resp.Body = &decompressedBody{body: resp.Body, encoding: encoding}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return nil
+}
+
+// decompressedBody decompresses a response body. The decompressor is
+// created on the first read, as empty bodies have no compression header.
+type decompressedBody struct {
+	body     io.ReadCloser
+	encoding string
+	reader   io.Reader
+}
+
+func (b *decompressedBody) Read(p []byte) (int, error) {
+	if b.reader == nil {
+		reader, err := b.newReader()
+		if err != nil {
+			return 0, err
+		}
+		b.reader = reader
+	}
+	return b.reader.Read(p)
+}
+
+func (b *decompressedBody) newReader() (io.Reader, error) {
+	buffered := bufio.NewReader(b.body)
+	if _, err := buffered.Peek(1); err != nil {
+		return nil, err
+	}
+
+	if b.encoding != "deflate" {
+		return gzip.NewReader(buffered)
+	}
+
+	// Deflate is meant to be zlib-wrapped, but some servers send raw
+	// deflate data
+	header, err := buffered.Peek(2)
+	if err == nil && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(buffered)
+	}
+	return flate.NewReader(buffered), nil
+}
+
+func (b *decompressedBody) Close() error {
+	if closer, ok := b.reader.(io.Closer); ok {
+		closer.Close()
+	}
+	return b.body.Close()
+}
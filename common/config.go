@@ -0,0 +1,87 @@
+package common
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack/config/clouds"
+)
+
+// AuthOptionsFromEnv builds AuthOptions from the OS_* environment variables
+// used by python-openstackclient (for example after sourcing an openrc file)
+func AuthOptionsFromEnv() (*AuthOptions, error) {
+	opts := &AuthOptions{
+		IdentityEndpoint:            GetEnvOrDefault("OS_AUTH_URL", ""),
+		Username:                    GetEnvOrDefault("OS_USERNAME", ""),
+		UserID:                      GetEnvOrDefault("OS_USER_ID", GetEnvOrDefault("OS_USERID", "")),
+		Password:                    GetEnvOrDefault("OS_PASSWORD", ""),
+		TokenID:                     GetEnvOrDefault("OS_TOKEN", ""),
+		DomainID:                    GetEnvOrDefault("OS_USER_DOMAIN_ID", GetEnvOrDefault("OS_DOMAIN_ID", "")),
+		DomainName:                  GetEnvOrDefault("OS_USER_DOMAIN_NAME", GetEnvOrDefault("OS_DOMAIN_NAME", "")),
+		ApplicationCredentialID:     GetEnvOrDefault("OS_APPLICATION_CREDENTIAL_ID", ""),
+		ApplicationCredentialName:   GetEnvOrDefault("OS_APPLICATION_CREDENTIAL_NAME", ""),
+		ApplicationCredentialSecret: GetEnvOrDefault("OS_APPLICATION_CREDENTIAL_SECRET", ""),
+	}
+
+	// A bare token cannot be used to obtain a new one
+	opts.AllowReauth = opts.TokenID == ""
+
+	projectID := GetEnvOrDefault("OS_PROJECT_ID", GetEnvOrDefault("OS_TENANT_ID", ""))
+	projectName := GetEnvOrDefault("OS_PROJECT_NAME", GetEnvOrDefault("OS_TENANT_NAME", ""))
+
+	// Application credentials carry their own scope
+	hasAppCred := opts.ApplicationCredentialID != "" || opts.ApplicationCredentialName != ""
+	if !hasAppCred && (projectID != "" || projectName != "") {
+		opts.Scope = &gophercloud.AuthScope{
+			ProjectID:   projectID,
+			ProjectName: projectName,
+		}
+
+		// A project name is only unique within its domain
+		if projectID == "" {
+			opts.Scope.DomainID = GetEnvOrDefault("OS_PROJECT_DOMAIN_ID", opts.DomainID)
+			opts.Scope.DomainName = GetEnvOrDefault("OS_PROJECT_DOMAIN_NAME", "")
+			if opts.Scope.DomainID == "" && opts.Scope.DomainName == "" {
+				opts.Scope.DomainName = opts.DomainName
+			}
+		}
+	}
+
+	if err := ValidateAuthOptions(opts); err != nil {
+		return nil, err
+	}
+
+	return opts, nil
+}
+
+// AuthOptionsFromCloud builds AuthOptions from the named entry of a
+// clouds.yaml file, searched in the standard locations
+func AuthOptionsFromCloud(cloudName string) (*AuthOptions, error) {
+	ao, _, _, err := clouds.Parse(clouds.WithCloudName(cloudName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cloud %q: %w", cloudName, err)
+	}
+
+	opts := &AuthOptions{
+		IdentityEndpoint:            ao.IdentityEndpoint,
+		Username:                    ao.Username,
+		UserID:                      ao.UserID,
+		Password:                    ao.Password,
+		TokenID:                     ao.TokenID,
+		DomainID:                    ao.DomainID,
+		DomainName:                  ao.DomainName,
+		TenantID:                    ao.TenantID,
+		TenantName:                  ao.TenantName,
+		ApplicationCredentialID:     ao.ApplicationCredentialID,
+		ApplicationCredentialName:   ao.ApplicationCredentialName,
+		ApplicationCredentialSecret: ao.ApplicationCredentialSecret,
+		Scope:                       ao.Scope,
+		AllowReauth:                 ao.TokenID == "",
+	}
+
+	if err := ValidateAuthOptions(opts); err != nil {
+		return nil, err
+	}
+
+	return opts, nil
+}
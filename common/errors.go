@@ -1,6 +1,10 @@
 package common
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // APIError represents an API error response
 type APIError struct {
@@ -8,6 +12,7 @@ type APIError struct {
 	Message    string
 	URL        string
 	Method     string
+	RetryAfter time.Duration
 }
 
 func (e *APIError) Error() string {
@@ -15,18 +20,32 @@ func (e *APIError) Error() string {
 		e.Method, e.URL, e.StatusCode, e.Message)
 }
 
-// IsNotFound checks if the error is a 404 Not Found error
+// IsNotFound checks if the error is a 404 Not Found error or a failed lookup
+// by name
 func IsNotFound(err error) bool {
-	if apiErr, ok := err.(*APIError); ok {
-		return apiErr.StatusCode == 404
+	switch e := err.(type) {
+	case *APIError:
+		return e.StatusCode == 404
+	case *NotFoundError:
+		return true
 	}
 	return false
 }
 
-// IsConflict checks if the error is a 409 Conflict error
+// IsAmbiguous checks if the error is an AmbiguousError
+func IsAmbiguous(err error) bool {
+	_, ok := err.(*AmbiguousError)
+	return ok
+}
+
+// IsConflict checks if the error is a 409 Conflict error, a 412
+// Precondition Failed answer to a conditional request or a ConflictError
 func IsConflict(err error) bool {
-	if apiErr, ok := err.(*APIError); ok {
-		return apiErr.StatusCode == 409
+	switch e := err.(type) {
+	case *APIError:
+		return e.StatusCode == 409 || e.StatusCode == 412
+	case *ConflictError:
+		return true
 	}
 	return false
 }
@@ -63,6 +82,51 @@ func IsServerError(err error) bool {
 	return false
 }
 
+// IsTooManyRequests checks if the error is a 429 Too Many Requests error
+func IsTooManyRequests(err error) bool {
+	if apiErr, ok := err.(*APIError); ok {
+		return apiErr.StatusCode == 429
+	}
+	return false
+}
+
+// ConflictError reports that a resource was modified by someone else since
+// it was read, detected by comparing its last update time
+type ConflictError struct {
+	Resource          string
+	ID                string
+	ExpectedUpdatedAt string
+	ActualUpdatedAt   string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("conflict: %s %s was modified concurrently (read at version %q, now %q)",
+		e.Resource, e.ID, e.ExpectedUpdatedAt, e.ActualUpdatedAt)
+}
+
+// NotFoundError reports that no resource has the requested name
+type NotFoundError struct {
+	Resource string
+	Name     string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("no %s with a name or ID of '%s' exists", e.Resource, e.Name)
+}
+
+// AmbiguousError reports that several resources have the requested name
+type AmbiguousError struct {
+	Resource string
+	Name     string
+	// Candidates are the IDs of the matching resources
+	Candidates []string
+}
+
+func (e *AmbiguousError) Error() string {
+	return fmt.Sprintf("more than one %s exists with the name '%s': %s",
+		e.Resource, e.Name, strings.Join(e.Candidates, ", "))
+}
+
 // AuthError represents an authentication error
 type AuthError struct {
 	Message string
@@ -81,3 +145,52 @@ type ValidationError struct {
 func (e *ValidationError) Error() string {
 	return fmt.Sprintf("validation error for field '%s': %s", e.Field, e.Message)
 }
+
+// ValidationErrors collects the validation errors of several fields
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	switch len(e) {
+	case 0:
+		return "validation error"
+	case 1:
+		return e[0].Error()
+	}
+
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fmt.Sprintf("'%s' %s", fieldErr.Field, fieldErr.Message)
+	}
+	return fmt.Sprintf("validation errors for %d fields: %s", len(e), strings.Join(messages, "; "))
+}
+
+// Unwrap exposes the individual field errors to errors.As
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, fieldErr := range e {
+		errs[i] = fieldErr
+	}
+	return errs
+}
+
+// Add records an error for a field
+func (e *ValidationErrors) Add(field, message string) {
+	*e = append(*e, &ValidationError{Field: field, Message: message})
+}
+
+// Err returns the collected errors, or nil if there are none
+func (e ValidationErrors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// IsValidationError checks if the error is a ValidationError or ValidationErrors
+func IsValidationError(err error) bool {
+	switch err.(type) {
+	case *ValidationError, ValidationErrors:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,375 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/openstack"
+	"github.com/gophercloud/gophercloud/v2/openstack/identity/v3/tokens"
+)
+
+// HealthStatus is the outcome of a health check
+type HealthStatus string
+
+const (
+	HealthStatusOK HealthStatus = "ok"
+	// HealthStatusDegraded is usable but needs attention, e.g. a slow
+	// endpoint or a certificate about to expire
+	HealthStatusDegraded HealthStatus = "degraded"
+	HealthStatusFailed   HealthStatus = "failed"
+	// HealthStatusSkipped is for checks that do not apply, e.g. an optional
+	// service missing from the catalog
+	HealthStatusSkipped HealthStatus = "skipped"
+)
+
+// severity orders the statuses for the overall status of a report
+func (s HealthStatus) severity() int {
+	switch s {
+	case HealthStatusFailed:
+		return 2
+	case HealthStatusDegraded:
+		return 1
+	}
+	return 0
+}
+
+// Health check defaults
+const (
+	DefaultHealthTimeout      = 5 * time.Second
+	DefaultSlowResponse       = 2 * time.Second
+	DefaultCertificateWarning = 14 * 24 * time.Hour
+	DefaultMaxClockSkew       = time.Minute
+)
+
+// HealthCheckOptions tunes HealthCheckWithOptions; zero values select the
+// defaults
+type HealthCheckOptions struct {
+	// Timeout bounds each probe
+	Timeout time.Duration
+	// SlowResponse is the latency above which an endpoint is degraded
+	SlowResponse time.Duration
+	// CertificateWarning degrades endpoints whose TLS certificate expires
+	// within this duration
+	CertificateWarning time.Duration
+	// MaxClockSkew degrades the token check when the local clock is further
+	// off Keystone's
+	MaxClockSkew time.Duration
+	// RequiredServices fail the check when they are missing from the
+	// catalog; other missing services are skipped. Defaults to the
+	// optimization service.
+	RequiredServices []ServiceType
+	// HTTPClient performs the endpoint probes, e.g. to trust a private CA;
+	// defaults to a client with Timeout. BaseClient.HealthCheckWithOptions
+	// defaults to the client's own HTTP client and Transport instead.
+	HTTPClient *http.Client
+}
+
+func (o HealthCheckOptions) withDefaults() HealthCheckOptions {
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultHealthTimeout
+	}
+	if o.SlowResponse <= 0 {
+		o.SlowResponse = DefaultSlowResponse
+	}
+	if o.CertificateWarning <= 0 {
+		o.CertificateWarning = DefaultCertificateWarning
+	}
+	if o.MaxClockSkew <= 0 {
+		o.MaxClockSkew = DefaultMaxClockSkew
+	}
+	if o.RequiredServices == nil {
+		o.RequiredServices = []ServiceType{ServiceTypeOptimization}
+	}
+	if o.HTTPClient == nil {
+		o.HTTPClient = &http.Client{Timeout: o.Timeout}
+	}
+	return o
+}
+
+func (o HealthCheckOptions) required(service ServiceType) bool {
+	for _, s := range o.RequiredServices {
+		if s == service {
+			return true
+		}
+	}
+	return false
+}
+
+// HealthReport is the result of a health check, e.g. for a readiness probe
+type HealthReport struct {
+	Status    HealthStatus     `json:"status"`
+	CheckedAt time.Time        `json:"checked_at"`
+	Token     TokenHealth      `json:"token"`
+	Endpoints []EndpointHealth `json:"endpoints"`
+}
+
+// TokenHealth is the state of the Keystone token
+type TokenHealth struct {
+	Status    HealthStatus `json:"status"`
+	Message   string       `json:"message,omitempty"`
+	IssuedAt  *time.Time   `json:"issued_at,omitempty"`
+	ExpiresAt *time.Time   `json:"expires_at,omitempty"`
+	// ClockSkew is how far the local clock is ahead of Keystone's, negative
+	// if it is behind, measured against the token issue time
+	ClockSkew time.Duration `json:"clock_skew"`
+}
+
+// EndpointHealth is the state of one service endpoint
+type EndpointHealth struct {
+	Service ServiceType  `json:"service"`
+	Status  HealthStatus `json:"status"`
+	Message string       `json:"message,omitempty"`
+	// URL is the endpoint from the catalog, empty if it is missing there
+	URL        string        `json:"url,omitempty"`
+	Latency    time.Duration `json:"latency,omitempty"`
+	StatusCode int           `json:"status_code,omitempty"`
+	// CertificateExpiry is when the TLS certificate of the endpoint expires
+	CertificateExpiry *time.Time `json:"certificate_expiry,omitempty"`
+}
+
+// Healthy reports whether no check failed; degraded checks count as healthy
+func (r *HealthReport) Healthy() bool {
+	return r.Status != HealthStatusFailed
+}
+
+// Err returns an error listing the failed checks, or nil if the report is
+// healthy. It fits readiness checkers such as controller-runtime's
+// healthz.Checker.
+func (r *HealthReport) Err() error {
+	if r.Healthy() {
+		return nil
+	}
+
+	var failures []string
+	if r.Token.Status == HealthStatusFailed {
+		failures = append(failures, "token: "+r.Token.Message)
+	}
+	for _, e := range r.Endpoints {
+		if e.Status == HealthStatusFailed {
+			failures = append(failures, e.Service.String()+": "+e.Message)
+		}
+	}
+	return fmt.Errorf("health check failed: %s", strings.Join(failures, "; "))
+}
+
+// summarize sets the overall status to the worst of the checks
+func (r *HealthReport) summarize() {
+	r.Status = HealthStatusOK
+	statuses := []HealthStatus{r.Token.Status}
+	for _, e := range r.Endpoints {
+		statuses = append(statuses, e.Status)
+	}
+	for _, s := range statuses {
+		if s.severity() > r.Status.severity() {
+			r.Status = s
+		}
+	}
+}
+
+// healthServices are the endpoints a full health check probes
+var healthServices = []ServiceType{
+	ServiceTypeIdentity,
+	ServiceTypeOptimization,
+	ServiceTypeMigration,
+	ServiceTypeCloudWatcher,
+}
+
+// HealthCheck checks the Keystone token and clock skew, and the catalog
+// presence, reachability, latency and TLS certificate of Keystone and every
+// Safir service, with the default options
+func (a *Authenticator) HealthCheck(ctx context.Context) *HealthReport {
+	return a.HealthCheckWithOptions(ctx, HealthCheckOptions{})
+}
+
+// HealthCheckWithOptions is HealthCheck with tuned thresholds
+func (a *Authenticator) HealthCheckWithOptions(ctx context.Context, opts HealthCheckOptions) *HealthReport {
+	opts = opts.withDefaults()
+	report := &HealthReport{CheckedAt: time.Now()}
+
+	token, err := a.GetToken()
+	if err != nil {
+		report.Token = TokenHealth{Status: HealthStatusFailed, Message: err.Error()}
+	} else {
+		report.Token = a.checkToken(ctx, token, opts)
+	}
+
+	a.mutex.RLock()
+	endpoints := map[ServiceType]string{ServiceTypeIdentity: NormalizeEndpoint(a.authOptions.IdentityEndpoint)}
+	for service, endpoint := range a.endpoints {
+		endpoints[service] = endpoint
+	}
+	a.mutex.RUnlock()
+
+	report.Endpoints = make([]EndpointHealth, len(healthServices))
+	var wg sync.WaitGroup
+	for i, service := range healthServices {
+		endpoint, ok := endpoints[service]
+		if !ok {
+			report.Endpoints[i] = missingEndpoint(service, opts)
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Endpoints[i] = probeEndpoint(ctx, service, endpoint, token, opts)
+		}()
+	}
+	wg.Wait()
+
+	report.summarize()
+	return report
+}
+
+// checkToken validates the token with Keystone and measures the clock skew
+func (a *Authenticator) checkToken(ctx context.Context, token string, opts HealthCheckOptions) TokenHealth {
+	a.mutex.RLock()
+	provider := a.provider
+	started, finished := a.authStarted, a.authFinished
+	ownToken := a.authOptions.TokenID == ""
+	a.mutex.RUnlock()
+
+	identity, err := openstack.NewIdentityV3(provider, gophercloud.EndpointOpts{})
+	if err != nil {
+		return TokenHealth{Status: HealthStatusFailed, Message: fmt.Sprintf("identity client: %v", err)}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	var details struct {
+		IssuedAt  time.Time `json:"issued_at"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := tokens.Get(ctx, identity, token).ExtractIntoStructPtr(&details, "token"); err != nil {
+		return TokenHealth{Status: HealthStatusFailed, Message: fmt.Sprintf("token rejected by Keystone: %v", err)}
+	}
+
+	health := TokenHealth{Status: HealthStatusOK, IssuedAt: &details.IssuedAt, ExpiresAt: &details.ExpiresAt}
+	if !details.ExpiresAt.After(time.Now()) {
+		health.Status = HealthStatusFailed
+		health.Message = "token expired at " + details.ExpiresAt.Format(time.RFC3339)
+		return health
+	}
+
+	// The token was issued between started and finished by the local
+	// clock; a token passed in the auth options was issued earlier
+	if ownToken && !started.IsZero() {
+		switch {
+		case details.IssuedAt.Before(started):
+			health.ClockSkew = started.Sub(details.IssuedAt)
+		case details.IssuedAt.After(finished):
+			health.ClockSkew = finished.Sub(details.IssuedAt)
+		}
+	}
+	if health.ClockSkew > opts.MaxClockSkew || -health.ClockSkew > opts.MaxClockSkew {
+		health.Status = HealthStatusDegraded
+		health.Message = fmt.Sprintf("local clock is %s off Keystone's", health.ClockSkew.Round(time.Second))
+	}
+
+	return health
+}
+
+// missingEndpoint reports a service that is not in the catalog
+func missingEndpoint(service ServiceType, opts HealthCheckOptions) EndpointHealth {
+	health := EndpointHealth{Service: service, Status: HealthStatusSkipped, Message: "not in the service catalog"}
+	if opts.required(service) {
+		health.Status = HealthStatusFailed
+	}
+	return health
+}
+
+// probeEndpoint GETs an endpoint; any answer but a server error shows that
+// the service is reachable
+func probeEndpoint(ctx context.Context, service ServiceType, endpoint, token string, opts HealthCheckOptions) EndpointHealth {
+	health := EndpointHealth{Service: service, URL: endpoint}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"/", nil)
+	if err != nil {
+		health.Status = HealthStatusFailed
+		health.Message = fmt.Sprintf("invalid endpoint: %v", err)
+		return health
+	}
+	if token != "" {
+		req.Header.Set("X-Auth-Token", token)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "golang-safirclient/1.0")
+
+	start := time.Now()
+	resp, err := opts.HTTPClient.Do(req)
+	health.Latency = time.Since(start)
+	if err != nil {
+		health.Status = HealthStatusFailed
+		health.Message = fmt.Sprintf("unreachable: %v", err)
+		return health
+	}
+	resp.Body.Close()
+
+	health.StatusCode = resp.StatusCode
+	health.Status = HealthStatusOK
+	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+		expiry := resp.TLS.PeerCertificates[0].NotAfter
+		health.CertificateExpiry = &expiry
+	}
+
+	switch {
+	case resp.StatusCode >= 500:
+		health.Status = HealthStatusFailed
+		health.Message = fmt.Sprintf("server error %d", resp.StatusCode)
+	case health.CertificateExpiry != nil && time.Until(*health.CertificateExpiry) < opts.CertificateWarning:
+		health.Status = HealthStatusDegraded
+		health.Message = "TLS certificate expires at " + health.CertificateExpiry.Format(time.RFC3339)
+	case health.Latency > opts.SlowResponse:
+		health.Status = HealthStatusDegraded
+		health.Message = fmt.Sprintf("slow response after %s", health.Latency.Round(time.Millisecond))
+	}
+
+	return health
+}
+
+// HealthCheck checks Keystone and every Safir endpoint if the client
+// authenticates with an Authenticator. With a bare token there is neither
+// Keystone nor a catalog to check, and only the client's own endpoint is
+// probed.
+func (c *BaseClient) HealthCheck(ctx context.Context) *HealthReport {
+	return c.HealthCheckWithOptions(ctx, HealthCheckOptions{})
+}
+
+// HealthCheckWithOptions is HealthCheck with tuned thresholds. Endpoints
+// are probed with the client's HTTP client, bounded by the probe timeout,
+// unless opts.HTTPClient is set.
+func (c *BaseClient) HealthCheckWithOptions(ctx context.Context, opts HealthCheckOptions) *HealthReport {
+	if opts.HTTPClient == nil {
+		probe := *c.httpClient
+		probe.Timeout = opts.withDefaults().Timeout
+		opts.HTTPClient = &probe
+	}
+
+	if auth, ok := c.authenticator.(*Authenticator); ok {
+		return auth.HealthCheckWithOptions(ctx, opts)
+	}
+
+	opts = opts.withDefaults()
+	report := &HealthReport{
+		CheckedAt: time.Now(),
+		Token:     TokenHealth{Status: HealthStatusSkipped, Message: "static token, not validated"},
+	}
+
+	token, err := c.authenticator.GetToken()
+	if err != nil {
+		report.Token = TokenHealth{Status: HealthStatusFailed, Message: err.Error()}
+	}
+	report.Endpoints = []EndpointHealth{probeEndpoint(ctx, c.serviceType, c.versionRoot().endpoint, token, opts)}
+
+	report.summarize()
+	return report
+}
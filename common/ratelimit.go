@@ -0,0 +1,180 @@
+package common
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultRetryAfter is used when a 429 response has no usable Retry-After header
+const DefaultRetryAfter = 1 * time.Second
+
+// RateLimiter is a token bucket rate limiter. A single limiter can be shared
+// by several clients to enforce a common request budget.
+type RateLimiter struct {
+	mutex        sync.Mutex
+	rate         float64
+	burst        float64
+	tokens       float64
+	last         time.Time
+	blockedUntil time.Time
+}
+
+// NewRateLimiter creates a rate limiter allowing requestsPerSecond on average
+// with bursts of up to burst requests
+func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a request may be sent or the context is done
+func (r *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		delay := r.reserve()
+		if delay == 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve takes a token if one is available, otherwise it returns how long
+// the caller should wait before trying again
+func (r *RateLimiter) reserve() time.Duration {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := time.Now()
+	if now.Before(r.blockedUntil) {
+		return r.blockedUntil.Sub(now)
+	}
+
+	if r.rate > 0 {
+		r.tokens += now.Sub(r.last).Seconds() * r.rate
+		if r.tokens > r.burst {
+			r.tokens = r.burst
+		}
+	}
+	r.last = now
+
+	if r.tokens >= 1 {
+		r.tokens--
+		return 0
+	}
+
+	if r.rate <= 0 {
+		// A zero rate only allows the initial burst; poll for Pause/Reset
+		return time.Second
+	}
+
+	return time.Duration((1 - r.tokens) / r.rate * float64(time.Second))
+}
+
+// Pause blocks all callers for the given duration, e.g. after the server
+// answered with 429 and a Retry-After header
+func (r *RateLimiter) Pause(d time.Duration) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	until := time.Now().Add(d)
+	if until.After(r.blockedUntil) {
+		r.blockedUntil = until
+	}
+	r.tokens = 0
+}
+
+// ConcurrencyLimiter limits the number of requests in flight. A single
+// limiter can be shared by several clients.
+type ConcurrencyLimiter struct {
+	slots chan struct{}
+}
+
+// NewConcurrencyLimiter creates a limiter allowing at most maxInFlight
+// concurrent requests
+func NewConcurrencyLimiter(maxInFlight int) *ConcurrencyLimiter {
+	if maxInFlight < 1 {
+		maxInFlight = 1
+	}
+
+	return &ConcurrencyLimiter{
+		slots: make(chan struct{}, maxInFlight),
+	}
+}
+
+// Acquire blocks until a slot is free or the context is done
+func (l *ConcurrencyLimiter) Acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release frees a slot taken by Acquire
+func (l *ConcurrencyLimiter) Release() {
+	<-l.slots
+}
+
+// InFlight returns the number of slots currently in use
+func (l *ConcurrencyLimiter) InFlight() int {
+	return len(l.slots)
+}
+
+// ParseRetryAfter parses a Retry-After header value given either in seconds
+// or as an HTTP date. It returns zero if the value is missing or invalid.
+func ParseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
+
+// releaseOnClose releases a concurrency slot once the response body is closed
+type releaseOnClose struct {
+	body    io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (r *releaseOnClose) Read(p []byte) (int, error) {
+	return r.body.Read(p)
+}
+
+func (r *releaseOnClose) Close() error {
+	err := r.body.Close()
+	r.once.Do(r.release)
+	return err
+}
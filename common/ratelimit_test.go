@@ -0,0 +1,121 @@
+package common
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterBurstAndRate(t *testing.T) {
+	limiter := NewRateLimiter(20, 3)
+	ctx := context.Background()
+
+	started := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(ctx); err != nil {
+			t.Fatalf("Wait: %v", err)
+		}
+	}
+	if elapsed := time.Since(started); elapsed > 20*time.Millisecond {
+		t.Errorf("burst of 3 took %s, want no wait", elapsed)
+	}
+
+	// The bucket is empty, the next tokens come at 20 per second
+	started = time.Now()
+	for i := 0; i < 2; i++ {
+		if err := limiter.Wait(ctx); err != nil {
+			t.Fatalf("Wait: %v", err)
+		}
+	}
+	if elapsed := time.Since(started); elapsed < 80*time.Millisecond {
+		t.Errorf("2 requests over the burst took %s, want about 100ms", elapsed)
+	}
+}
+
+func TestRateLimiterPause(t *testing.T) {
+	limiter := NewRateLimiter(1000, 10)
+	limiter.Pause(50 * time.Millisecond)
+
+	started := time.Now()
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	if elapsed := time.Since(started); elapsed < 50*time.Millisecond {
+		t.Errorf("Wait after Pause returned after %s, want at least 50ms", elapsed)
+	}
+}
+
+func TestRateLimiterContext(t *testing.T) {
+	limiter := NewRateLimiter(0, 1)
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+
+	// A zero rate only allows the initial burst
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Wait on an empty bucket = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestConcurrencyLimiter(t *testing.T) {
+	limiter := NewConcurrencyLimiter(2)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := limiter.Acquire(ctx); err != nil {
+			t.Fatalf("Acquire: %v", err)
+		}
+	}
+	if limiter.InFlight() != 2 {
+		t.Errorf("InFlight = %d, want 2", limiter.InFlight())
+	}
+
+	timeout, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.Acquire(timeout); err != context.DeadlineExceeded {
+		t.Errorf("Acquire over the limit = %v, want context.DeadlineExceeded", err)
+	}
+
+	acquired := make(chan error)
+	go func() {
+		acquired <- limiter.Acquire(ctx)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("Acquire over the limit did not block")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	limiter.Release()
+	if err := <-acquired; err != nil {
+		t.Errorf("Acquire after Release: %v", err)
+	}
+	if limiter.InFlight() != 2 {
+		t.Errorf("InFlight = %d, want 2", limiter.InFlight())
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		min   time.Duration
+		max   time.Duration
+	}{
+		{"", 0, 0},
+		{"garbage", 0, 0},
+		{"-5", 0, 0},
+		{"3", 3 * time.Second, 3 * time.Second},
+		{time.Now().Add(time.Minute).UTC().Format(http.TimeFormat), 58 * time.Second, time.Minute},
+		{time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), 0, 0},
+	}
+
+	for _, test := range tests {
+		if got := ParseRetryAfter(test.value); got < test.min || got > test.max {
+			t.Errorf("ParseRetryAfter(%q) = %s, want between %s and %s", test.value, got, test.min, test.max)
+		}
+	}
+}
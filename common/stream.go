@@ -0,0 +1,156 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// MaxErrorBodySize caps how much of a response body is quoted in errors
+const MaxErrorBodySize = 1024
+
+// ResponseTooLargeError reports a response body over the maximum size set
+// with BaseClientConfig.MaxResponseSize
+type ResponseTooLargeError struct {
+	Limit int64
+}
+
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("response body exceeds the maximum size of %d bytes", e.Limit)
+}
+
+// IsResponseTooLarge checks if the error is a ResponseTooLargeError
+func IsResponseTooLarge(err error) bool {
+	var tooLarge *ResponseTooLargeError
+	return errors.As(err, &tooLarge)
+}
+
+// limitedBody fails reads once more than limit bytes were read
+type limitedBody struct {
+	body      io.ReadCloser
+	limit     int64
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, &ResponseTooLargeError{Limit: b.limit}
+	}
+
+	// Read one byte more than allowed to tell a body of exactly limit bytes
+	// from a larger one
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.body.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n, &ResponseTooLargeError{Limit: b.limit}
+	}
+	return n, err
+}
+
+func (b *limitedBody) Close() error {
+	return b.body.Close()
+}
+
+// limitResponse enforces the maximum response size on a successful response
+func (c *BaseClient) limitResponse(resp *http.Response) (*http.Response, error) {
+	if c.maxResponseSize <= 0 {
+		return resp, nil
+	}
+
+	if resp.ContentLength > c.maxResponseSize {
+		resp.Body.Close()
+		return nil, &ResponseTooLargeError{Limit: c.maxResponseSize}
+	}
+
+	resp.Body = &limitedBody{body: resp.Body, limit: c.maxResponseSize, remaining: c.maxResponseSize}
+	return resp, nil
+}
+
+// excerptReader keeps the start of what it reads, to quote in errors
+type excerptReader struct {
+	r         io.Reader
+	excerpt   []byte
+	truncated bool
+}
+
+func (e *excerptReader) Read(p []byte) (int, error) {
+	n, err := e.r.Read(p)
+	if room := MaxErrorBodySize - len(e.excerpt); room > 0 {
+		e.excerpt = append(e.excerpt, p[:min(n, room)]...)
+		e.truncated = e.truncated || n > room
+	} else if n > 0 {
+		e.truncated = true
+	}
+	return n, err
+}
+
+// parseError reports a malformed response, quoting at most
+// MaxErrorBodySize bytes of it
+func (e *excerptReader) parseError(err error) error {
+	if IsResponseTooLarge(err) {
+		return err
+	}
+
+	// Quote what follows the point where decoding stopped as well
+	io.CopyN(io.Discard, e, int64(MaxErrorBodySize-len(e.excerpt)+1))
+
+	excerpt := string(e.excerpt)
+	if e.truncated {
+		excerpt += "... (truncated)"
+	}
+	return fmt.Errorf("failed to parse response: %w (body: %s)", err, excerpt)
+}
+
+// errTrailingData reports data after the JSON value of a response
+var errTrailingData = errors.New("unexpected data after the JSON value")
+
+// DecodeList decodes a response holding a JSON array one item at a time,
+// calling fn for each, so that neither the body nor the whole list has to
+// be held in memory. This holds only for responses read from the server: a
+// client with a response cache reads a response in full to store it before
+// it is decoded. An empty or null body is an empty list. An error returned
+// by fn stops decoding and is returned as is.
+func DecodeList[T any](resp *http.Response, fn func(T) error) error {
+	defer resp.Body.Close()
+
+	body := &excerptReader{r: resp.Body}
+	decoder := json.NewDecoder(body)
+
+	token, err := decoder.Token()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return body.parseError(err)
+	}
+	if token == nil {
+		return nil
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return body.parseError(fmt.Errorf("expected a JSON array, got %v", token))
+	}
+
+	for decoder.More() {
+		var item T
+		if err := decoder.Decode(&item); err != nil {
+			return body.parseError(err)
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+
+	// The closing bracket
+	if _, err := decoder.Token(); err != nil {
+		return body.parseError(err)
+	}
+	if decoder.More() {
+		return body.parseError(errTrailingData)
+	}
+	return nil
+}
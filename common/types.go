@@ -71,6 +71,9 @@ const (
 	ServiceTypeOptimization ServiceType = "safiroptimization"
 	ServiceTypeMigration    ServiceType = "migration"
 	ServiceTypeCloudWatcher ServiceType = "cloud_watcher"
+
+	// ServiceTypeIdentity is Keystone
+	ServiceTypeIdentity ServiceType = "identity"
 )
 
 // String returns the string representation of ServiceType
@@ -3,6 +3,7 @@ package common
 import (
 	"fmt"
 	"net/url"
+	"os"
 	"strings"
 )
 
@@ -68,6 +69,12 @@ func ValidateAuthOptions(opts *AuthOptions) error {
 	return nil
 }
 
+// Ptr returns a pointer to v, for setting the optional fields of update
+// requests
+func Ptr[T any](v T) *T {
+	return &v
+}
+
 // NormalizeEndpoint normalizes an endpoint URL by removing trailing slashes
 func NormalizeEndpoint(endpoint string) string {
 	return strings.TrimRight(endpoint, "/")
@@ -84,7 +91,9 @@ func BuildEndpointURL(baseEndpoint, version string) string {
 
 // GetEnvOrDefault returns environment variable value or default
 func GetEnvOrDefault(key, defaultValue string) string {
-	// This is a placeholder - in real implementation you'd use os.Getenv
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
 	return defaultValue
 }
 
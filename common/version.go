@@ -0,0 +1,308 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// APIVersionHeader carries the microversion a request is made with
+const APIVersionHeader = "OpenStack-API-Version"
+
+// Version statuses reported by the version discovery document
+const (
+	VersionStatusCurrent    = "CURRENT"
+	VersionStatusSupported  = "SUPPORTED"
+	VersionStatusDeprecated = "DEPRECATED"
+)
+
+// Microversion is a version within a major API version, e.g. 1.2 of v1
+type Microversion struct {
+	Major int
+	Minor int
+}
+
+// BaseMicroversion is what servers that do not advertise microversions
+// implement
+var BaseMicroversion = Microversion{Major: 1, Minor: 0}
+
+// ParseMicroversion parses a version of the form "1.2"
+func ParseMicroversion(s string) (Microversion, error) {
+	major, minor, ok := strings.Cut(s, ".")
+	if !ok {
+		return Microversion{}, fmt.Errorf("invalid microversion %q: expected <major>.<minor>", s)
+	}
+
+	var v Microversion
+	var err error
+	if v.Major, err = strconv.Atoi(major); err != nil || v.Major < 0 {
+		return Microversion{}, fmt.Errorf("invalid microversion %q: bad major version", s)
+	}
+	if v.Minor, err = strconv.Atoi(minor); err != nil || v.Minor < 0 {
+		return Microversion{}, fmt.Errorf("invalid microversion %q: bad minor version", s)
+	}
+	return v, nil
+}
+
+// String returns the version as "<major>.<minor>", or "" for the zero value
+func (v Microversion) String() string {
+	if v.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// IsZero reports whether the version is unset
+func (v Microversion) IsZero() bool {
+	return v == Microversion{}
+}
+
+// LessThan reports whether v is older than other
+func (v Microversion) LessThan(other Microversion) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	return v.Minor < other.Minor
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (v Microversion) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler; an empty string is the
+// zero value
+func (v *Microversion) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*v = Microversion{}
+		return nil
+	}
+
+	parsed, err := ParseMicroversion(string(text))
+	if err != nil {
+		return err
+	}
+	*v = parsed
+	return nil
+}
+
+// VersionInfo describes one major version of an API
+type VersionInfo struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	// MinVersion and MaxVersion bound the microversions the server
+	// implements; both are zero if it does not advertise microversions
+	MinVersion Microversion `json:"min_version"`
+	MaxVersion Microversion `json:"max_version"`
+	Updated    string       `json:"updated,omitempty"`
+	Links      []Link       `json:"links,omitempty"`
+}
+
+// HasMicroversions reports whether the server advertises microversions for
+// this version
+func (i *VersionInfo) HasMicroversions() bool {
+	return !i.MaxVersion.IsZero()
+}
+
+// Supports reports whether the server implements microversion v
+func (i *VersionInfo) Supports(v Microversion) bool {
+	if !i.HasMicroversions() {
+		return v == BaseMicroversion
+	}
+	return !v.LessThan(i.MinVersion) && !i.MaxVersion.LessThan(v)
+}
+
+// UnsupportedVersionError reports that an operation needs a newer
+// microversion than the one negotiated with the server
+type UnsupportedVersionError struct {
+	Operation string
+	Required  Microversion
+	Available Microversion
+}
+
+func (e *UnsupportedVersionError) Error() string {
+	return fmt.Sprintf("%s requires API version %s or later, but only %s is available",
+		e.Operation, e.Required, e.Available)
+}
+
+// IsUnsupportedVersion checks if the error is an UnsupportedVersionError
+func IsUnsupportedVersion(err error) bool {
+	_, ok := err.(*UnsupportedVersionError)
+	return ok
+}
+
+// versionState is the microversion of a client, shared by its copies so that
+// negotiation happens once
+type versionState struct {
+	mutex sync.Mutex
+	// max is the highest microversion the client implements
+	max Microversion
+	// current is the negotiated or pinned version, valid once settled
+	current Microversion
+	// send is whether current goes out in the APIVersionHeader; servers
+	// without microversions do not get it
+	send    bool
+	settled bool
+}
+
+// newVersionState returns nil, disabling negotiation, if no version is
+// pinned and the client implements nothing above BaseMicroversion, which
+// every server implements
+func newVersionState(max, pinned Microversion) *versionState {
+	if pinned.IsZero() && !BaseMicroversion.LessThan(max) {
+		return nil
+	}
+
+	state := &versionState{max: max}
+	if !pinned.IsZero() {
+		state.current = pinned
+		state.send = true
+		state.settled = true
+	}
+	return state
+}
+
+// header returns the value of the APIVersionHeader, "" for none
+func (s *versionState) header(serviceType ServiceType) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.settled || !s.send {
+		return ""
+	}
+	return serviceType.String() + " " + s.current.String()
+}
+
+// versionRoot returns a copy of the client addressing the version discovery
+// document, which lives above the versioned endpoint
+func (c *BaseClient) versionRoot() *BaseClient {
+	root := *c
+	root.endpoint = strings.TrimSuffix(c.endpoint, "/"+c.apiVersion)
+	root.cache = nil
+	root.version = nil
+	return &root
+}
+
+// ListVersions returns the major API versions the server offers
+func (c *BaseClient) ListVersions() ([]VersionInfo, error) {
+	return c.listVersions(context.Background())
+}
+
+func (c *BaseClient) listVersions(ctx context.Context) ([]VersionInfo, error) {
+	root := c.versionRoot()
+	resp, err := root.doWithRetries(ctx, http.MethodGet, "/", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Versions []VersionInfo `json:"versions"`
+	}
+	if err := root.ParseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Versions, nil
+}
+
+// GetVersion returns the server's description of the API version the client
+// talks, including the range of microversions it implements
+func (c *BaseClient) GetVersion() (*VersionInfo, error) {
+	return c.getVersion(context.Background())
+}
+
+func (c *BaseClient) getVersion(ctx context.Context) (*VersionInfo, error) {
+	versions, err := c.listVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range versions {
+		if versions[i].ID == c.apiVersion {
+			return &versions[i], nil
+		}
+	}
+
+	return nil, &NotFoundError{Resource: "API version", Name: c.apiVersion}
+}
+
+// Microversion returns the microversion requests are made with, negotiating
+// it first if needed. It is BaseMicroversion for servers without
+// microversions and the zero value if negotiation is disabled.
+func (c *BaseClient) Microversion(ctx context.Context) (Microversion, error) {
+	if c.version == nil {
+		return Microversion{}, nil
+	}
+	if err := c.negotiateVersion(ctx); err != nil {
+		return Microversion{}, err
+	}
+
+	c.version.mutex.Lock()
+	defer c.version.mutex.Unlock()
+	return c.version.current, nil
+}
+
+// RequireVersion fails with an UnsupportedVersionError if the negotiated
+// microversion is older than min. Request methods call it first, so that an
+// older server yields a clear error rather than a 404.
+func (c *BaseClient) RequireVersion(ctx context.Context, operation string, min Microversion) error {
+	current, err := c.Microversion(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Without negotiation the server version is unknown; let it decide
+	if current.IsZero() || !current.LessThan(min) {
+		return nil
+	}
+
+	return &UnsupportedVersionError{Operation: operation, Required: min, Available: current}
+}
+
+// negotiateVersion selects the highest microversion both the client and the
+// server implement, once. Servers that do not advertise microversions, or
+// have no discovery document, are talked to at BaseMicroversion without the
+// APIVersionHeader. Other discovery failures are returned and negotiation is
+// tried again with the next request.
+func (c *BaseClient) negotiateVersion(ctx context.Context) error {
+	state := c.version
+	if state == nil {
+		return nil
+	}
+
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	if state.settled {
+		return nil
+	}
+
+	info, err := c.getVersion(ctx)
+	if err != nil && !IsNotFound(err) {
+		return fmt.Errorf("API version negotiation: %w", err)
+	}
+
+	if err != nil || !info.HasMicroversions() {
+		state.current = BaseMicroversion
+		state.send = false
+		state.settled = true
+		return nil
+	}
+
+	current := state.max
+	if info.MaxVersion.LessThan(current) {
+		current = info.MaxVersion
+	}
+	if current.LessThan(info.MinVersion) {
+		return fmt.Errorf("API version negotiation: server implements %s to %s, client at most %s",
+			info.MinVersion, info.MaxVersion, state.max)
+	}
+
+	state.current = current
+	state.send = true
+	state.settled = true
+	return nil
+}
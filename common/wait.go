@@ -0,0 +1,160 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Default wait settings
+const (
+	DefaultWaitInterval   = 2 * time.Second
+	DefaultWaitMultiplier = 2.0
+)
+
+// WaitOptions controls how WaitForWithOptions polls
+type WaitOptions struct {
+	// Interval is the delay before the second check (default 2s)
+	Interval time.Duration
+	// MaxInterval enables exponential backoff: the delay grows by
+	// Multiplier after every check up to MaxInterval. Zero keeps the
+	// delay constant.
+	MaxInterval time.Duration
+	// Multiplier is the backoff growth factor (default 2)
+	Multiplier float64
+	// Timeout bounds the whole wait in addition to the context (optional)
+	Timeout time.Duration
+	// Progress, if set, is called after every check that was not done
+	Progress func(WaitProgress)
+}
+
+// WaitProgress describes a pending wait
+type WaitProgress struct {
+	Attempt   int
+	Elapsed   time.Duration
+	NextDelay time.Duration
+}
+
+// WaitTimeoutError reports that a wait ran out of time. It unwraps to the
+// context error.
+type WaitTimeoutError struct {
+	Attempts int
+	Elapsed  time.Duration
+	Err      error
+}
+
+func (e *WaitTimeoutError) Error() string {
+	return fmt.Sprintf("timed out after %s and %d checks: %v", e.Elapsed.Round(time.Millisecond), e.Attempts, e.Err)
+}
+
+func (e *WaitTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// WaitFor calls condition every interval until it reports done, returns an
+// error or the context ends
+func WaitFor(ctx context.Context, interval time.Duration, condition func() (bool, error)) error {
+	return WaitForWithOptions(ctx, WaitOptions{Interval: interval}, condition)
+}
+
+// WaitForWithOptions calls condition until it reports done or returns an
+// error, backing off as configured. The first check happens immediately. A
+// deadline of the context or of opts.Timeout ends the wait with a
+// WaitTimeoutError; a cancelled context with its error.
+func WaitForWithOptions(ctx context.Context, opts WaitOptions, condition func() (bool, error)) error {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultWaitInterval
+	}
+	if opts.Multiplier <= 1 {
+		opts.Multiplier = DefaultWaitMultiplier
+	}
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	delay := opts.Interval
+	for attempt := 1; ; attempt++ {
+		if ctx.Err() != nil {
+			return waitEnded(ctx, attempt-1, start)
+		}
+
+		done, err := condition()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		if opts.Progress != nil {
+			opts.Progress(WaitProgress{Attempt: attempt, Elapsed: time.Since(start), NextDelay: delay})
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return waitEnded(ctx, attempt, start)
+		case <-timer.C:
+		}
+
+		if opts.MaxInterval > delay {
+			delay = time.Duration(float64(delay) * opts.Multiplier)
+			if delay > opts.MaxInterval {
+				delay = opts.MaxInterval
+			}
+		}
+	}
+}
+
+// waitEnded reports why a wait stopped early: a WaitTimeoutError if a
+// deadline passed, the context error as is if it was cancelled
+func waitEnded(ctx context.Context, attempts int, start time.Time) error {
+	err := ctx.Err()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	return &WaitTimeoutError{Attempts: attempts, Elapsed: time.Since(start), Err: err}
+}
+
+// StatusError reports that a polled status reached a failure state
+type StatusError struct {
+	Status string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("reached failure status %q", e.Status)
+}
+
+// WaitForStatus polls getStatus until it returns one of targets and returns
+// that status. Reaching one of failures ends the wait with a StatusError.
+// It is meant for asynchronous jobs such as migrations.
+func WaitForStatus(ctx context.Context, opts WaitOptions, getStatus func() (string, error), targets, failures []string) (string, error) {
+	var status string
+	err := WaitForWithOptions(ctx, opts, func() (bool, error) {
+		var err error
+		status, err = getStatus()
+		if err != nil {
+			return false, err
+		}
+
+		for _, failure := range failures {
+			if status == failure {
+				return false, &StatusError{Status: status}
+			}
+		}
+		for _, target := range targets {
+			if status == target {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+
+	return status, err
+}
@@ -0,0 +1,122 @@
+package fakesafir
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// authRequest is the subset of a Keystone v3 token request the fake understands
+type authRequest struct {
+	Auth struct {
+		Identity struct {
+			Methods  []string `json:"methods"`
+			Password struct {
+				User struct {
+					Name     string `json:"name"`
+					Password string `json:"password"`
+				} `json:"user"`
+			} `json:"password"`
+			Token struct {
+				ID string `json:"id"`
+			} `json:"token"`
+		} `json:"identity"`
+	} `json:"auth"`
+}
+
+func (s *Server) registerIdentity(mux *http.ServeMux) {
+	mux.HandleFunc("POST /v3/auth/tokens", s.createToken)
+	mux.HandleFunc("GET /v3/auth/tokens", s.getToken)
+}
+
+// createToken authenticates with password or token and returns a scoped token
+func (s *Server) createToken(w http.ResponseWriter, r *http.Request) {
+	var req authRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "malformed token request")
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	identity := req.Auth.Identity
+	authenticated := false
+	for _, method := range identity.Methods {
+		switch method {
+		case "password":
+			authenticated = identity.Password.User.Name == Username && identity.Password.User.Password == Password
+		case "token":
+			authenticated = s.validToken(identity.Token.ID)
+		}
+	}
+
+	if !authenticated {
+		writeError(w, http.StatusUnauthorized, "The request you have made requires authentication.")
+		return
+	}
+
+	token := s.issueToken()
+	w.Header().Set("X-Subject-Token", token)
+	writeJSON(w, http.StatusCreated, s.tokenBody(s.tokens[token]))
+}
+
+// getToken validates the token given in X-Subject-Token
+func (s *Server) getToken(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.validToken(r.Header.Get("X-Auth-Token")) {
+		writeError(w, http.StatusUnauthorized, "The request you have made requires authentication.")
+		return
+	}
+
+	subject := r.Header.Get("X-Subject-Token")
+	if !s.validToken(subject) {
+		writeError(w, http.StatusNotFound, "Could not find token.")
+		return
+	}
+
+	w.Header().Set("X-Subject-Token", subject)
+	writeJSON(w, http.StatusOK, s.tokenBody(s.tokens[subject]))
+}
+
+// tokenBody builds the token document including the service catalog
+func (s *Server) tokenBody(expiresAt time.Time) map[string]interface{} {
+	domain := map[string]interface{}{"id": DomainID, "name": "Default"}
+
+	return map[string]interface{}{
+		"token": map[string]interface{}{
+			"methods":    []string{"password"},
+			"issued_at":  expiresAt.Add(-TokenLifetime).UTC().Format(time.RFC3339Nano),
+			"expires_at": expiresAt.UTC().Format(time.RFC3339Nano),
+			"user":       map[string]interface{}{"id": "fake-user-id", "name": Username, "domain": domain},
+			"project":    map[string]interface{}{"id": "fake-project-id", "name": ProjectName, "domain": domain},
+			"roles":      []interface{}{map[string]interface{}{"id": "fake-role-id", "name": "admin"}},
+			"catalog": []interface{}{
+				catalogEntry("identity", "keystone", s.IdentityEndpoint()),
+				catalogEntry("safiroptimization", "safir-optimization", s.OptimizationEndpoint()),
+			},
+		},
+	}
+}
+
+func catalogEntry(serviceType, name, url string) map[string]interface{} {
+	var endpoints []interface{}
+	for _, iface := range []string{"public", "internal", "admin"} {
+		endpoints = append(endpoints, map[string]interface{}{
+			"id":        serviceType + "-" + iface,
+			"interface": iface,
+			"region":    "RegionOne",
+			"region_id": "RegionOne",
+			"url":       url,
+		})
+	}
+
+	return map[string]interface{}{
+		"id":        serviceType,
+		"type":      serviceType,
+		"name":      name,
+		"endpoints": endpoints,
+	}
+}
@@ -0,0 +1,413 @@
+package fakesafir
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// collectionConfig describes one resource collection of the Optimization API
+type collectionConfig struct {
+	// name identifies the collection in Count
+	name string
+	// path is the URL segment of the collection
+	path string
+	// key wraps the resource in create and update responses
+	key string
+	// nested collections live under /clusters/{cluster_id}/
+	nested   bool
+	required []string
+}
+
+var collections = []collectionConfig{
+	{name: "clusters", path: "clusters", key: "cluster", required: []string{"name"}},
+	{name: "hosts", path: "hosts", key: "host", nested: true, required: []string{"hostname"}},
+	{name: "excluded_vms", path: "excluded-vms", key: "vm", nested: true, required: []string{"vm_name"}},
+	{name: "workload_balancing", path: "workload-balancing", key: "policy", required: []string{"cluster_id", "name"}},
+	{name: "workload_consolidation", path: "workload-consolidation", key: "policy", required: []string{"cluster_id", "name"}},
+	{name: "host_maintenance", path: "host-maintenance", key: "policy", required: []string{"cluster_id", "name"}},
+}
+
+// collection holds the stored resources of one collection
+type collection struct {
+	config   collectionConfig
+	items    map[string]map[string]interface{}
+	sequence map[string]int
+	versions map[string]int
+	disabled bool
+}
+
+// etag returns the entity tag of an item, changing with every update
+func (c *collection) etag(item map[string]interface{}) string {
+	id := item["id"].(string)
+	return fmt.Sprintf(`"%s-%d"`, id, c.versions[id])
+}
+
+// setETag must be called with the mutex held
+func (s *Server) setETag(w http.ResponseWriter, c *collection, item map[string]interface{}) {
+	if s.etags {
+		w.Header().Set("ETag", c.etag(item))
+	}
+}
+
+// sorted returns the items matching clusterID (any if empty) in creation order
+func (c *collection) sorted(clusterID string) []map[string]interface{} {
+	items := make([]map[string]interface{}, 0, len(c.items))
+	for _, item := range c.items {
+		if clusterID != "" && item["cluster_id"] != clusterID {
+			continue
+		}
+		items = append(items, item)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return c.sequence[items[i]["id"].(string)] < c.sequence[items[j]["id"].(string)]
+	})
+
+	return items
+}
+
+func (s *Server) registerOptimization(mux *http.ServeMux) {
+	base := OptimizationPath + "/api/v1"
+
+	mux.HandleFunc("GET "+OptimizationPath+"/api/{$}", s.versions)
+	mux.Handle("GET "+base+"/{$}", s.api(http.HandlerFunc(s.root)))
+
+	for _, config := range collections {
+		c := s.collections[config.name]
+
+		prefix := base + "/" + config.path
+		if config.nested {
+			prefix = base + "/clusters/{cluster_id}/" + config.path
+		}
+
+		mux.Handle("GET "+prefix, s.api(s.enabled(c, s.list(c))))
+		mux.Handle("POST "+prefix, s.api(s.enabled(c, s.create(c))))
+		mux.Handle("GET "+prefix+"/{id}", s.api(s.enabled(c, s.get(c))))
+		mux.Handle("PUT "+prefix+"/{id}", s.api(s.enabled(c, s.update(c))))
+		mux.Handle("DELETE "+prefix+"/{id}", s.api(s.enabled(c, s.delete(c))))
+	}
+}
+
+// api guards the handlers of the versioned API
+func (s *Server) api(next http.Handler) http.Handler {
+	return s.authenticated(s.versioned(next))
+}
+
+// authenticated rejects requests without a valid X-Auth-Token
+func (s *Server) authenticated(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		valid := s.validToken(r.Header.Get("X-Auth-Token"))
+		s.mutex.Unlock()
+
+		if !valid {
+			writeError(w, http.StatusUnauthorized, "The request you have made requires authentication.")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// enabled answers 404 Not Found for collections turned off with Disable
+func (s *Server) enabled(c *collection, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		disabled := c.disabled
+		s.mutex.Unlock()
+
+		if disabled {
+			writeError(w, http.StatusNotFound, "The resource could not be found.")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// versioned rejects requests for a microversion outside of the advertised
+// range with 406 Not Acceptable, like OpenStack services do
+func (s *Server) versioned(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		minVersion, maxVersion := s.minVersion, s.maxVersion
+		s.mutex.Unlock()
+
+		header := r.Header.Get("OpenStack-API-Version")
+		if header == "" || maxVersion == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		service, version, _ := strings.Cut(header, " ")
+		requested, ok := parseVersion(version)
+		if service != "safiroptimization" || !ok {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid API version header %q", header))
+			return
+		}
+
+		low, _ := parseVersion(minVersion)
+		high, _ := parseVersion(maxVersion)
+		if requested[0] != low[0] || requested[1] < low[1] || requested[1] > high[1] {
+			writeError(w, http.StatusNotAcceptable, fmt.Sprintf(
+				"Version %s is not supported by the API. Minimum is %s and maximum is %s.",
+				version, minVersion, maxVersion))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// parseVersion parses a microversion of the form "1.2"
+func parseVersion(version string) ([2]int, bool) {
+	var v [2]int
+	_, err := fmt.Sscanf(version, "%d.%d", &v[0], &v[1])
+	return v, err == nil
+}
+
+// versions lists the API versions
+func (s *Server) versions(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"versions": []interface{}{s.versionDocument()},
+	})
+}
+
+// root describes the v1 API and links its top-level collections
+func (s *Server) root(w http.ResponseWriter, r *http.Request) {
+	document := map[string]interface{}{
+		"version": s.versionDocument(),
+	}
+
+	s.mutex.Lock()
+	for _, c := range s.collections {
+		if c.config.nested || c.disabled {
+			continue
+		}
+		href := s.OptimizationEndpoint() + "/api/v1/" + c.config.path
+		document[c.config.path] = []interface{}{
+			map[string]interface{}{"href": href, "rel": "self"},
+		}
+	}
+	s.mutex.Unlock()
+
+	writeJSON(w, http.StatusOK, document)
+}
+
+func (s *Server) versionDocument() map[string]interface{} {
+	document := map[string]interface{}{
+		"id":     "v1",
+		"status": "CURRENT",
+		"links": []interface{}{
+			map[string]interface{}{"href": s.OptimizationEndpoint() + "/api/v1/", "rel": "self"},
+		},
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.maxVersion != "" {
+		document["min_version"] = s.minVersion
+		document["max_version"] = s.maxVersion
+	}
+	return document
+}
+
+// clusterFilter returns the cluster a request is scoped to, if any
+func clusterFilter(c *collection, r *http.Request) string {
+	if c.config.nested {
+		return r.PathValue("cluster_id")
+	}
+	return r.URL.Query().Get("cluster_id")
+}
+
+// lookup returns the item addressed by the request, checking its cluster for
+// nested collections
+func (s *Server) lookup(c *collection, r *http.Request) (map[string]interface{}, bool) {
+	item, ok := c.items[r.PathValue("id")]
+	if !ok {
+		return nil, false
+	}
+
+	if c.config.nested && item["cluster_id"] != r.PathValue("cluster_id") {
+		return nil, false
+	}
+
+	return item, true
+}
+
+// clusterExists must be called with the mutex held
+func (s *Server) clusterExists(id interface{}) bool {
+	clusterID, ok := id.(string)
+	if !ok {
+		return false
+	}
+	_, exists := s.collections["clusters"].items[clusterID]
+	return exists
+}
+
+func (s *Server) list(c *collection) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		if c.config.nested && !s.clusterExists(r.PathValue("cluster_id")) {
+			writeError(w, http.StatusNotFound, "cluster not found")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, c.sorted(clusterFilter(c, r)))
+	})
+}
+
+func (s *Server) get(c *collection) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		item, ok := s.lookup(c, r)
+		if !ok {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("%s not found", c.config.key))
+			return
+		}
+
+		if s.etags && r.Header.Get("If-None-Match") == c.etag(item) {
+			s.setETag(w, c, item)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		s.setETag(w, c, item)
+		writeJSON(w, http.StatusOK, item)
+	})
+}
+
+func (s *Server) create(c *collection) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var item map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&item); err != nil || item == nil {
+			writeError(w, http.StatusBadRequest, "malformed request body")
+			return
+		}
+
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		if c.config.nested {
+			item["cluster_id"] = r.PathValue("cluster_id")
+		}
+
+		for _, field := range c.config.required {
+			if value, ok := item[field].(string); !ok || value == "" {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("field '%s' is required", field))
+				return
+			}
+		}
+
+		if _, scoped := item["cluster_id"]; scoped && !s.clusterExists(item["cluster_id"]) {
+			writeError(w, http.StatusNotFound, "cluster not found")
+			return
+		}
+
+		id := newID()
+		item["id"] = id
+		item["created_at"] = timestamp()
+		delete(item, "updated_at")
+
+		c.items[id] = item
+		c.sequence[id] = s.nextSequence()
+
+		writeJSON(w, http.StatusCreated, map[string]interface{}{
+			"message":    fmt.Sprintf("%s created successfully", c.config.key),
+			"code":       http.StatusCreated,
+			"title":      "Created",
+			c.config.key: item,
+		})
+	})
+}
+
+func (s *Server) update(c *collection) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var changes map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&changes); err != nil || changes == nil {
+			writeError(w, http.StatusBadRequest, "malformed request body")
+			return
+		}
+
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		item, ok := s.lookup(c, r)
+		if !ok {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("%s not found", c.config.key))
+			return
+		}
+
+		if s.etags {
+			if match := r.Header.Get("If-Match"); match != "" && match != c.etag(item) {
+				writeError(w, http.StatusPreconditionFailed, "resource was modified")
+				return
+			}
+		}
+
+		if clusterID, moved := changes["cluster_id"]; moved && clusterID != item["cluster_id"] {
+			if c.config.nested || !s.clusterExists(clusterID) {
+				writeError(w, http.StatusBadRequest, "invalid cluster_id")
+				return
+			}
+		}
+
+		for field, value := range changes {
+			switch field {
+			case "id", "created_at", "updated_at":
+				continue
+			}
+			item[field] = value
+		}
+		item["updated_at"] = timestamp()
+		c.versions[item["id"].(string)]++
+
+		s.setETag(w, c, item)
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"message":    fmt.Sprintf("%s updated successfully", c.config.key),
+			"code":       http.StatusOK,
+			"title":      "Updated",
+			c.config.key: item,
+		})
+	})
+}
+
+func (s *Server) delete(c *collection) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		item, ok := s.lookup(c, r)
+		if !ok {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("%s not found", c.config.key))
+			return
+		}
+
+		id := item["id"].(string)
+		delete(c.items, id)
+		delete(c.sequence, id)
+		delete(c.versions, id)
+
+		// Deleting a cluster removes everything attached to it
+		if c.config.name == "clusters" {
+			for _, other := range s.collections {
+				for otherID, otherItem := range other.items {
+					if otherItem["cluster_id"] == id {
+						delete(other.items, otherID)
+						delete(other.sequence, otherID)
+						delete(other.versions, otherID)
+					}
+				}
+			}
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,214 @@
+// Package fakesafir provides an in-memory fake of Keystone and the Safir
+// Optimization API for tests.
+//
+//	srv := fakesafir.NewServer()
+//	defer srv.Close()
+//
+//	client, err := optimization.NewClient(optimization.ClientOptions{
+//		AuthURL:     srv.IdentityEndpoint(),
+//		Username:    fakesafir.Username,
+//		Password:    fakesafir.Password,
+//		ProjectName: fakesafir.ProjectName,
+//	})
+//
+// The fake implements token issuing and validation, a service catalog with
+// the identity and safiroptimization services, and CRUD for clusters, hosts,
+// excluded VMs and the three policy types.
+package fakesafir
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+)
+
+// Credentials accepted by the fake Keystone
+const (
+	Username    = "admin"
+	Password    = "secret"
+	ProjectName = "admin"
+	DomainID    = "default"
+)
+
+// TokenLifetime is the validity of the tokens issued by the fake Keystone
+const TokenLifetime = time.Hour
+
+// OptimizationPath is the path of the Safir Optimization endpoint in the catalog
+const OptimizationPath = "/safir-optimization"
+
+// Server is a fake Keystone and Safir Optimization server
+type Server struct {
+	*httptest.Server
+
+	mutex       sync.Mutex
+	tokens      map[string]time.Time
+	collections map[string]*collection
+	sequence    int
+	requests    int
+	etags       bool
+	compression bool
+	minVersion  string
+	maxVersion  string
+}
+
+// NewServer starts a new fake server
+func NewServer() *Server {
+	s := &Server{
+		tokens:      make(map[string]time.Time),
+		collections: make(map[string]*collection),
+	}
+
+	for _, c := range collections {
+		s.collections[c.name] = &collection{
+			config:   c,
+			items:    make(map[string]map[string]interface{}),
+			sequence: make(map[string]int),
+			versions: make(map[string]int),
+		}
+	}
+
+	mux := http.NewServeMux()
+	s.registerIdentity(mux)
+	s.registerOptimization(mux)
+
+	s.Server = httptest.NewServer(s.countRequests(s.compress(mux)))
+	return s
+}
+
+// IdentityEndpoint returns the Keystone v3 endpoint of the fake
+func (s *Server) IdentityEndpoint() string {
+	return s.URL + "/v3"
+}
+
+// OptimizationEndpoint returns the Safir Optimization endpoint of the fake,
+// as published in the service catalog
+func (s *Server) OptimizationEndpoint() string {
+	return s.URL + OptimizationPath
+}
+
+// IssueToken creates a valid token without going through authentication
+func (s *Server) IssueToken() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.issueToken()
+}
+
+// RevokeTokens invalidates every issued token
+func (s *Server) RevokeTokens() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.tokens = make(map[string]time.Time)
+}
+
+// EnableETags makes the fake send ETag headers, honor If-Match on updates
+// and answer If-None-Match with 304 Not Modified. The real Safir API does
+// none of this, so it is off by default.
+func (s *Server) EnableETags() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.etags = true
+}
+
+// EnableCompression makes the fake gzip its responses to requests that
+// accept it. Gzip request bodies are always accepted.
+func (s *Server) EnableCompression() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.compression = true
+}
+
+// SetMicroversions makes the fake advertise the microversions min to max of
+// the v1 API, e.g. "1.0" and "1.2", and reject requests for other versions
+// with 406 Not Acceptable. By default no microversions are advertised.
+func (s *Server) SetMicroversions(min, max string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.minVersion = min
+	s.maxVersion = max
+}
+
+// Disable turns a collection off, e.g. "host_maintenance", as on a
+// deployment without that feature: it is no longer linked from the API root
+// and its requests are answered with 404 Not Found
+func (s *Server) Disable(collectionName string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.collections[collectionName].disabled = true
+}
+
+// Requests returns the number of requests served so far
+func (s *Server) Requests() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.requests
+}
+
+// Count returns the number of stored resources of a collection, e.g.
+// "clusters" or "hosts"
+func (s *Server) Count(collectionName string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.collections[collectionName].items)
+}
+
+func (s *Server) countRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		s.requests++
+		s.mutex.Unlock()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// issueToken must be called with the mutex held
+func (s *Server) issueToken() string {
+	token := newID()
+	s.tokens[token] = time.Now().Add(TokenLifetime)
+	return token
+}
+
+// validToken must be called with the mutex held
+func (s *Server) validToken(token string) bool {
+	expiry, ok := s.tokens[token]
+	return ok && time.Now().Before(expiry)
+}
+
+// nextSequence must be called with the mutex held
+func (s *Server) nextSequence() int {
+	s.sequence++
+	return s.sequence
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	h := hex.EncodeToString(b)
+	return fmt.Sprintf("%s-%s-%s-%s-%s", h[0:8], h[8:12], h[12:16], h[16:20], h[20:32])
+}
+
+func timestamp() string {
+	return time.Now().UTC().Format("2006-01-02T15:04:05.000000")
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"message": message,
+		"code":    status,
+		"title":   http.StatusText(status),
+	})
+}
@@ -0,0 +1,158 @@
+//go:build !live
+
+package integration
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+// inFlightTransport records the largest number of concurrent POST requests
+type inFlightTransport struct {
+	mutex       sync.Mutex
+	inFlight    int
+	maxInFlight int
+}
+
+func (t *inFlightTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodPost {
+		return http.DefaultTransport.RoundTrip(req)
+	}
+
+	t.mutex.Lock()
+	t.inFlight++
+	t.maxInFlight = max(t.maxInFlight, t.inFlight)
+	t.mutex.Unlock()
+
+	defer func() {
+		t.mutex.Lock()
+		t.inFlight--
+		t.mutex.Unlock()
+	}()
+
+	time.Sleep(5 * time.Millisecond)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func newBulkTestClient(t *testing.T, transport http.RoundTripper) *optimization.Client {
+	t.Helper()
+
+	srv := newFakeServer(t)
+	auth, err := common.NewAuthenticator(fakeAuthOptions(srv))
+	if err != nil {
+		t.Fatalf("NewAuthenticator: %v", err)
+	}
+	client, err := optimization.NewClientWithAuthenticator(auth, optimization.ClientOptions{Transport: transport})
+	if err != nil {
+		t.Fatalf("NewClientWithAuthenticator: %v", err)
+	}
+	return client
+}
+
+func bulkHosts(n int) []optimization.ClusterHostCreate {
+	hosts := make([]optimization.ClusterHostCreate, n)
+	for i := range hosts {
+		hosts[i] = optimization.ClusterHostCreate{Hostname: fmt.Sprintf("compute-%02d", i), Enabled: true}
+	}
+	return hosts
+}
+
+func TestBulkCreateClusterHostsConcurrency(t *testing.T) {
+	transport := &inFlightTransport{}
+	client := newBulkTestClient(t, transport)
+	cluster := createTestCluster(t, client)
+
+	results, err := client.BulkCreateClusterHosts(cluster.ID, bulkHosts(12), optimization.BulkOptions{Concurrency: 3})
+	if err != nil {
+		t.Fatalf("BulkCreateClusterHosts: %v", err)
+	}
+
+	for i, result := range results {
+		if result.Index != i || result.Host == nil || result.Host.Hostname != result.Request.Hostname {
+			t.Errorf("result %d = %+v", i, result)
+		}
+	}
+	if transport.maxInFlight > 3 {
+		t.Errorf("%d creates in flight, want at most 3", transport.maxInFlight)
+	}
+
+	hosts, err := client.ListClusterHosts(cluster.ID)
+	if err != nil || len(hosts) != 12 {
+		t.Errorf("ListClusterHosts = %d hosts, %v", len(hosts), err)
+	}
+}
+
+func TestBulkCreateClusterHostsPartialFailure(t *testing.T) {
+	client := newBulkTestClient(t, nil)
+	cluster := createTestCluster(t, client)
+
+	hosts := bulkHosts(6)
+	hosts[2].Hostname = "not a hostname"
+	hosts[4].Hostname = ""
+
+	// Best effort: every other host is created
+	results, err := client.BulkCreateClusterHosts(cluster.ID, hosts, optimization.BulkOptions{})
+	var bulkErr *optimization.BulkError
+	if !errors.As(err, &bulkErr) || bulkErr.Total != 6 || bulkErr.Failed != 2 || bulkErr.Skipped != 0 {
+		t.Fatalf("BulkCreateClusterHosts = %v, want 2 of 6 failed", err)
+	}
+	for i, result := range results {
+		failed := i == 2 || i == 4
+		if failed != (result.Err != nil) || failed != (result.Host == nil) {
+			t.Errorf("result %d = %+v", i, result)
+		}
+		if failed && !common.IsValidationError(result.Err) {
+			t.Errorf("result %d error = %v, want a validation error", i, result.Err)
+		}
+	}
+	if left, _ := client.ListClusterHosts(cluster.ID); len(left) != 4 {
+		t.Errorf("%d hosts after a best-effort bulk create, want 4", len(left))
+	}
+}
+
+func TestBulkCreateClusterHostsStopAndRollback(t *testing.T) {
+	client := newBulkTestClient(t, nil)
+	cluster := createTestCluster(t, client)
+
+	hosts := bulkHosts(5)
+	hosts[0].Hostname = ""
+
+	// With a single worker, items are attempted in order, so stopping on
+	// the first error skips every later item
+	results, err := client.BulkCreateClusterHosts(cluster.ID, hosts, optimization.BulkOptions{
+		Concurrency: 1,
+		StopOnError: true,
+	})
+	var bulkErr *optimization.BulkError
+	if !errors.As(err, &bulkErr) || bulkErr.Failed != 1 || bulkErr.Skipped != 4 {
+		t.Fatalf("BulkCreateClusterHosts = %v, want 1 failed and 4 skipped", err)
+	}
+	for _, result := range results[1:] {
+		if !errors.Is(result.Err, optimization.ErrBulkSkipped) {
+			t.Errorf("result %d error = %v, want ErrBulkSkipped", result.Index, result.Err)
+		}
+	}
+
+	// Rollback deletes the hosts created before the failure
+	hosts = bulkHosts(5)
+	hosts[4].Hostname = ""
+	results, err = client.BulkCreateClusterHosts(cluster.ID, hosts, optimization.BulkOptions{Rollback: true})
+	if !errors.As(err, &bulkErr) || bulkErr.Failed != 1 || bulkErr.RolledBack != 4 || bulkErr.RollbackFailed != 0 {
+		t.Fatalf("BulkCreateClusterHosts = %v, want 4 rolled back", err)
+	}
+	for _, result := range results[:4] {
+		if !result.RolledBack {
+			t.Errorf("result %d = %+v, want rolled back", result.Index, result)
+		}
+	}
+	if left, _ := client.ListClusterHosts(cluster.ID); len(left) != 0 {
+		t.Errorf("%d hosts after a rolled back bulk create, want 0", len(left))
+	}
+}
@@ -0,0 +1,193 @@
+//go:build !live
+
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/fakesafir"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+// newCachedClient returns a client of srv that caches responses
+func newCachedClient(t *testing.T, srv *fakesafir.Server, config common.CacheConfig) *optimization.Client {
+	t.Helper()
+
+	auth, err := common.NewAuthenticator(fakeAuthOptions(srv))
+	if err != nil {
+		t.Fatalf("NewAuthenticator: %v", err)
+	}
+	client, err := optimization.NewClientWithAuthenticator(auth)
+	if err != nil {
+		t.Fatalf("NewClientWithAuthenticator: %v", err)
+	}
+	client.SetCache(common.NewResponseCache(config))
+
+	return client
+}
+
+func TestCacheServesRepeatedReads(t *testing.T) {
+	srv := newFakeServer(t)
+	client := newCachedClient(t, srv, common.CacheConfig{DefaultTTL: time.Minute})
+	cluster := createTestCluster(t, client)
+
+	if _, err := client.ListClusters(); err != nil {
+		t.Fatalf("ListClusters: %v", err)
+	}
+	before := srv.Requests()
+	clusters, err := client.ListClusters()
+	if err != nil {
+		t.Fatalf("ListClusters: %v", err)
+	}
+	if srv.Requests() != before {
+		t.Error("second ListClusters was not served from the cache")
+	}
+	if len(clusters) != 1 || clusters[0].ID != cluster.ID {
+		t.Errorf("cached ListClusters = %+v", clusters)
+	}
+
+	stats := client.CacheStats()
+	if stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("stats = %+v, want 1 hit and 1 miss", stats)
+	}
+
+	// A write through the same client invalidates the cached list
+	if _, err := client.UpdateCluster(cluster.ID, &optimization.ClusterUpdate{Description: common.Ptr("changed")}); err != nil {
+		t.Fatalf("UpdateCluster: %v", err)
+	}
+	clusters, err = client.ListClusters()
+	if err != nil {
+		t.Fatalf("ListClusters: %v", err)
+	}
+	if clusters[0].Description != "changed" {
+		t.Errorf("ListClusters after update returned a stale description %q", clusters[0].Description)
+	}
+	if stats := client.CacheStats(); stats.Invalidations == 0 {
+		t.Errorf("stats = %+v, want invalidations", stats)
+	}
+}
+
+func TestCacheRevalidatesWithETags(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.EnableETags()
+	client := newCachedClient(t, srv, common.CacheConfig{
+		DefaultTTL: time.Minute,
+		TTLs:       map[string]time.Duration{"clusters": time.Nanosecond},
+	})
+	cluster := createTestCluster(t, client)
+
+	for i := 0; i < 3; i++ {
+		got, err := client.GetCluster(cluster.ID)
+		if err != nil {
+			t.Fatalf("GetCluster: %v", err)
+		}
+		if got.ID != cluster.ID || got.ETag == "" {
+			t.Errorf("GetCluster = %+v, want the cluster with its ETag", got)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	stats := client.CacheStats()
+	if stats.Misses != 1 || stats.Revalidations != 2 {
+		t.Errorf("stats = %+v, want 1 miss and 2 revalidations", stats)
+	}
+}
+
+func TestCacheDisabledPerResource(t *testing.T) {
+	srv := newFakeServer(t)
+	client := newCachedClient(t, srv, common.CacheConfig{
+		TTLs: map[string]time.Duration{"hosts": 0},
+	})
+	cluster := createTestCluster(t, client)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.ListClusterHosts(cluster.ID); err != nil {
+			t.Fatalf("ListClusterHosts: %v", err)
+		}
+	}
+
+	if stats := client.CacheStats(); stats.Hits != 0 || stats.Entries != 0 {
+		t.Errorf("stats = %+v, hosts must not be cached", stats)
+	}
+}
+
+func TestCacheKeepsScopesApart(t *testing.T) {
+	srv := newFakeServer(t)
+	cache := common.NewResponseCache(common.CacheConfig{DefaultTTL: time.Minute})
+
+	var clients []*optimization.Client
+	for i := 0; i < 2; i++ {
+		client := optimization.NewClientWithToken(srv.OptimizationEndpoint(), srv.IssueToken(), optimization.ClientOptions{Cache: cache})
+		if _, err := client.ListClusters(); err != nil {
+			t.Fatalf("ListClusters: %v", err)
+		}
+		clients = append(clients, client)
+	}
+
+	// Each token has its own entry, which is then served to it
+	if stats := cache.Stats(); stats.Hits != 0 || stats.Misses != 2 || stats.Entries != 2 {
+		t.Errorf("stats = %+v, want 2 misses and 2 entries", stats)
+	}
+	if _, err := clients[0].ListClusters(); err != nil {
+		t.Fatalf("ListClusters: %v", err)
+	}
+	if stats := cache.Stats(); stats.Hits != 1 {
+		t.Errorf("stats = %+v, want 1 hit", stats)
+	}
+}
+
+func TestCacheStoresOnlySuccessfulResponses(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.EnableETags()
+	client := newCachedClient(t, srv, common.CacheConfig{DefaultTTL: time.Minute})
+	cluster := createTestCluster(t, client)
+
+	live, err := optimization.NewClientWithToken(srv.OptimizationEndpoint(), srv.IssueToken()).GetCluster(cluster.ID)
+	if err != nil {
+		t.Fatalf("GetCluster: %v", err)
+	}
+
+	// A 304 to the caller's own If-None-Match creates no entry
+	header := http.Header{"If-None-Match": []string{live.ETag}}
+	resp, err := client.DoRequestWithHeaders(context.Background(), http.MethodGet, "/clusters/"+cluster.ID, nil, header)
+	if err != nil {
+		t.Fatalf("conditional GET: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotModified {
+		t.Fatalf("conditional GET answered %d, want 304", resp.StatusCode)
+	}
+	if stats := client.CacheStats(); stats.Entries != 0 {
+		t.Errorf("stats = %+v, a 304 must not be cached", stats)
+	}
+
+	got, err := client.GetCluster(cluster.ID)
+	if err != nil || got.Name != cluster.Name {
+		t.Errorf("GetCluster after a 304 = %+v, %v", got, err)
+	}
+}
+
+func TestPingBypassesCache(t *testing.T) {
+	srv := newFakeServer(t)
+	client := newCachedClient(t, srv, common.CacheConfig{DefaultTTL: time.Minute})
+
+	if err := client.Ping(); err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+	before := srv.Requests()
+	if err := client.Ping(); err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+	if srv.Requests() == before {
+		t.Error("second Ping was served from the cache")
+	}
+
+	srv.Close()
+	if err := client.Ping(); err == nil {
+		t.Error("Ping succeeded after the server was closed")
+	}
+}
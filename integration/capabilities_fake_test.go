@@ -0,0 +1,134 @@
+//go:build !live
+
+package integration
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+func TestCapabilities(t *testing.T) {
+	client := newTestClient(t)
+
+	capabilities, err := client.Capabilities()
+	if err != nil {
+		t.Fatalf("Capabilities: %v", err)
+	}
+	if capabilities.Version == nil || capabilities.Version.ID != "v1" {
+		t.Errorf("version = %+v", capabilities.Version)
+	}
+
+	for _, feature := range []common.Feature{
+		common.FeatureWorkloadBalancing,
+		common.FeatureWorkloadConsolidation,
+		common.FeatureHostMaintenance,
+	} {
+		if !capabilities.Supports(feature) {
+			t.Errorf("%s is not supported", feature)
+		}
+	}
+
+	// The fake has no trials and no migration or watcher service
+	for _, feature := range []common.Feature{
+		common.FeatureTrials,
+		common.FeatureMigration,
+		common.FeatureCloudWatcher,
+	} {
+		if capabilities.Supports(feature) {
+			t.Errorf("%s is supported", feature)
+		}
+	}
+}
+
+func TestMissingFeatureFailsFast(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.Disable("host_maintenance")
+	client := newFakeClient(t, srv)
+	cluster := createTestCluster(t, client)
+
+	if _, err := client.ListWorkloadBalancingPolicies(nil); err != nil {
+		t.Fatalf("ListWorkloadBalancingPolicies: %v", err)
+	}
+
+	before := srv.Requests()
+	_, err := client.CreateHostMaintenancePolicy(&optimization.HostMaintenancePolicyCreate{
+		ClusterID: cluster.ID,
+		Name:      "maintenance",
+	})
+	if !errors.Is(err, common.ErrNotSupported) || !common.IsNotSupported(err) {
+		t.Fatalf("CreateHostMaintenancePolicy = %v, want ErrNotSupported", err)
+	}
+	if srv.Requests() != before {
+		t.Error("the unsupported request was sent")
+	}
+}
+
+// newRootServer serves an optimization API whose root document is answered
+// by root; it has no version document and lists no balancing policies
+func newRootServer(t *testing.T, root http.HandlerFunc) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/":
+			root(w, r)
+		case "/api/v1/workload-balancing":
+			w.Write([]byte("[]"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCapabilitiesWithUnrelatedRootLinks(t *testing.T) {
+	srv := newRootServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"links": [{"href": "/api/v1/", "rel": "self"}]}`))
+	})
+	client := optimization.NewClientWithToken(srv.URL, "token")
+
+	// A self link does not make the root document a list of resources
+	capabilities, err := client.Capabilities()
+	if err != nil {
+		t.Fatalf("Capabilities: %v", err)
+	}
+	for _, feature := range []common.Feature{
+		common.FeatureWorkloadBalancing,
+		common.FeatureWorkloadConsolidation,
+		common.FeatureHostMaintenance,
+	} {
+		if !capabilities.Supports(feature) {
+			t.Errorf("%s is not supported", feature)
+		}
+	}
+	if capabilities.Supports(common.FeatureTrials) {
+		t.Error("trials are supported")
+	}
+}
+
+func TestFailedDiscoveryDoesNotBlockRequests(t *testing.T) {
+	for _, status := range []int{http.StatusUnauthorized, http.StatusInternalServerError} {
+		var discoveries int
+		srv := newRootServer(t, func(w http.ResponseWriter, r *http.Request) {
+			discoveries++
+			http.Error(w, http.StatusText(status), status)
+		})
+		client := optimization.NewClientWithToken(srv.URL, "token")
+
+		// The request is sent, and discovery is tried again next time
+		for i := 0; i < 2; i++ {
+			if _, err := client.ListWorkloadBalancingPolicies(nil); err != nil {
+				t.Fatalf("ListWorkloadBalancingPolicies after a %d on the root: %v", status, err)
+			}
+		}
+		if discoveries != 2 {
+			t.Errorf("%d discoveries after a %d, want 2", discoveries, status)
+		}
+	}
+}
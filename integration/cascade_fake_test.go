@@ -0,0 +1,25 @@
+//go:build !live
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+func TestDeleteClusterCascadeWithoutPolicyType(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.Disable("host_maintenance")
+	client := newFakeClient(t, srv)
+	cluster := createTestCluster(t, client)
+	createTestHost(t, client, cluster.ID)
+
+	result, err := client.DeleteClusterCascade(cluster.ID, optimization.CascadeDeleteOptions{})
+	if err != nil {
+		t.Fatalf("DeleteClusterCascade: %v", err)
+	}
+	if len(result.Items) != 2 || srv.Count("clusters") != 0 || srv.Count("hosts") != 0 {
+		t.Errorf("cascade deleted %+v, leaving %d clusters and %d hosts", result.Items, srv.Count("clusters"), srv.Count("hosts"))
+	}
+}
@@ -0,0 +1,72 @@
+package integration
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+func TestDeleteClusterCascade(t *testing.T) {
+	client := newTestClient(t)
+	cluster := createTestCluster(t, client)
+
+	createTestHost(t, client, cluster.ID)
+	if _, err := client.CreateClusterExcludedVM(cluster.ID, &optimization.ClusterExcludedVMCreate{VMName: "database-01"}); err != nil {
+		t.Fatalf("CreateClusterExcludedVM: %v", err)
+	}
+	policy, err := client.CreateWorkloadConsolidationPolicy(&optimization.WorkloadConsolidationPolicyCreate{
+		ClusterID: cluster.ID,
+		Name:      "consolidation",
+		Period:    3600,
+	})
+	if err != nil {
+		t.Fatalf("CreateWorkloadConsolidationPolicy: %v", err)
+	}
+	cleanup(t, "workload consolidation policy", func() error { return client.DeleteWorkloadConsolidationPolicy(policy.ID) })
+
+	plan, err := client.DeleteClusterCascade(cluster.ID, optimization.CascadeDeleteOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("DeleteClusterCascade(dry run): %v", err)
+	}
+
+	wantKinds := []optimization.ResourceKind{
+		optimization.KindWorkloadConsolidationPolicy,
+		optimization.KindHost,
+		optimization.KindExcludedVM,
+		optimization.KindCluster,
+	}
+	if len(plan.Items) != len(wantKinds) {
+		t.Fatalf("dry run lists %d items, want %d:\n%s", len(plan.Items), len(wantKinds), plan)
+	}
+	for i, kind := range wantKinds {
+		if plan.Items[i].Kind != kind || plan.Items[i].Deleted {
+			t.Errorf("dry run item %d = %+v, want an undeleted %s", i, plan.Items[i], kind)
+		}
+	}
+	if !strings.Contains(plan.String(), "would delete cluster") {
+		t.Errorf("dry run output does not mention the cluster:\n%s", plan)
+	}
+
+	if _, err := client.GetCluster(cluster.ID); err != nil {
+		t.Fatalf("cluster is gone after a dry run: %v", err)
+	}
+
+	result, err := client.DeleteClusterCascade(cluster.ID, optimization.CascadeDeleteOptions{})
+	if err != nil {
+		t.Fatalf("DeleteClusterCascade: %v\n%s", err, result)
+	}
+	for _, item := range result.Items {
+		if !item.Deleted {
+			t.Errorf("%s %s was not deleted", item.Kind, item.ID)
+		}
+	}
+
+	if _, err := client.GetCluster(cluster.ID); !common.IsNotFound(err) {
+		t.Errorf("GetCluster after cascade delete returned %v, want a 404", err)
+	}
+	if _, err := client.GetWorkloadConsolidationPolicy(policy.ID); !common.IsNotFound(err) {
+		t.Errorf("GetWorkloadConsolidationPolicy after cascade delete returned %v, want a 404", err)
+	}
+}
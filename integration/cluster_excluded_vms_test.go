@@ -0,0 +1,49 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+func TestClusterExcludedVMCRUD(t *testing.T) {
+	client := newTestClient(t)
+	cluster := createTestCluster(t, client)
+
+	created, err := client.CreateClusterExcludedVM(cluster.ID, &optimization.ClusterExcludedVMCreate{
+		VMName: "database-01",
+	})
+	if err != nil {
+		t.Fatalf("CreateClusterExcludedVM: %v", err)
+	}
+	cleanup(t, "excluded VM", func() error { return client.DeleteClusterExcludedVM(cluster.ID, created.ID) })
+
+	if created.ID == "" || created.ClusterID != cluster.ID || created.VMName != "database-01" {
+		t.Fatalf("created excluded VM = %+v", created)
+	}
+
+	got, err := client.GetClusterExcludedVM(cluster.ID, created.ID)
+	if err != nil {
+		t.Fatalf("GetClusterExcludedVM: %v", err)
+	}
+	if got.VMName != created.VMName {
+		t.Errorf("GetClusterExcludedVM name = %q, want %q", got.VMName, created.VMName)
+	}
+
+	vms, err := client.ListClusterExcludedVMs(cluster.ID)
+	if err != nil {
+		t.Fatalf("ListClusterExcludedVMs: %v", err)
+	}
+	if len(vms) != 1 || vms[0].ID != created.ID {
+		t.Errorf("ListClusterExcludedVMs = %+v, want only VM %s", vms, created.ID)
+	}
+
+	if err := client.DeleteClusterExcludedVM(cluster.ID, created.ID); err != nil {
+		t.Fatalf("DeleteClusterExcludedVM: %v", err)
+	}
+
+	if _, err := client.GetClusterExcludedVM(cluster.ID, created.ID); !common.IsNotFound(err) {
+		t.Errorf("GetClusterExcludedVM after delete returned %v, want a 404", err)
+	}
+}
@@ -0,0 +1,66 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+func TestClusterHostCRUD(t *testing.T) {
+	client := newTestClient(t)
+	cluster := createTestCluster(t, client)
+
+	created, err := client.CreateClusterHost(cluster.ID, &optimization.ClusterHostCreate{
+		Hostname: "compute-01.example.com",
+		Enabled:  true,
+	})
+	if err != nil {
+		t.Fatalf("CreateClusterHost: %v", err)
+	}
+	cleanup(t, "host", func() error { return client.DeleteClusterHost(cluster.ID, created.ID) })
+
+	if created.ID == "" || created.ClusterID != cluster.ID {
+		t.Fatalf("created host = %+v, want an ID and cluster %s", created, cluster.ID)
+	}
+	if created.Hostname != "compute-01.example.com" || !created.Enabled {
+		t.Errorf("created host = %+v", created)
+	}
+
+	got, err := client.GetClusterHost(cluster.ID, created.ID)
+	if err != nil {
+		t.Fatalf("GetClusterHost: %v", err)
+	}
+	if got.Hostname != created.Hostname {
+		t.Errorf("GetClusterHost hostname = %q, want %q", got.Hostname, created.Hostname)
+	}
+
+	hosts, err := client.ListClusterHosts(cluster.ID)
+	if err != nil {
+		t.Fatalf("ListClusterHosts: %v", err)
+	}
+	if len(hosts) != 1 || hosts[0].ID != created.ID {
+		t.Errorf("ListClusterHosts = %+v, want only host %s", hosts, created.ID)
+	}
+
+	updated, err := client.UpdateClusterHost(cluster.ID, created.ID, &optimization.ClusterHostUpdate{
+		Enabled: common.Ptr(false),
+	})
+	if err != nil {
+		t.Fatalf("UpdateClusterHost: %v", err)
+	}
+	if updated.Enabled {
+		t.Error("host is still enabled after update")
+	}
+	if updated.Hostname != created.Hostname {
+		t.Errorf("UpdateClusterHost changed the hostname to %q", updated.Hostname)
+	}
+
+	if err := client.DeleteClusterHost(cluster.ID, created.ID); err != nil {
+		t.Fatalf("DeleteClusterHost: %v", err)
+	}
+
+	if _, err := client.GetClusterHost(cluster.ID, created.ID); !common.IsNotFound(err) {
+		t.Errorf("GetClusterHost after delete returned %v, want a 404", err)
+	}
+}
@@ -0,0 +1,119 @@
+package integration
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+func TestClusterCRUD(t *testing.T) {
+	client := newTestClient(t)
+
+	name := uniqueName(t, "cluster")
+	created, err := client.CreateCluster(&optimization.ClusterCreate{
+		Name:        name,
+		Description: "initial description",
+	})
+	if err != nil {
+		t.Fatalf("CreateCluster: %v", err)
+	}
+	cleanup(t, "cluster", func() error { return client.DeleteCluster(created.ID) })
+
+	if created.ID == "" {
+		t.Fatal("created cluster has no ID")
+	}
+	if created.Name != name || created.Description != "initial description" {
+		t.Errorf("created cluster = %+v, want name %q and the initial description", created, name)
+	}
+
+	got, err := client.GetCluster(created.ID)
+	if err != nil {
+		t.Fatalf("GetCluster: %v", err)
+	}
+	if got.ID != created.ID || got.Name != name {
+		t.Errorf("GetCluster = %+v, want %+v", got, created)
+	}
+
+	clusters, err := client.ListClusters()
+	if err != nil {
+		t.Fatalf("ListClusters: %v", err)
+	}
+	if !containsCluster(clusters, created.ID) {
+		t.Errorf("ListClusters does not contain cluster %s", created.ID)
+	}
+
+	updated, err := client.UpdateCluster(created.ID, &optimization.ClusterUpdate{
+		Description: common.Ptr("updated description"),
+	})
+	if err != nil {
+		t.Fatalf("UpdateCluster: %v", err)
+	}
+	if updated.Description != "updated description" {
+		t.Errorf("updated description = %q", updated.Description)
+	}
+	if updated.Name != name {
+		t.Errorf("UpdateCluster changed the name to %q", updated.Name)
+	}
+
+	cleared, err := client.UpdateCluster(created.ID, &optimization.ClusterUpdate{
+		Description: common.Ptr(""),
+	})
+	if err != nil {
+		t.Fatalf("UpdateCluster(clear description): %v", err)
+	}
+	if cleared.Description != "" || cleared.Name != name {
+		t.Errorf("cluster after clearing the description = %+v", cleared)
+	}
+
+	if err := client.DeleteCluster(created.ID); err != nil {
+		t.Fatalf("DeleteCluster: %v", err)
+	}
+
+	if _, err := client.GetCluster(created.ID); !common.IsNotFound(err) {
+		t.Errorf("GetCluster after delete returned %v, want a 404", err)
+	}
+}
+
+func TestGetClusterNotFound(t *testing.T) {
+	client := newTestClient(t)
+
+	_, err := client.GetCluster("00000000-0000-4000-8000-000000000000")
+	if !common.IsNotFound(err) {
+		t.Fatalf("GetCluster(unknown) returned %v, want a 404", err)
+	}
+}
+
+func TestClusterUpdateJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		req  optimization.ClusterUpdate
+		want string
+	}{
+		{"absent", optimization.ClusterUpdate{}, `{}`},
+		{"zero", optimization.ClusterUpdate{Description: common.Ptr("")}, `{"description":""}`},
+		{"value", optimization.ClusterUpdate{Name: common.Ptr("c1")}, `{"name":"c1"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.req)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("Marshal = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func containsCluster(clusters []optimization.Cluster, id string) bool {
+	for _, cluster := range clusters {
+		if cluster.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/overwatch144/golang-safirclient/common"
+)
+
+func TestAuthenticator(t *testing.T) {
+	opts := newTestAuthOptions(t)
+
+	auth, err := common.NewAuthenticator(opts)
+	if err != nil {
+		t.Fatalf("NewAuthenticator: %v", err)
+	}
+
+	token, err := auth.GetToken()
+	if err != nil {
+		t.Fatalf("GetToken: %v", err)
+	}
+	if token == "" {
+		t.Error("GetToken returned an empty token")
+	}
+
+	if expiry := auth.GetTokenExpiry(); !expiry.After(time.Now()) {
+		t.Errorf("token expiry %s is not in the future", expiry)
+	}
+	if auth.IsTokenExpired() {
+		t.Error("fresh token reported as expired")
+	}
+
+	info := auth.GetAuthInfo()
+	if info.Username != opts.Username {
+		t.Errorf("AuthInfo.Username = %q, want %q", info.Username, opts.Username)
+	}
+	if info.IsExpired {
+		t.Error("AuthInfo.IsExpired is true for a fresh token")
+	}
+
+	endpoint, err := auth.GetEndpoint(common.ServiceTypeOptimization)
+	if err != nil {
+		t.Fatalf("GetEndpoint(%s): %v", common.ServiceTypeOptimization, err)
+	}
+	if endpoint == "" {
+		t.Error("optimization endpoint is empty")
+	}
+
+	if err := auth.Reauth(); err != nil {
+		t.Fatalf("Reauth: %v", err)
+	}
+	if _, err := auth.GetToken(); err != nil {
+		t.Fatalf("GetToken after Reauth: %v", err)
+	}
+}
+
+func TestAuthenticatorRejectsBadCredentials(t *testing.T) {
+	opts := *newTestAuthOptions(t)
+	opts.Password = "wrong-password"
+
+	if _, err := common.NewAuthenticator(&opts); err == nil {
+		t.Fatal("NewAuthenticator succeeded with a wrong password")
+	}
+}
+
+func TestValidateAuthOptions(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  *common.AuthOptions
+		field string
+	}{
+		{"nil", nil, "auth_options"},
+		{"missing endpoint", &common.AuthOptions{Username: "u", Password: "p"}, "identity_endpoint"},
+		{"no method", &common.AuthOptions{IdentityEndpoint: "http://keystone/v3", Username: "u"}, "authentication"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := common.ValidateAuthOptions(tt.opts)
+			validationErr, ok := err.(*common.ValidationError)
+			if !ok {
+				t.Fatalf("ValidateAuthOptions returned %v, want a *ValidationError", err)
+			}
+			if validationErr.Field != tt.field {
+				t.Errorf("ValidationError.Field = %q, want %q", validationErr.Field, tt.field)
+			}
+		})
+	}
+
+	valid := &common.AuthOptions{IdentityEndpoint: "http://keystone/v3", TokenID: "token"}
+	if err := common.ValidateAuthOptions(valid); err != nil {
+		t.Errorf("ValidateAuthOptions(token auth) = %v, want nil", err)
+	}
+}
+
+func TestPing(t *testing.T) {
+	client := newTestClient(t)
+
+	if err := client.Ping(); err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+}
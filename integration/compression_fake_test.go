@@ -0,0 +1,113 @@
+//go:build !live
+
+package integration
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/fakesafir"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+func TestCompression(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.EnableCompression()
+
+	// A custom transport does not decompress responses by itself
+	client, err := optimization.NewClient(optimization.ClientOptions{
+		AuthURL:         srv.IdentityEndpoint(),
+		Username:        fakesafir.Username,
+		Password:        fakesafir.Password,
+		ProjectName:     fakesafir.ProjectName,
+		ProjectDomainID: fakesafir.DomainID,
+		UserDomainID:    fakesafir.DomainID,
+		Compression:     common.CompressionConfig{GzipRequests: true, MinRequestSize: 1},
+		Transport:       &http.Transport{DisableCompression: true},
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	cluster := createTestCluster(t, client)
+	host := createTestHost(t, client, cluster.ID)
+
+	got, err := client.GetClusterHost(cluster.ID, host.ID)
+	if err != nil {
+		t.Fatalf("GetClusterHost: %v", err)
+	}
+	if got.Hostname != host.Hostname {
+		t.Errorf("hostname = %q, want %q", got.Hostname, host.Hostname)
+	}
+
+	hosts, err := client.ListClusterHosts(cluster.ID)
+	if err != nil || len(hosts) != 1 {
+		t.Errorf("ListClusterHosts = %d hosts, %v", len(hosts), err)
+	}
+
+	// The size limit applies to the decompressed body
+	client.SetMaxResponseSize(16)
+	if _, err := client.ListClusterHosts(cluster.ID); !common.IsResponseTooLarge(err) {
+		t.Errorf("ListClusterHosts over the size limit = %v, want ResponseTooLargeError", err)
+	}
+}
+
+func TestCompressionHeaders(t *testing.T) {
+	var encoding string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/clusters" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Accept-Encoding") != "gzip, deflate" {
+			t.Errorf("Accept-Encoding = %q", r.Header.Get("Accept-Encoding"))
+		}
+		if r.Method == http.MethodPost && r.Header.Get("Content-Encoding") != "gzip" {
+			t.Errorf("request Content-Encoding = %q, want gzip", r.Header.Get("Content-Encoding"))
+		}
+
+		body := []byte(`[{"id": "cluster", "name": "prod"}]`)
+		var buf bytes.Buffer
+		switch encoding {
+		case "gzip":
+			writer := gzip.NewWriter(&buf)
+			writer.Write(body)
+			writer.Close()
+		case "deflate":
+			// Raw deflate, as sent by some servers instead of zlib
+			writer, _ := flate.NewWriter(&buf, flate.DefaultCompression)
+			writer.Write(body)
+			writer.Close()
+		default:
+			buf.Write(body)
+		}
+		if encoding != "" {
+			w.Header().Set("Content-Encoding", encoding)
+		}
+		w.Write(buf.Bytes())
+	}))
+	defer srv.Close()
+
+	client := optimization.NewClientWithToken(srv.URL, "token")
+	client.SetCompression(common.CompressionConfig{GzipRequests: true, MinRequestSize: 1})
+
+	for _, encoding = range []string{"", "gzip", "deflate"} {
+		clusters, err := client.ListClusters()
+		if err != nil || len(clusters) != 1 || clusters[0].Name != "prod" {
+			t.Errorf("ListClusters with %q = %+v, %v", encoding, clusters, err)
+		}
+	}
+
+	resp, err := client.DoRequest(http.MethodPost, "/clusters", map[string]string{"name": "prod"})
+	if err != nil {
+		t.Fatalf("DoRequest: %v", err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
@@ -0,0 +1,50 @@
+//go:build !live
+
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+func TestUpdateIfUnchangedWithETags(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.EnableETags()
+
+	auth, err := common.NewAuthenticator(fakeAuthOptions(srv))
+	if err != nil {
+		t.Fatalf("NewAuthenticator: %v", err)
+	}
+	client, err := optimization.NewClientWithAuthenticator(auth)
+	if err != nil {
+		t.Fatalf("NewClientWithAuthenticator: %v", err)
+	}
+
+	testUpdateIfUnchanged(t, client)
+}
+
+func TestModifyKeepsUnknownBalancingMode(t *testing.T) {
+	client := newTestClient(t)
+	policy := createTestWorkloadBalancingPolicy(t, client)
+
+	// A newer server may use a mode this client does not know
+	resp, err := client.DoRequest(http.MethodPut, "/workload-balancing/"+policy.ID, map[string]string{"balancing_mode": "extreme"})
+	if err != nil {
+		t.Fatalf("setting an unknown mode: %v", err)
+	}
+	resp.Body.Close()
+
+	modified, err := client.ModifyWorkloadBalancingPolicy(policy.ID, func(policy *optimization.WorkloadBalancingPolicy) error {
+		policy.Period = 600
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ModifyWorkloadBalancingPolicy: %v", err)
+	}
+	if modified.Period != 600 || modified.BalancingMode != "extreme" || !modified.BalancingMode.Unknown() {
+		t.Errorf("modified policy = %+v", modified)
+	}
+}
@@ -0,0 +1,94 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+// createTestWorkloadBalancingPolicy creates a policy that is deleted when the test ends
+func createTestWorkloadBalancingPolicy(t *testing.T, client *optimization.Client) *optimization.WorkloadBalancingPolicy {
+	t.Helper()
+
+	cluster := createTestCluster(t, client)
+	policy, err := client.CreateWorkloadBalancingPolicy(&optimization.WorkloadBalancingPolicyCreate{
+		ClusterID:     cluster.ID,
+		Name:          "balancing",
+		BalancingMode: optimization.BalancingModeModerate,
+		Period:        3600,
+		Enabled:       true,
+	})
+	if err != nil {
+		t.Fatalf("CreateWorkloadBalancingPolicy: %v", err)
+	}
+	cleanup(t, "workload balancing policy", func() error { return client.DeleteWorkloadBalancingPolicy(policy.ID) })
+
+	return policy
+}
+
+func TestUpdateIfUnchangedDetectsConflicts(t *testing.T) {
+	client := newTestClient(t)
+	testUpdateIfUnchanged(t, client)
+}
+
+func testUpdateIfUnchanged(t *testing.T, client *optimization.Client) {
+	created := createTestWorkloadBalancingPolicy(t, client)
+
+	first, err := client.GetWorkloadBalancingPolicy(created.ID)
+	if err != nil {
+		t.Fatalf("GetWorkloadBalancingPolicy: %v", err)
+	}
+	second := *first
+
+	if _, err := client.UpdateWorkloadBalancingPolicyIfUnchanged(first, &optimization.WorkloadBalancingPolicyUpdate{
+		Period: common.Ptr(1800),
+	}); err != nil {
+		t.Fatalf("first conditional update: %v", err)
+	}
+
+	_, err = client.UpdateWorkloadBalancingPolicyIfUnchanged(&second, &optimization.WorkloadBalancingPolicyUpdate{
+		Period: common.Ptr(900),
+	})
+	if !common.IsConflict(err) {
+		t.Fatalf("conditional update of a stale copy returned %v, want a conflict", err)
+	}
+
+	live, err := client.GetWorkloadBalancingPolicy(created.ID)
+	if err != nil {
+		t.Fatalf("GetWorkloadBalancingPolicy: %v", err)
+	}
+	if live.Period != 1800 {
+		t.Errorf("period = %d, the stale update must not have been applied", live.Period)
+	}
+}
+
+func TestModifyRetriesOnConflict(t *testing.T) {
+	client := newTestClient(t)
+	created := createTestWorkloadBalancingPolicy(t, client)
+
+	calls := 0
+	modified, err := client.ModifyWorkloadBalancingPolicy(created.ID, func(policy *optimization.WorkloadBalancingPolicy) error {
+		calls++
+		if calls == 1 {
+			// Someone else changes the policy between our read and write
+			if _, err := client.UpdateWorkloadBalancingPolicy(created.ID, &optimization.WorkloadBalancingPolicyUpdate{
+				Enabled: common.Ptr(false),
+			}); err != nil {
+				t.Fatalf("concurrent update: %v", err)
+			}
+		}
+		policy.Period = 600
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ModifyWorkloadBalancingPolicy: %v", err)
+	}
+
+	if calls != 2 {
+		t.Errorf("modify called %d times, want 2", calls)
+	}
+	if modified.Period != 600 || modified.Enabled {
+		t.Errorf("modified policy = %+v, want period 600 and the concurrent disable kept", modified)
+	}
+}
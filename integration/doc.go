@@ -0,0 +1,15 @@
+// Package integration contains the end-to-end tests of the Safir client.
+//
+// By default the tests run against the in-memory fake from the fakesafir
+// package:
+//
+//	go test ./integration/...
+//
+// With the live build tag they run against a real cloud configured through
+// the OS_* environment variables (for example after sourcing an openrc file):
+//
+//	go test -tags live ./integration/...
+//
+// Every resource created by a test is removed with t.Cleanup, even when the
+// test fails.
+package integration
@@ -0,0 +1,34 @@
+//go:build !live
+
+package integration
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+func TestExportWithoutPolicyType(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.Disable("host_maintenance")
+	client := newFakeClient(t, srv)
+	cluster := createTestCluster(t, client)
+
+	// The policy type the deployment does not offer is exported empty
+	doc, err := client.ExportCluster(cluster.ID)
+	if err != nil {
+		t.Fatalf("ExportCluster: %v", err)
+	}
+	data, err := doc.Encode(optimization.FormatJSON)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if string(fields["host_maintenance_policies"]) != "[]" {
+		t.Errorf("host_maintenance_policies = %s, want []", fields["host_maintenance_policies"])
+	}
+}
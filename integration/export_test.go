@@ -0,0 +1,84 @@
+package integration
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+func TestExportImportRoundTrip(t *testing.T) {
+	client := newTestClient(t)
+	cluster := createTestCluster(t, client)
+	host := createTestHost(t, client, cluster.ID)
+
+	policy, err := client.CreateHostMaintenancePolicy(&optimization.HostMaintenancePolicyCreate{
+		ClusterID: cluster.ID,
+		Name:      uniqueName(t, "maintenance"),
+		Enabled:   true,
+	})
+	if err != nil {
+		t.Fatalf("CreateHostMaintenancePolicy: %v", err)
+	}
+	cleanup(t, "host maintenance policy", func() error { return client.DeleteHostMaintenancePolicy(policy.ID) })
+
+	doc, err := client.ExportCluster(cluster.ID)
+	if err != nil {
+		t.Fatalf("ExportCluster: %v", err)
+	}
+
+	for _, format := range []optimization.DocumentFormat{optimization.FormatJSON, optimization.FormatYAML} {
+		data, err := doc.Encode(format)
+		if err != nil {
+			t.Fatalf("Encode(%s): %v", format, err)
+		}
+
+		decoded, err := optimization.DecodeClusterDocument(data)
+		if err != nil {
+			t.Fatalf("DecodeClusterDocument(%s): %v", format, err)
+		}
+		if decoded.Cluster.Name != cluster.Name || len(decoded.Hosts) != 1 || len(decoded.HostMaintenancePolicies) != 1 {
+			t.Fatalf("%s round trip = %+v", format, decoded)
+		}
+		doc = decoded
+	}
+
+	doc.Cluster.Name = uniqueName(t, "imported")
+	result, err := client.ImportCluster(doc)
+	if err != nil {
+		t.Fatalf("ImportCluster: %v", err)
+	}
+	imported := result.Cluster.ID
+	cleanup(t, "imported cluster", func() error {
+		_, err := client.DeleteClusterCascade(imported, optimization.CascadeDeleteOptions{})
+		return err
+	})
+
+	if result.IDMap[cluster.ID] != imported || result.IDMap[host.ID] == "" || result.IDMap[policy.ID] == "" {
+		t.Errorf("IDMap = %v", result.IDMap)
+	}
+
+	hosts, err := client.ListClusterHosts(imported)
+	if err != nil || len(hosts) != 1 || hosts[0].Hostname != host.Hostname {
+		t.Errorf("imported hosts = %+v, %v", hosts, err)
+	}
+	policies, err := client.ListHostMaintenancePolicies(&imported)
+	if err != nil || len(policies) != 1 || policies[0].Name != policy.Name {
+		t.Errorf("imported policies = %+v, %v", policies, err)
+	}
+}
+
+func TestDecodeClusterDocumentValidation(t *testing.T) {
+	_, err := optimization.DecodeClusterDocument([]byte("api_version: v0\nkind: Cluster\n"))
+
+	var errs common.ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 3 {
+		t.Fatalf("DecodeClusterDocument = %v, want 3 validation errors", err)
+	}
+	for i, field := range []string{"api_version", "kind", "cluster.name"} {
+		if errs[i].Field != field {
+			t.Errorf("error %d is for %q, want %q", i, errs[i].Field, field)
+		}
+	}
+}
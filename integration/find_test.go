@@ -0,0 +1,95 @@
+package integration
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+func TestFindClusterByName(t *testing.T) {
+	client := newTestClient(t)
+	cluster := createTestCluster(t, client)
+
+	found, err := client.FindClusterByName(cluster.Name)
+	if err != nil {
+		t.Fatalf("FindClusterByName: %v", err)
+	}
+	if found.ID != cluster.ID {
+		t.Errorf("FindClusterByName returned cluster %s, want %s", found.ID, cluster.ID)
+	}
+
+	for _, nameOrID := range []string{cluster.ID, cluster.Name} {
+		id, err := client.ResolveClusterID(nameOrID)
+		if err != nil || id != cluster.ID {
+			t.Errorf("ResolveClusterID(%q) = %q, %v, want %q", nameOrID, id, err, cluster.ID)
+		}
+	}
+
+	_, err = client.FindClusterByName(uniqueName(t, "missing"))
+	if !common.IsNotFound(err) {
+		t.Errorf("FindClusterByName(missing) returned %v, want a not found error", err)
+	}
+}
+
+func TestResolveClusterIDWithReservedCharacters(t *testing.T) {
+	client := newTestClient(t)
+	other := createTestCluster(t, client)
+
+	// Tried as an ID, the name must not turn into the path of other
+	for _, name := range []string{other.ID + "?name", other.ID + "/hosts"} {
+		cluster, err := client.CreateCluster(&optimization.ClusterCreate{Name: name})
+		if err != nil {
+			t.Fatalf("CreateCluster: %v", err)
+		}
+		cleanup(t, "cluster "+cluster.ID, func() error { return client.DeleteCluster(cluster.ID) })
+
+		id, err := client.ResolveClusterID(name)
+		if err != nil || id != cluster.ID {
+			t.Errorf("ResolveClusterID(%q) = %q, %v, want %q", name, id, err, cluster.ID)
+		}
+	}
+}
+
+func TestFindAmbiguousName(t *testing.T) {
+	client := newTestClient(t)
+	cluster := createTestCluster(t, client)
+
+	var ids []string
+	for i := 0; i < 2; i++ {
+		policy, err := client.CreateHostMaintenancePolicy(&optimization.HostMaintenancePolicyCreate{
+			ClusterID: cluster.ID,
+			Name:      "duplicate",
+		})
+		if err != nil {
+			t.Fatalf("CreateHostMaintenancePolicy: %v", err)
+		}
+		cleanup(t, "host maintenance policy", func() error { return client.DeleteHostMaintenancePolicy(policy.ID) })
+		ids = append(ids, policy.ID)
+	}
+
+	_, err := client.FindHostMaintenancePolicyByName(&cluster.ID, "duplicate")
+
+	var ambiguous *common.AmbiguousError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("FindHostMaintenancePolicyByName returned %v, want an AmbiguousError", err)
+	}
+	if len(ambiguous.Candidates) != 2 || ambiguous.Candidates[0] != ids[0] || ambiguous.Candidates[1] != ids[1] {
+		t.Errorf("candidates = %v, want %v", ambiguous.Candidates, ids)
+	}
+}
+
+func TestFindClusterHostByHostname(t *testing.T) {
+	client := newTestClient(t)
+	cluster := createTestCluster(t, client)
+	host := createTestHost(t, client, cluster.ID)
+
+	found, err := client.FindClusterHostByHostname(cluster.ID, host.Hostname)
+	if err != nil {
+		t.Fatalf("FindClusterHostByHostname: %v", err)
+	}
+	if found.ID != host.ID {
+		t.Errorf("FindClusterHostByHostname returned host %s, want %s", found.ID, host.ID)
+	}
+}
@@ -0,0 +1,135 @@
+//go:build !live
+
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/fakesafir"
+	"github.com/overwatch144/golang-safirclient/optimization"
+	"github.com/overwatch144/golang-safirclient/safir"
+)
+
+func TestHealthCheck(t *testing.T) {
+	client := newTestClient(t)
+
+	report := client.HealthCheck(context.Background())
+	if report.Status != common.HealthStatusOK || report.Err() != nil {
+		t.Fatalf("report = %+v, err = %v", report, report.Err())
+	}
+
+	if report.Token.ExpiresAt == nil || report.Token.IssuedAt == nil {
+		t.Errorf("token = %+v, want issue and expiry times", report.Token)
+	}
+	if report.Token.ClockSkew != 0 {
+		t.Errorf("clock skew against a local Keystone = %s", report.Token.ClockSkew)
+	}
+
+	want := map[common.ServiceType]common.HealthStatus{
+		common.ServiceTypeIdentity:     common.HealthStatusOK,
+		common.ServiceTypeOptimization: common.HealthStatusOK,
+		common.ServiceTypeMigration:    common.HealthStatusSkipped,
+		common.ServiceTypeCloudWatcher: common.HealthStatusSkipped,
+	}
+	for _, endpoint := range report.Endpoints {
+		if endpoint.Status != want[endpoint.Service] {
+			t.Errorf("%s = %+v, want %s", endpoint.Service, endpoint, want[endpoint.Service])
+		}
+		if endpoint.Status == common.HealthStatusOK && endpoint.Latency <= 0 {
+			t.Errorf("%s has no latency", endpoint.Service)
+		}
+	}
+
+	// A service required by the caller but missing from the catalog fails
+	report = client.HealthCheckWithOptions(context.Background(), common.HealthCheckOptions{
+		RequiredServices: []common.ServiceType{common.ServiceTypeOptimization, common.ServiceTypeMigration},
+	})
+	if report.Healthy() {
+		t.Error("report without a required service is healthy")
+	}
+}
+
+func TestHealthCheckFromEnvironment(t *testing.T) {
+	srv := newFakeServer(t)
+	t.Setenv("OS_CLOUD", "")
+	t.Setenv("OS_AUTH_URL", srv.IdentityEndpoint())
+	t.Setenv("OS_USERNAME", fakesafir.Username)
+	t.Setenv("OS_PASSWORD", fakesafir.Password)
+	t.Setenv("OS_USER_DOMAIN_ID", fakesafir.DomainID)
+	t.Setenv("OS_PROJECT_NAME", fakesafir.ProjectName)
+	t.Setenv("OS_PROJECT_DOMAIN_ID", fakesafir.DomainID)
+
+	report := safir.HealthCheck(context.Background())
+	if err := report.Err(); err != nil || report.Token.Status != common.HealthStatusOK {
+		t.Fatalf("report = %+v, err = %v", report, err)
+	}
+
+	// Credentials Keystone rejects fail the token check
+	t.Setenv("OS_PASSWORD", "wrong")
+	report = safir.HealthCheck(context.Background())
+	if report.Healthy() || report.Token.Status != common.HealthStatusFailed {
+		t.Errorf("report with a wrong password = %+v", report)
+	}
+}
+
+func TestHealthCheckRevokedToken(t *testing.T) {
+	srv := newFakeServer(t)
+	auth, err := common.NewAuthenticator(fakeAuthOptions(srv))
+	if err != nil {
+		t.Fatalf("NewAuthenticator: %v", err)
+	}
+
+	srv.RevokeTokens()
+	report := auth.HealthCheck(context.Background())
+	if report.Token.Status != common.HealthStatusFailed || report.Err() == nil {
+		t.Errorf("token = %+v, want a failure", report.Token)
+	}
+}
+
+func TestHealthCheckCertificateExpiry(t *testing.T) {
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer tlsServer.Close()
+
+	client := optimization.NewClientWithToken(tlsServer.URL, "token")
+	report := client.HealthCheckWithOptions(context.Background(), common.HealthCheckOptions{
+		HTTPClient:         tlsServer.Client(),
+		CertificateWarning: 100 * 365 * 24 * time.Hour,
+	})
+
+	if report.Token.Status != common.HealthStatusSkipped {
+		t.Errorf("static token = %+v, want skipped", report.Token)
+	}
+	if len(report.Endpoints) != 1 {
+		t.Fatalf("endpoints = %+v, want the client's own", report.Endpoints)
+	}
+	endpoint := report.Endpoints[0]
+	if endpoint.CertificateExpiry == nil || endpoint.Status != common.HealthStatusDegraded {
+		t.Errorf("endpoint = %+v, want a degraded certificate", endpoint)
+	}
+	if !report.Healthy() {
+		t.Error("a certificate about to expire made the report unhealthy")
+	}
+}
+
+func TestHealthCheckUsesClientTransport(t *testing.T) {
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer tlsServer.Close()
+
+	// Only the client's Transport trusts the test certificate
+	client := optimization.NewClientWithToken(tlsServer.URL, "token", optimization.ClientOptions{
+		Transport: tlsServer.Client().Transport,
+	})
+	report := client.HealthCheck(context.Background())
+	if len(report.Endpoints) != 1 || report.Endpoints[0].Status != common.HealthStatusOK {
+		t.Errorf("endpoints = %+v, want the client's own probed with its Transport", report.Endpoints)
+	}
+}
@@ -0,0 +1,60 @@
+package integration
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+// newTestClient returns an Optimization client for the test environment
+func newTestClient(t *testing.T) *optimization.Client {
+	t.Helper()
+
+	auth, err := common.NewAuthenticator(newTestAuthOptions(t))
+	if err != nil {
+		t.Fatalf("failed to create authenticator: %v", err)
+	}
+
+	client, err := optimization.NewClientWithAuthenticator(auth)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	return client
+}
+
+// uniqueName returns a name that does not collide with other test runs
+func uniqueName(t *testing.T, prefix string) string {
+	return fmt.Sprintf("%s-%s-%d", prefix, strings.ReplaceAll(t.Name(), "/", "-"), time.Now().UnixNano())
+}
+
+// cleanup registers a delete function that tolerates already deleted resources
+func cleanup(t *testing.T, what string, del func() error) {
+	t.Helper()
+
+	t.Cleanup(func() {
+		if err := del(); err != nil && !common.IsNotFound(err) {
+			t.Errorf("failed to clean up %s: %v", what, err)
+		}
+	})
+}
+
+// createTestCluster creates a cluster that is deleted when the test ends
+func createTestCluster(t *testing.T, client *optimization.Client) *optimization.Cluster {
+	t.Helper()
+
+	cluster, err := client.CreateCluster(&optimization.ClusterCreate{
+		Name:        uniqueName(t, "cluster"),
+		Description: "Created by golang-safirclient integration tests",
+	})
+	if err != nil {
+		t.Fatalf("failed to create cluster: %v", err)
+	}
+	cleanup(t, "cluster "+cluster.ID, func() error { return client.DeleteCluster(cluster.ID) })
+
+	return cluster
+}
@@ -0,0 +1,61 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+func TestHostMaintenancePolicyCRUD(t *testing.T) {
+	client := newTestClient(t)
+	cluster := createTestCluster(t, client)
+
+	created, err := client.CreateHostMaintenancePolicy(&optimization.HostMaintenancePolicyCreate{
+		ClusterID: cluster.ID,
+		Name:      "maintenance",
+		Enabled:   true,
+	})
+	if err != nil {
+		t.Fatalf("CreateHostMaintenancePolicy: %v", err)
+	}
+	cleanup(t, "host maintenance policy", func() error { return client.DeleteHostMaintenancePolicy(created.ID) })
+
+	if created.ID == "" || created.ClusterID != cluster.ID || created.Name != "maintenance" || !created.Enabled {
+		t.Fatalf("created policy = %+v", created)
+	}
+
+	got, err := client.GetHostMaintenancePolicy(created.ID)
+	if err != nil {
+		t.Fatalf("GetHostMaintenancePolicy: %v", err)
+	}
+	if got.Name != created.Name {
+		t.Errorf("GetHostMaintenancePolicy name = %q, want %q", got.Name, created.Name)
+	}
+
+	policies, err := client.ListHostMaintenancePolicies(&cluster.ID)
+	if err != nil {
+		t.Fatalf("ListHostMaintenancePolicies: %v", err)
+	}
+	if len(policies) != 1 || policies[0].ID != created.ID {
+		t.Errorf("ListHostMaintenancePolicies(cluster) = %+v, want only policy %s", policies, created.ID)
+	}
+
+	updated, err := client.UpdateHostMaintenancePolicy(created.ID, &optimization.HostMaintenancePolicyUpdate{
+		Enabled: common.Ptr(false),
+	})
+	if err != nil {
+		t.Fatalf("UpdateHostMaintenancePolicy: %v", err)
+	}
+	if updated.Enabled {
+		t.Error("policy is still enabled after update")
+	}
+
+	if err := client.DeleteHostMaintenancePolicy(created.ID); err != nil {
+		t.Fatalf("DeleteHostMaintenancePolicy: %v", err)
+	}
+
+	if _, err := client.GetHostMaintenancePolicy(created.ID); !common.IsNotFound(err) {
+		t.Errorf("GetHostMaintenancePolicy after delete returned %v, want a 404", err)
+	}
+}
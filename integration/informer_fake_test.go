@@ -0,0 +1,136 @@
+//go:build !live
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/optimization"
+	"github.com/overwatch144/golang-safirclient/optimization/informer"
+)
+
+// drainEvents returns the events already sent by the informer
+func drainEvents(inf *informer.Informer) []informer.Event {
+	var events []informer.Event
+	for {
+		select {
+		case event := <-inf.Events():
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestInformerReportsChanges(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+	inf := informer.New(client, informer.Options{})
+
+	cluster := createTestCluster(t, client)
+	host := createTestHost(t, client, cluster.ID)
+
+	if err := inf.Poll(ctx); err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if !inf.HasSynced() {
+		t.Error("HasSynced = false after a successful poll")
+	}
+	events := drainEvents(inf)
+	if len(events) != 2 || events[0].Type != informer.EventAdded || events[0].Kind != optimization.KindCluster ||
+		events[1].Type != informer.EventAdded || events[1].Kind != optimization.KindHost || events[1].ID != host.ID {
+		t.Fatalf("events after first poll = %+v, want cluster and host added", events)
+	}
+	if hosts := inf.Store().Hosts(cluster.ID); len(hosts) != 1 || hosts[0].ID != host.ID {
+		t.Errorf("Store().Hosts = %+v", hosts)
+	}
+
+	// Nothing changed, nothing to report
+	if err := inf.Poll(ctx); err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if events := drainEvents(inf); len(events) != 0 {
+		t.Errorf("events without changes = %+v", events)
+	}
+
+	time.Sleep(time.Millisecond)
+	if _, err := client.UpdateClusterHost(cluster.ID, host.ID, &optimization.ClusterHostUpdate{Enabled: common.Ptr(false)}); err != nil {
+		t.Fatalf("UpdateClusterHost: %v", err)
+	}
+	if err := inf.Poll(ctx); err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	events = drainEvents(inf)
+	if len(events) != 1 || events[0].Type != informer.EventUpdated {
+		t.Fatalf("events after update = %+v, want host updated", events)
+	}
+	if old, current := events[0].Old.(optimization.ClusterHost), events[0].Object.(optimization.ClusterHost); !old.Enabled || current.Enabled {
+		t.Errorf("update event Old = %+v, Object = %+v", old, current)
+	}
+
+	if err := client.DeleteClusterHost(cluster.ID, host.ID); err != nil {
+		t.Fatalf("DeleteClusterHost: %v", err)
+	}
+	if err := inf.Poll(ctx); err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	events = drainEvents(inf)
+	if len(events) != 1 || events[0].Type != informer.EventDeleted || events[0].ID != host.ID {
+		t.Fatalf("events after delete = %+v, want host deleted", events)
+	}
+	if _, ok := inf.Store().Host(host.ID); ok {
+		t.Error("deleted host is still in the store")
+	}
+
+	if err := inf.Resync(ctx); err != nil {
+		t.Fatalf("Resync: %v", err)
+	}
+	events = drainEvents(inf)
+	if len(events) != 1 || !events[0].Resync || events[0].ID != cluster.ID {
+		t.Errorf("events after resync = %+v, want the cluster", events)
+	}
+}
+
+func TestInformerStart(t *testing.T) {
+	client := newTestClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	inf := informer.New(client, informer.Options{Interval: 10 * time.Millisecond})
+	inf.Start(ctx)
+
+	cluster := createTestCluster(t, client)
+
+	timeout := time.After(5 * time.Second)
+	for added := false; !added; {
+		select {
+		case event := <-inf.Events():
+			added = event.Type == informer.EventAdded && event.ID == cluster.ID
+		case <-timeout:
+			t.Fatal("cluster was not reported as added")
+		}
+	}
+
+	cancel()
+	for range inf.Events() {
+	}
+}
+
+func TestInformerWithoutPolicyType(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.Disable("host_maintenance")
+	client := newFakeClient(t, srv)
+	policy := createTestWorkloadBalancingPolicy(t, client)
+
+	inf := informer.New(client, informer.Options{})
+	if err := inf.Poll(context.Background()); err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if !inf.HasSynced() {
+		t.Error("HasSynced = false without host maintenance")
+	}
+	if _, ok := inf.Store().WorkloadBalancingPolicy(policy.ID); !ok {
+		t.Error("workload balancing policy missing from the store")
+	}
+}
@@ -0,0 +1,178 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+// fakeEvacuator drains a host by one VM per poll
+type fakeEvacuator struct {
+	mutex     sync.Mutex
+	vms       int
+	stuck     bool
+	evacuated []string
+}
+
+func (e *fakeEvacuator) Evacuate(ctx context.Context, hostname string) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.evacuated = append(e.evacuated, hostname)
+	return nil
+}
+
+func (e *fakeEvacuator) RemainingVMs(ctx context.Context, hostname string) (int, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	remaining := e.vms
+	if !e.stuck && e.vms > 0 {
+		e.vms--
+	}
+	return remaining, nil
+}
+
+func createTestHost(t *testing.T, client *optimization.Client, clusterID string) *optimization.ClusterHost {
+	t.Helper()
+
+	host, err := client.CreateClusterHost(clusterID, &optimization.ClusterHostCreate{
+		Hostname: "compute-01",
+		Enabled:  true,
+	})
+	if err != nil {
+		t.Fatalf("CreateClusterHost: %v", err)
+	}
+	cleanup(t, "host", func() error { return client.DeleteClusterHost(clusterID, host.ID) })
+
+	return host
+}
+
+// cleanupMaintenancePolicies deletes the host maintenance policies that
+// maintenance creates for the cluster when the test ends
+func cleanupMaintenancePolicies(t *testing.T, client *optimization.Client, clusterID string) {
+	t.Helper()
+
+	cleanup(t, "host maintenance policies", func() error {
+		policies, err := client.ListHostMaintenancePolicies(&clusterID)
+		if err != nil {
+			return err
+		}
+		for _, policy := range policies {
+			if err := client.DeleteHostMaintenancePolicy(policy.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func TestRunHostMaintenance(t *testing.T) {
+	client := newTestClient(t)
+	cluster := createTestCluster(t, client)
+	host := createTestHost(t, client, cluster.ID)
+	cleanupMaintenancePolicies(t, client, cluster.ID)
+
+	evacuator := &fakeEvacuator{vms: 2}
+	var steps []optimization.MaintenanceStep
+	opts := optimization.MaintenanceOptions{
+		Evacuator:    evacuator,
+		PollInterval: time.Millisecond,
+		Progress: func(event optimization.MaintenanceEvent) {
+			steps = append(steps, event.Step)
+		},
+	}
+
+	workRan := false
+	err := client.RunHostMaintenance(context.Background(), cluster.ID, host.ID, opts,
+		func(ctx context.Context, drained *optimization.ClusterHost) error {
+			workRan = true
+			if drained.Enabled {
+				t.Error("host is enabled during maintenance")
+			}
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("RunHostMaintenance: %v", err)
+	}
+
+	if !workRan {
+		t.Error("maintenance work was not called")
+	}
+	if len(evacuator.evacuated) != 1 || evacuator.evacuated[0] != host.Hostname {
+		t.Errorf("evacuated hosts = %v, want [%s]", evacuator.evacuated, host.Hostname)
+	}
+
+	want := []optimization.MaintenanceStep{
+		optimization.MaintenanceStepDisabled,
+		optimization.MaintenanceStepPolicy,
+		optimization.MaintenanceStepEvacuating,
+		optimization.MaintenanceStepWaiting,
+		optimization.MaintenanceStepWaiting,
+		optimization.MaintenanceStepDrained,
+		optimization.MaintenanceStepRestored,
+	}
+	if len(steps) != len(want) {
+		t.Fatalf("steps = %v, want %v", steps, want)
+	}
+	for i := range want {
+		if steps[i] != want[i] {
+			t.Fatalf("steps = %v, want %v", steps, want)
+		}
+	}
+
+	restored, err := client.GetClusterHost(cluster.ID, host.ID)
+	if err != nil {
+		t.Fatalf("GetClusterHost: %v", err)
+	}
+	if !restored.Enabled {
+		t.Error("host was not enabled after maintenance")
+	}
+
+	policies, err := client.ListHostMaintenancePolicies(&cluster.ID)
+	if err != nil || len(policies) != 1 || !policies[0].Enabled {
+		t.Fatalf("host maintenance policies = %+v, %v, want one enabled policy", policies, err)
+	}
+}
+
+func TestHostMaintenanceTimeout(t *testing.T) {
+	client := newTestClient(t)
+	cluster := createTestCluster(t, client)
+	host := createTestHost(t, client, cluster.ID)
+	cleanupMaintenancePolicies(t, client, cluster.ID)
+
+	_, err := client.StartHostMaintenance(context.Background(), cluster.ID, host.ID, optimization.MaintenanceOptions{
+		Evacuator:    &fakeEvacuator{vms: 3, stuck: true},
+		PollInterval: time.Millisecond,
+		Timeout:      20 * time.Millisecond,
+	})
+
+	var timeoutErr *optimization.MaintenanceTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("StartHostMaintenance returned %v, want a MaintenanceTimeoutError", err)
+	}
+	if timeoutErr.RemainingVMs != 3 {
+		t.Errorf("RemainingVMs = %d, want 3", timeoutErr.RemainingVMs)
+	}
+
+	live, err := client.GetClusterHost(cluster.ID, host.ID)
+	if err != nil {
+		t.Fatalf("GetClusterHost: %v", err)
+	}
+	if live.Enabled {
+		t.Error("host was enabled again after a failed drain")
+	}
+
+	// A deadline of the caller is not a maintenance timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = client.StartHostMaintenance(ctx, cluster.ID, host.ID, optimization.MaintenanceOptions{
+		Evacuator:    &fakeEvacuator{vms: 3, stuck: true},
+		PollInterval: time.Millisecond,
+	})
+	if !errors.Is(err, context.DeadlineExceeded) || errors.As(err, &timeoutErr) {
+		t.Errorf("StartHostMaintenance past the context deadline returned %v, want context.DeadlineExceeded", err)
+	}
+}
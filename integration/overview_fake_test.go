@@ -0,0 +1,25 @@
+//go:build !live
+
+package integration
+
+import (
+	"testing"
+)
+
+func TestGetClusterOverviewWithoutPolicyType(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.Disable("workload_consolidation")
+	client := newFakeClient(t, srv)
+	policy := createTestWorkloadBalancingPolicy(t, client)
+
+	overview, err := client.GetClusterOverview(policy.ClusterID)
+	if err != nil {
+		t.Fatalf("GetClusterOverview: %v", err)
+	}
+	if overview.WorkloadConsolidationPolicies == nil || len(overview.WorkloadConsolidationPolicies) != 0 {
+		t.Errorf("workload consolidation policies = %v, want an empty list", overview.WorkloadConsolidationPolicies)
+	}
+	if len(overview.WorkloadBalancingPolicies) != 1 || overview.Stats.Policies != 1 {
+		t.Errorf("overview = %+v, want the workload balancing policy", overview)
+	}
+}
@@ -0,0 +1,62 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+func TestGetClusterOverview(t *testing.T) {
+	client := newTestClient(t)
+	cluster := createTestCluster(t, client)
+
+	createTestHost(t, client, cluster.ID)
+	if _, err := client.CreateClusterHost(cluster.ID, &optimization.ClusterHostCreate{Hostname: "compute-02"}); err != nil {
+		t.Fatalf("CreateClusterHost: %v", err)
+	}
+	if _, err := client.CreateWorkloadConsolidationPolicy(&optimization.WorkloadConsolidationPolicyCreate{
+		ClusterID: cluster.ID,
+		Name:      "consolidation",
+		Period:    3600,
+		Enabled:   true,
+	}); err != nil {
+		t.Fatalf("CreateWorkloadConsolidationPolicy: %v", err)
+	}
+	if _, err := client.CreateHostMaintenancePolicy(&optimization.HostMaintenancePolicyCreate{
+		ClusterID: cluster.ID,
+		Name:      "maintenance",
+	}); err != nil {
+		t.Fatalf("CreateHostMaintenancePolicy: %v", err)
+	}
+
+	overview, err := client.GetClusterOverview(cluster.ID)
+	if err != nil {
+		t.Fatalf("GetClusterOverview: %v", err)
+	}
+
+	if overview.Cluster == nil || overview.Cluster.ID != cluster.ID {
+		t.Fatalf("overview cluster = %+v, want %s", overview.Cluster, cluster.ID)
+	}
+	want := optimization.ClusterOverviewStats{
+		Hosts:          2,
+		EnabledHosts:   1,
+		Policies:       2,
+		ActivePolicies: 1,
+	}
+	if overview.Stats != want {
+		t.Errorf("stats = %+v, want %+v", overview.Stats, want)
+	}
+	if len(overview.Errors) != 0 {
+		t.Errorf("errors = %v, want none", overview.Errors)
+	}
+}
+
+func TestGetClusterOverviewNotFound(t *testing.T) {
+	client := newTestClient(t)
+
+	_, err := client.GetClusterOverview("00000000-0000-4000-8000-000000000000")
+	if !common.IsNotFound(err) {
+		t.Fatalf("GetClusterOverview(unknown) returned %v, want a 404", err)
+	}
+}
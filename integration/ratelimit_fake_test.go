@@ -0,0 +1,102 @@
+//go:build !live
+
+package integration
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+func TestConcurrencyLimiterWithToken(t *testing.T) {
+	var mutex sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/clusters" {
+			http.NotFound(w, r)
+			return
+		}
+
+		mutex.Lock()
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		mutex.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+		w.Write([]byte(`[]`))
+
+		mutex.Lock()
+		inFlight--
+		mutex.Unlock()
+	}))
+	defer srv.Close()
+
+	client := optimization.NewClientWithToken(srv.URL, "token", optimization.ClientOptions{
+		ConcurrencyLimiter: common.NewConcurrencyLimiter(2),
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.ListClusters(); err != nil {
+				t.Errorf("ListClusters: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight > 2 {
+		t.Errorf("%d requests in flight, want at most 2", maxInFlight)
+	}
+}
+
+func TestRateLimitRetries(t *testing.T) {
+	var mutex sync.Mutex
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/clusters" {
+			http.NotFound(w, r)
+			return
+		}
+
+		mutex.Lock()
+		requests++
+		first := requests == 1
+		mutex.Unlock()
+
+		if first {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	client := optimization.NewClientWithToken(srv.URL, "token")
+	if _, err := client.ListClusters(); !common.IsTooManyRequests(err) {
+		t.Fatalf("ListClusters answered with 429 = %v, want a TooManyRequests error", err)
+	}
+
+	mutex.Lock()
+	requests = 0
+	mutex.Unlock()
+	client = optimization.NewClientWithToken(srv.URL, "token", optimization.ClientOptions{
+		RateLimiter:      common.NewRateLimiter(100, 10),
+		RateLimitRetries: 1,
+	})
+	started := time.Now()
+	if _, err := client.ListClusters(); err != nil {
+		t.Fatalf("ListClusters with a retry: %v", err)
+	}
+	if elapsed := time.Since(started); elapsed < time.Second {
+		t.Errorf("retry came after %s, want the Retry-After delay of 1s", elapsed)
+	}
+}
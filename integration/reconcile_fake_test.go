@@ -0,0 +1,190 @@
+//go:build !live
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/optimization"
+	"github.com/overwatch144/golang-safirclient/optimization/reconcile"
+)
+
+func TestReconcile(t *testing.T) {
+	base := reconcile.Spec{
+		Name:        "prod",
+		Description: "production",
+		Hosts: []reconcile.HostSpec{
+			{Hostname: "compute-01", Enabled: true},
+			{Hostname: "compute-02", Enabled: true},
+		},
+		ExcludedVMs: []string{"vm-01"},
+		WorkloadBalancingPolicies: []reconcile.WorkloadBalancingPolicySpec{
+			{Name: "balancing", BalancingMode: optimization.BalancingModeModerate, CPUBalancing: true, Period: 3600, Enabled: true},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		initial *reconcile.Spec
+		desired func(spec *reconcile.Spec)
+		creates int
+		updates int
+		deletes int
+		kinds   []optimization.ResourceKind
+	}{
+		{
+			name:    "create everything",
+			desired: func(spec *reconcile.Spec) {},
+			creates: 5,
+			kinds: []optimization.ResourceKind{
+				optimization.KindCluster,
+				optimization.KindHost,
+				optimization.KindHost,
+				optimization.KindExcludedVM,
+				optimization.KindWorkloadBalancingPolicy,
+			},
+		},
+		{
+			name:    "no changes",
+			initial: &base,
+			desired: func(spec *reconcile.Spec) {},
+		},
+		{
+			name:    "update fields",
+			initial: &base,
+			desired: func(spec *reconcile.Spec) {
+				spec.Description = "staging"
+				spec.Hosts[1].Enabled = false
+				spec.WorkloadBalancingPolicies[0].Period = 600
+			},
+			updates: 3,
+			kinds: []optimization.ResourceKind{
+				optimization.KindCluster,
+				optimization.KindHost,
+				optimization.KindWorkloadBalancingPolicy,
+			},
+		},
+		{
+			name:    "replace members",
+			initial: &base,
+			desired: func(spec *reconcile.Spec) {
+				spec.Hosts = []reconcile.HostSpec{{Hostname: "compute-01", Enabled: true}, {Hostname: "compute-03", Enabled: true}}
+				spec.ExcludedVMs = nil
+				spec.WorkloadBalancingPolicies = nil
+				spec.HostMaintenancePolicies = []reconcile.HostMaintenancePolicySpec{{Name: "maintenance", Enabled: true}}
+			},
+			creates: 2,
+			deletes: 3,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := newTestClient(t)
+			r := reconcile.New(client)
+
+			if test.initial != nil {
+				applySpec(t, r, copySpec(test.initial))
+			}
+
+			spec := copySpec(&base)
+			test.desired(spec)
+
+			plan, err := r.Plan(spec)
+			if err != nil {
+				t.Fatalf("Plan: %v", err)
+			}
+			if plan.Count(reconcile.ActionCreate) != test.creates ||
+				plan.Count(reconcile.ActionUpdate) != test.updates ||
+				plan.Count(reconcile.ActionDelete) != test.deletes {
+				t.Errorf("plan:\n%s want %d to create, %d to update, %d to delete", plan, test.creates, test.updates, test.deletes)
+			}
+			if test.kinds != nil {
+				for i, action := range plan.Actions {
+					if i >= len(test.kinds) || action.Kind != test.kinds[i] {
+						t.Errorf("action %d = %s, want kinds %v", i, action, test.kinds)
+					}
+				}
+			}
+
+			// A dry run changes nothing
+			if _, err := r.Apply(plan, reconcile.ApplyOptions{DryRun: true}); err != nil {
+				t.Fatalf("Apply(dry run): %v", err)
+			}
+			if replanned, err := r.Plan(spec); err != nil || len(replanned.Actions) != len(plan.Actions) {
+				t.Errorf("plan after a dry run = %v, %v", replanned, err)
+			}
+
+			applySpec(t, r, spec)
+		})
+	}
+}
+
+func TestReconcileInvalidSpec(t *testing.T) {
+	client := newTestClient(t)
+	r := reconcile.New(client)
+
+	spec := &reconcile.Spec{
+		Name:  "prod",
+		Hosts: []reconcile.HostSpec{{Hostname: "compute-01"}, {Hostname: "compute-01"}},
+	}
+	if _, err := r.Plan(spec); !common.IsValidationError(err) {
+		t.Errorf("Plan with duplicate hosts = %v, want a validation error", err)
+	}
+}
+
+func TestReconcileWithoutPolicyType(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.Disable("workload_consolidation")
+	r := reconcile.New(newFakeClient(t, srv))
+
+	// The policy type the deployment does not offer is left out of the plan
+	spec := &reconcile.Spec{
+		Name:  "prod",
+		Hosts: []reconcile.HostSpec{{Hostname: "compute-01", Enabled: true}},
+	}
+	applySpec(t, r, spec)
+
+	spec.Hosts[0].Enabled = false
+	plan, err := r.Plan(spec)
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	if plan.Count(reconcile.ActionUpdate) != 1 || len(plan.Actions) != 1 {
+		t.Errorf("plan =\n%s, want one host update", plan)
+	}
+}
+
+// applySpec plans and applies the spec and checks that the live state then
+// matches it
+func applySpec(t *testing.T, r *reconcile.Reconciler, spec *reconcile.Spec) {
+	t.Helper()
+
+	plan, err := r.Plan(spec)
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	if _, err := r.Apply(plan, reconcile.ApplyOptions{}); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	plan, err = r.Plan(spec)
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	if !plan.IsEmpty() {
+		t.Errorf("plan after apply is not empty:\n%s", plan)
+	}
+}
+
+// copySpec returns a copy of the spec that can be modified independently
+func copySpec(spec *reconcile.Spec) *reconcile.Spec {
+	c := *spec
+	c.Hosts = append([]reconcile.HostSpec(nil), spec.Hosts...)
+	c.ExcludedVMs = append([]string(nil), spec.ExcludedVMs...)
+	c.WorkloadBalancingPolicies = append([]reconcile.WorkloadBalancingPolicySpec(nil), spec.WorkloadBalancingPolicies...)
+	c.WorkloadConsolidationPolicies = append([]reconcile.WorkloadConsolidationPolicySpec(nil), spec.WorkloadConsolidationPolicies...)
+	c.HostMaintenancePolicies = append([]reconcile.HostMaintenancePolicySpec(nil), spec.HostMaintenancePolicies...)
+	return &c
+}
@@ -0,0 +1,60 @@
+//go:build !live
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/gophercloud/gophercloud/v2"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/fakesafir"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+// newTestAuthOptions starts a fake server and returns credentials for it
+func newTestAuthOptions(t *testing.T) *common.AuthOptions {
+	t.Helper()
+
+	return fakeAuthOptions(newFakeServer(t))
+}
+
+// newFakeServer starts a fake server that is closed when the test ends
+func newFakeServer(t *testing.T) *fakesafir.Server {
+	t.Helper()
+
+	srv := fakesafir.NewServer()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// newFakeClient returns a client of a fake server, e.g. one with some
+// collections disabled
+func newFakeClient(t *testing.T, srv *fakesafir.Server) *optimization.Client {
+	t.Helper()
+
+	auth, err := common.NewAuthenticator(fakeAuthOptions(srv))
+	if err != nil {
+		t.Fatalf("NewAuthenticator: %v", err)
+	}
+	client, err := optimization.NewClientWithAuthenticator(auth)
+	if err != nil {
+		t.Fatalf("NewClientWithAuthenticator: %v", err)
+	}
+	return client
+}
+
+// fakeAuthOptions returns credentials for a fake server
+func fakeAuthOptions(srv *fakesafir.Server) *common.AuthOptions {
+	return &common.AuthOptions{
+		IdentityEndpoint: srv.IdentityEndpoint(),
+		Username:         fakesafir.Username,
+		Password:         fakesafir.Password,
+		DomainID:         fakesafir.DomainID,
+		AllowReauth:      true,
+		Scope: &gophercloud.AuthScope{
+			ProjectName: fakesafir.ProjectName,
+			DomainID:    fakesafir.DomainID,
+		},
+	}
+}
@@ -0,0 +1,22 @@
+//go:build live
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/overwatch144/golang-safirclient/common"
+)
+
+// newTestAuthOptions returns the credentials of the cloud described by the
+// OS_* environment variables
+func newTestAuthOptions(t *testing.T) *common.AuthOptions {
+	t.Helper()
+
+	opts, err := common.AuthOptionsFromEnv()
+	if err != nil {
+		t.Fatalf("live tests need OS_* credentials: %v", err)
+	}
+
+	return opts
+}
@@ -0,0 +1,120 @@
+//go:build !live
+
+package integration
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+func TestForEachClusterHost(t *testing.T) {
+	client := newTestClient(t)
+	cluster := createTestCluster(t, client)
+
+	for i := 0; i < 20; i++ {
+		if _, err := client.CreateClusterHost(cluster.ID, &optimization.ClusterHostCreate{
+			Hostname: fmt.Sprintf("compute-%02d", i),
+			Enabled:  true,
+		}); err != nil {
+			t.Fatalf("CreateClusterHost: %v", err)
+		}
+	}
+
+	seen := 0
+	err := client.ForEachClusterHost(cluster.ID, func(host optimization.ClusterHost) error {
+		if host.ClusterID != cluster.ID || host.Hostname == "" {
+			t.Errorf("decoded host = %+v", host)
+		}
+		seen++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ForEachClusterHost: %v", err)
+	}
+	if seen != 20 {
+		t.Errorf("ForEachClusterHost visited %d hosts, want 20", seen)
+	}
+
+	// An error from the callback stops the iteration
+	stop := errors.New("stop")
+	seen = 0
+	err = client.ForEachClusterHost(cluster.ID, func(optimization.ClusterHost) error {
+		seen++
+		return stop
+	})
+	if err != stop || seen != 1 {
+		t.Errorf("stopped ForEachClusterHost = %v after %d hosts", err, seen)
+	}
+
+	// Lists larger than the maximum response size are refused
+	client.SetMaxResponseSize(512)
+	if _, err := client.ListClusterHosts(cluster.ID); !common.IsResponseTooLarge(err) {
+		t.Errorf("ListClusterHosts over the size limit = %v, want ResponseTooLargeError", err)
+	}
+}
+
+func TestErrorBodiesAreTruncated(t *testing.T) {
+	garbage := strings.Repeat("x", 100*common.MaxErrorBodySize)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/clusters":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "/api/v1/clusters/cluster":
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(garbage))
+	}))
+	defer srv.Close()
+
+	client := optimization.NewClientWithToken(srv.URL, "token")
+
+	_, err := client.ListClusters()
+	apiErr, ok := err.(*common.APIError)
+	if !ok || len(apiErr.Message) > common.MaxErrorBodySize {
+		t.Errorf("error for a large error body = %T with %d bytes", err, len(err.Error()))
+	}
+
+	_, err = client.GetCluster("cluster")
+	if err == nil || len(err.Error()) > 2*common.MaxErrorBodySize || !strings.Contains(err.Error(), "truncated") {
+		t.Errorf("error for a large malformed body has %d bytes: %v", len(err.Error()), err)
+	}
+}
+
+func TestTrailingDataIsRejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/clusters":
+			w.Write([]byte(`[{"id": "cluster"}] garbage`))
+		case "/api/v1/clusters/cluster":
+			w.Write([]byte(`{"id": "cluster"} {"id": "other"}`))
+		case "/api/v1/clusters/spaced":
+			w.Write([]byte("{\"id\": \"spaced\"}\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	client := optimization.NewClientWithToken(srv.URL, "token")
+
+	if _, err := client.ListClusters(); err == nil || !strings.Contains(err.Error(), "garbage") {
+		t.Errorf("list with trailing data = %v, want a parse error", err)
+	}
+	if _, err := client.GetCluster("cluster"); err == nil || !strings.Contains(err.Error(), "failed to parse") {
+		t.Errorf("cluster with trailing data = %v, want a parse error", err)
+	}
+
+	// Trailing whitespace is fine
+	if cluster, err := client.GetCluster("spaced"); err != nil || cluster.ID != "spaced" {
+		t.Errorf("cluster with a trailing newline = %+v, %v", cluster, err)
+	}
+}
@@ -0,0 +1,118 @@
+package integration
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+func TestCreateValidation(t *testing.T) {
+	client := newTestClient(t)
+
+	_, err := client.CreateWorkloadBalancingPolicy(&optimization.WorkloadBalancingPolicyCreate{
+		BalancingMode: "extreme",
+		Period:        -1,
+	})
+
+	var errs common.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("CreateWorkloadBalancingPolicy returned %v, want ValidationErrors", err)
+	}
+
+	fields := make(map[string]bool)
+	for _, fieldErr := range errs {
+		fields[fieldErr.Field] = true
+	}
+	for _, field := range []string{"cluster_id", "name", "balancing_mode", "period"} {
+		if !fields[field] {
+			t.Errorf("no validation error for %q in %v", field, err)
+		}
+	}
+}
+
+func TestEmptyIDValidation(t *testing.T) {
+	client := newTestClient(t)
+
+	// An empty ID would turn the request into one for the collection
+	for name, call := range map[string]func() error{
+		"GetCluster": func() error {
+			_, err := client.GetCluster("")
+			return err
+		},
+		"DeleteCluster": func() error { return client.DeleteCluster("") },
+		"ListClusterHosts": func() error {
+			_, err := client.ListClusterHosts("")
+			return err
+		},
+		"GetClusterHost": func() error {
+			_, err := client.GetClusterHost("cluster", "")
+			return err
+		},
+		"DeleteClusterExcludedVM": func() error { return client.DeleteClusterExcludedVM("", "vm") },
+		"GetWorkloadBalancingPolicy": func() error {
+			_, err := client.GetWorkloadBalancingPolicy(" ")
+			return err
+		},
+		"DeleteHostMaintenancePolicy": func() error { return client.DeleteHostMaintenancePolicy("") },
+	} {
+		if err := call(); !common.IsValidationError(err) {
+			t.Errorf("%s with an empty ID = %v, want a validation error", name, err)
+		}
+	}
+}
+
+func TestHostnameValidation(t *testing.T) {
+	client := newTestClient(t)
+	cluster := createTestCluster(t, client)
+
+	for _, hostname := range []string{"-compute", "compute_01", "compute..example.com"} {
+		_, err := client.CreateClusterHost(cluster.ID, &optimization.ClusterHostCreate{Hostname: hostname})
+		if !common.IsValidationError(err) {
+			t.Errorf("CreateClusterHost(%q) returned %v, want a validation error", hostname, err)
+		}
+	}
+
+	if _, err := client.UpdateClusterHost("", "host", &optimization.ClusterHostUpdate{}); !common.IsValidationError(err) {
+		t.Errorf("UpdateClusterHost with an empty cluster ID returned %v, want a validation error", err)
+	}
+}
+
+func TestBalancingModeJSON(t *testing.T) {
+	for _, mode := range optimization.BalancingModes() {
+		data, err := json.Marshal(mode)
+		if err != nil {
+			t.Fatalf("Marshal(%q): %v", mode, err)
+		}
+
+		var decoded optimization.BalancingMode
+		if err := json.Unmarshal(data, &decoded); err != nil || decoded != mode || decoded.Unknown() {
+			t.Errorf("round trip of %q gave %q (unknown %v, err %v)", mode, decoded, decoded.Unknown(), err)
+		}
+	}
+
+	// A mode unknown to the client is kept and encoded again as it is
+	var policy optimization.WorkloadBalancingPolicy
+	if err := json.Unmarshal([]byte(`{"balancing_mode": "extreme"}`), &policy); err != nil {
+		t.Fatalf("Unmarshal of an unknown mode: %v", err)
+	}
+	if !policy.BalancingMode.Unknown() {
+		t.Errorf("mode %q not reported as unknown", policy.BalancingMode)
+	}
+	data, err := json.Marshal(policy)
+	if err != nil || !strings.Contains(string(data), `"balancing_mode":"extreme"`) {
+		t.Errorf("Marshal of an unknown mode = %s, %v", data, err)
+	}
+
+	// but never sent in a request
+	extreme := optimization.BalancingMode("extreme")
+	if err := (&optimization.WorkloadBalancingPolicyUpdate{BalancingMode: &extreme}).Validate(); !common.IsValidationError(err) {
+		t.Errorf("Validate of an update to an unknown mode = %v, want a validation error", err)
+	}
+	if _, err := optimization.ParseBalancingMode("extreme"); err == nil {
+		t.Error("ParseBalancingMode accepted an unknown mode")
+	}
+}
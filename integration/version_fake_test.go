@@ -0,0 +1,186 @@
+//go:build !live
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/fakesafir"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+// newVersionedClient returns a base client of srv implementing the
+// microversions up to max, or pinned to a version
+func newVersionedClient(t *testing.T, srv *fakesafir.Server, max, pinned common.Microversion) *common.BaseClient {
+	t.Helper()
+
+	auth, err := common.NewAuthenticator(fakeAuthOptions(srv))
+	if err != nil {
+		t.Fatalf("NewAuthenticator: %v", err)
+	}
+
+	return common.NewBaseClient(common.BaseClientConfig{
+		Endpoint:        srv.OptimizationEndpoint() + "/api",
+		Authenticator:   auth,
+		ServiceType:     common.ServiceTypeOptimization,
+		MaxMicroversion: max,
+		Microversion:    pinned,
+	})
+}
+
+func TestGetVersion(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.SetMicroversions("1.0", "1.2")
+	client := newVersionedClient(t, srv, optimization.MaxMicroversion, common.Microversion{})
+
+	version, err := client.GetVersion()
+	if err != nil {
+		t.Fatalf("GetVersion: %v", err)
+	}
+	if version.ID != "v1" || version.Status != common.VersionStatusCurrent {
+		t.Errorf("version = %+v", version)
+	}
+	if version.MinVersion.String() != "1.0" || version.MaxVersion.String() != "1.2" {
+		t.Errorf("microversions = %s to %s, want 1.0 to 1.2", version.MinVersion, version.MaxVersion)
+	}
+	if !version.Supports(common.Microversion{Major: 1, Minor: 1}) || version.Supports(common.Microversion{Major: 1, Minor: 3}) {
+		t.Error("Supports does not match the advertised range")
+	}
+}
+
+func TestVersionNegotiation(t *testing.T) {
+	ctx := context.Background()
+	srv := newFakeServer(t)
+	srv.SetMicroversions("1.0", "1.2")
+
+	// The highest version both sides implement is selected
+	client := newVersionedClient(t, srv, common.Microversion{Major: 1, Minor: 3}, common.Microversion{})
+	version, err := client.Microversion(ctx)
+	if err != nil {
+		t.Fatalf("Microversion: %v", err)
+	}
+	if version.String() != "1.2" {
+		t.Errorf("negotiated %s, want 1.2", version)
+	}
+
+	resp, err := client.DoRequestWithContext(ctx, http.MethodGet, "/clusters", nil)
+	if err != nil {
+		t.Fatalf("request at the negotiated version: %v", err)
+	}
+	resp.Body.Close()
+
+	if err := client.RequireVersion(ctx, "ListSomething", common.Microversion{Major: 1, Minor: 1}); err != nil {
+		t.Errorf("RequireVersion 1.1: %v", err)
+	}
+	err = client.RequireVersion(ctx, "ListSomething", common.Microversion{Major: 1, Minor: 3})
+	if !common.IsUnsupportedVersion(err) || !common.IsNotSupported(err) {
+		t.Errorf("RequireVersion 1.3 = %v, want an unsupported version error", err)
+	}
+
+	// A client too old for the server fails before sending anything else
+	srv.SetMicroversions("1.3", "1.4")
+	old := newVersionedClient(t, srv, common.Microversion{Major: 1, Minor: 2}, common.Microversion{})
+	if _, err := old.DoRequestWithContext(ctx, http.MethodGet, "/clusters", nil); err == nil {
+		t.Error("request without a common version succeeded")
+	}
+
+	// A pinned version is sent as is
+	pinned := newVersionedClient(t, srv, common.Microversion{}, common.Microversion{Major: 1, Minor: 5})
+	if _, err := pinned.DoRequestWithContext(ctx, http.MethodGet, "/clusters", nil); !isStatus(err, http.StatusNotAcceptable) {
+		t.Errorf("request pinned to an unsupported version = %v, want 406", err)
+	}
+}
+
+func TestVersionNegotiationWithoutMicroversions(t *testing.T) {
+	ctx := context.Background()
+	srv := newFakeServer(t)
+
+	// A client at the base version does not negotiate at all
+	client := newVersionedClient(t, srv, common.BaseMicroversion, common.Microversion{})
+	if version, err := client.Microversion(ctx); err != nil || !version.IsZero() {
+		t.Errorf("Microversion = %s, %v, want none", version, err)
+	}
+	before := srv.Requests()
+	if _, err := client.DoRequestWithContext(ctx, http.MethodGet, "/clusters", nil); err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	if n := srv.Requests() - before; n != 1 {
+		t.Errorf("%d requests, want only the request itself", n)
+	}
+
+	// A newer client talks the base version to a server without
+	// microversions
+	client = newVersionedClient(t, srv, common.Microversion{Major: 1, Minor: 1}, common.Microversion{})
+	version, err := client.Microversion(ctx)
+	if err != nil {
+		t.Fatalf("Microversion: %v", err)
+	}
+	if version != common.BaseMicroversion {
+		t.Errorf("negotiated %s, want the base version", version)
+	}
+
+	err = client.RequireVersion(ctx, "ListSomething", common.Microversion{Major: 1, Minor: 1})
+	if !common.IsUnsupportedVersion(err) {
+		t.Errorf("RequireVersion 1.1 = %v, want an unsupported version error", err)
+	}
+}
+
+func TestVersionNegotiationFailure(t *testing.T) {
+	var discoveries int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/":
+			// Unavailable at first, then without a discovery document
+			discoveries++
+			if discoveries == 1 {
+				http.Error(w, "unavailable", http.StatusServiceUnavailable)
+				return
+			}
+			http.NotFound(w, r)
+		case "/api/v1/clusters":
+			if r.Header.Get(common.APIVersionHeader) != "" {
+				t.Errorf("request sent %s %q", common.APIVersionHeader, r.Header.Get(common.APIVersionHeader))
+			}
+			w.Write([]byte("[]"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	client := common.NewBaseClient(common.BaseClientConfig{
+		Endpoint:        srv.URL + "/api",
+		Authenticator:   common.NewTokenAuthenticator(srv.URL, "token"),
+		ServiceType:     common.ServiceTypeOptimization,
+		MaxMicroversion: common.Microversion{Major: 1, Minor: 1},
+	})
+	ctx := context.Background()
+
+	// A failed discovery fails the request and is retried with the next one
+	if _, err := client.DoRequestWithContext(ctx, http.MethodGet, "/clusters", nil); !isStatus(errors.Unwrap(err), http.StatusServiceUnavailable) {
+		t.Fatalf("request while discovery is unavailable = %v, want 503", err)
+	}
+
+	// Without a discovery document requests go out at the base version,
+	// and discovery is not retried
+	for i := 0; i < 2; i++ {
+		resp, err := client.DoRequestWithContext(ctx, http.MethodGet, "/clusters", nil)
+		if err != nil {
+			t.Fatalf("request without a discovery document: %v", err)
+		}
+		resp.Body.Close()
+	}
+	if discoveries != 2 {
+		t.Errorf("%d discovery requests, want 2", discoveries)
+	}
+}
+
+func isStatus(err error, status int) bool {
+	apiErr, ok := err.(*common.APIError)
+	return ok && apiErr.StatusCode == status
+}
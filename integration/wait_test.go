@@ -0,0 +1,153 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/migration"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+var fastWait = common.WaitOptions{Interval: time.Millisecond, Timeout: 5 * time.Second}
+
+func TestWaitForClusterDeleted(t *testing.T) {
+	client := newTestClient(t)
+	cluster := createTestCluster(t, client)
+
+	if err := client.DeleteCluster(cluster.ID); err != nil {
+		t.Fatalf("DeleteCluster: %v", err)
+	}
+	if err := client.WaitForClusterDeleted(context.Background(), cluster.ID, fastWait); err != nil {
+		t.Fatalf("WaitForClusterDeleted: %v", err)
+	}
+}
+
+func TestWaitForPolicyEnabled(t *testing.T) {
+	client := newTestClient(t)
+	cluster := createTestCluster(t, client)
+
+	policy, err := client.CreateHostMaintenancePolicy(&optimization.HostMaintenancePolicyCreate{
+		ClusterID: cluster.ID,
+		Name:      "maintenance",
+	})
+	if err != nil {
+		t.Fatalf("CreateHostMaintenancePolicy: %v", err)
+	}
+	cleanup(t, "host maintenance policy", func() error { return client.DeleteHostMaintenancePolicy(policy.ID) })
+
+	checks := 0
+	opts := fastWait
+	opts.Progress = func(progress common.WaitProgress) {
+		checks = progress.Attempt
+		if progress.Attempt == 3 {
+			if _, err := client.UpdateHostMaintenancePolicy(policy.ID, &optimization.HostMaintenancePolicyUpdate{
+				Enabled: common.Ptr(true),
+			}); err != nil {
+				t.Errorf("UpdateHostMaintenancePolicy: %v", err)
+			}
+		}
+	}
+
+	err = client.WaitForPolicyEnabled(context.Background(), optimization.PolicyTypeHostMaintenance, policy.ID, opts)
+	if err != nil {
+		t.Fatalf("WaitForPolicyEnabled: %v", err)
+	}
+	if checks != 3 {
+		t.Errorf("policy reported as enabled after %d pending checks, want 3", checks)
+	}
+}
+
+func TestWaitForTimeoutAndBackoff(t *testing.T) {
+	var delays []time.Duration
+	err := common.WaitForWithOptions(context.Background(), common.WaitOptions{
+		Interval:    time.Millisecond,
+		MaxInterval: 4 * time.Millisecond,
+		Timeout:     50 * time.Millisecond,
+		Progress: func(progress common.WaitProgress) {
+			delays = append(delays, progress.NextDelay)
+		},
+	}, func() (bool, error) { return false, nil })
+
+	var timeoutErr *common.WaitTimeoutError
+	if !errors.As(err, &timeoutErr) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WaitForWithOptions returned %v, want a timeout", err)
+	}
+
+	want := []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond, 4 * time.Millisecond}
+	if len(delays) < len(want) {
+		t.Fatalf("delays = %v, want at least %v", delays, want)
+	}
+	for i := range want {
+		if delays[i] != want[i] {
+			t.Fatalf("delays = %v, want them to start with %v", delays, want)
+		}
+	}
+}
+
+func TestWaitForStatus(t *testing.T) {
+	statuses := []string{"PENDING", "RUNNING", "SUCCEEDED"}
+	next := func() (string, error) {
+		status := statuses[0]
+		if len(statuses) > 1 {
+			statuses = statuses[1:]
+		}
+		return status, nil
+	}
+
+	status, err := common.WaitForStatus(context.Background(), fastWait, next, []string{"SUCCEEDED"}, []string{"FAILED"})
+	if err != nil || status != "SUCCEEDED" {
+		t.Fatalf("WaitForStatus = %q, %v, want SUCCEEDED", status, err)
+	}
+
+	failed := func() (string, error) { return "FAILED", nil }
+	_, err = common.WaitForStatus(context.Background(), fastWait, failed, []string{"SUCCEEDED"}, []string{"FAILED"})
+	var statusErr *common.StatusError
+	if !errors.As(err, &statusErr) || statusErr.Status != "FAILED" {
+		t.Fatalf("WaitForStatus returned %v, want a StatusError", err)
+	}
+}
+
+// fakeJobs advances a migration job by one status per check
+type fakeJobs struct {
+	statuses []string
+}
+
+func (j *fakeJobs) JobStatus(ctx context.Context, jobID string) (string, error) {
+	status := j.statuses[0]
+	if len(j.statuses) > 1 {
+		j.statuses = j.statuses[1:]
+	}
+	return status, nil
+}
+
+func TestWaitForMigration(t *testing.T) {
+	jobs := &fakeJobs{statuses: []string{"queued", "running", "completed"}}
+	status, err := migration.WaitForMigration(context.Background(), jobs, "job", []string{"completed"}, []string{"error"}, fastWait)
+	if err != nil || status != "completed" {
+		t.Fatalf("WaitForMigration = %q, %v, want completed", status, err)
+	}
+
+	jobs = &fakeJobs{statuses: []string{"running", "error"}}
+	_, err = migration.WaitForMigration(context.Background(), jobs, "job", []string{"completed"}, []string{"error"}, fastWait)
+	var statusErr *common.StatusError
+	if !errors.As(err, &statusErr) || statusErr.Status != "error" {
+		t.Fatalf("WaitForMigration of a failed job returned %v, want a StatusError", err)
+	}
+}
+
+func TestWaitForCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	err := common.WaitForWithOptions(ctx, fastWait, func() (bool, error) {
+		cancel()
+		return false, nil
+	})
+
+	// A cancelled wait did not time out
+	var timeoutErr *common.WaitTimeoutError
+	if err != context.Canceled || errors.As(err, &timeoutErr) {
+		t.Errorf("cancelled wait returned %v, want context.Canceled", err)
+	}
+}
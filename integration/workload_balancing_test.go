@@ -0,0 +1,72 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+func TestWorkloadBalancingPolicyCRUD(t *testing.T) {
+	client := newTestClient(t)
+	cluster := createTestCluster(t, client)
+
+	created, err := client.CreateWorkloadBalancingPolicy(&optimization.WorkloadBalancingPolicyCreate{
+		ClusterID:       cluster.ID,
+		Name:            "balancing",
+		BalancingMode:   optimization.BalancingModeModerate,
+		CPUBalancing:    true,
+		MemoryBalancing: true,
+		Period:          3600,
+		Enabled:         true,
+	})
+	if err != nil {
+		t.Fatalf("CreateWorkloadBalancingPolicy: %v", err)
+	}
+	cleanup(t, "workload balancing policy", func() error { return client.DeleteWorkloadBalancingPolicy(created.ID) })
+
+	if created.ID == "" || created.ClusterID != cluster.ID {
+		t.Fatalf("created policy = %+v", created)
+	}
+	if created.BalancingMode != optimization.BalancingModeModerate || created.Period != 3600 || !created.CPUBalancing || !created.MemoryBalancing {
+		t.Errorf("created policy = %+v", created)
+	}
+
+	got, err := client.GetWorkloadBalancingPolicy(created.ID)
+	if err != nil {
+		t.Fatalf("GetWorkloadBalancingPolicy: %v", err)
+	}
+	if got.Name != created.Name || got.Period != created.Period {
+		t.Errorf("GetWorkloadBalancingPolicy = %+v, want %+v", got, created)
+	}
+
+	policies, err := client.ListWorkloadBalancingPolicies(&cluster.ID)
+	if err != nil {
+		t.Fatalf("ListWorkloadBalancingPolicies: %v", err)
+	}
+	if len(policies) != 1 || policies[0].ID != created.ID {
+		t.Errorf("ListWorkloadBalancingPolicies(cluster) = %+v, want only policy %s", policies, created.ID)
+	}
+
+	updated, err := client.UpdateWorkloadBalancingPolicy(created.ID, &optimization.WorkloadBalancingPolicyUpdate{
+		Period:       common.Ptr(7200),
+		CPUBalancing: common.Ptr(false),
+	})
+	if err != nil {
+		t.Fatalf("UpdateWorkloadBalancingPolicy: %v", err)
+	}
+	if updated.Period != 7200 || updated.CPUBalancing {
+		t.Errorf("updated policy = %+v, want period 7200 without CPU balancing", updated)
+	}
+	if !updated.MemoryBalancing || updated.BalancingMode != optimization.BalancingModeModerate {
+		t.Errorf("update changed fields it did not set: %+v", updated)
+	}
+
+	if err := client.DeleteWorkloadBalancingPolicy(created.ID); err != nil {
+		t.Fatalf("DeleteWorkloadBalancingPolicy: %v", err)
+	}
+
+	if _, err := client.GetWorkloadBalancingPolicy(created.ID); !common.IsNotFound(err) {
+		t.Errorf("GetWorkloadBalancingPolicy after delete returned %v, want a 404", err)
+	}
+}
@@ -0,0 +1,63 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+func TestWorkloadConsolidationPolicyCRUD(t *testing.T) {
+	client := newTestClient(t)
+	cluster := createTestCluster(t, client)
+
+	created, err := client.CreateWorkloadConsolidationPolicy(&optimization.WorkloadConsolidationPolicyCreate{
+		ClusterID: cluster.ID,
+		Name:      "consolidation",
+		Period:    7200,
+		Enabled:   true,
+	})
+	if err != nil {
+		t.Fatalf("CreateWorkloadConsolidationPolicy: %v", err)
+	}
+	cleanup(t, "workload consolidation policy", func() error { return client.DeleteWorkloadConsolidationPolicy(created.ID) })
+
+	if created.ID == "" || created.ClusterID != cluster.ID || created.Period != 7200 || !created.Enabled {
+		t.Fatalf("created policy = %+v", created)
+	}
+
+	got, err := client.GetWorkloadConsolidationPolicy(created.ID)
+	if err != nil {
+		t.Fatalf("GetWorkloadConsolidationPolicy: %v", err)
+	}
+	if got.Name != created.Name {
+		t.Errorf("GetWorkloadConsolidationPolicy name = %q, want %q", got.Name, created.Name)
+	}
+
+	policies, err := client.ListWorkloadConsolidationPolicies(&cluster.ID)
+	if err != nil {
+		t.Fatalf("ListWorkloadConsolidationPolicies: %v", err)
+	}
+	if len(policies) != 1 || policies[0].ID != created.ID {
+		t.Errorf("ListWorkloadConsolidationPolicies(cluster) = %+v, want only policy %s", policies, created.ID)
+	}
+
+	updated, err := client.UpdateWorkloadConsolidationPolicy(created.ID, &optimization.WorkloadConsolidationPolicyUpdate{
+		Period:  common.Ptr(1800),
+		Enabled: common.Ptr(false),
+	})
+	if err != nil {
+		t.Fatalf("UpdateWorkloadConsolidationPolicy: %v", err)
+	}
+	if updated.Period != 1800 || updated.Enabled {
+		t.Errorf("updated policy = %+v, want period 1800 and disabled", updated)
+	}
+
+	if err := client.DeleteWorkloadConsolidationPolicy(created.ID); err != nil {
+		t.Fatalf("DeleteWorkloadConsolidationPolicy: %v", err)
+	}
+
+	if _, err := client.GetWorkloadConsolidationPolicy(created.ID); !common.IsNotFound(err) {
+		t.Errorf("GetWorkloadConsolidationPolicy after delete returned %v, want a 404", err)
+	}
+}
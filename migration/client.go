@@ -0,0 +1,107 @@
+package migration
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/overwatch144/golang-safirclient/common"
+)
+
+// Client represents the Safir Migration API client
+type Client struct {
+	*common.BaseClient
+}
+
+// ClientOptions represents client configuration options
+type ClientOptions struct {
+	AuthURL         string
+	Username        string
+	Password        string
+	ProjectName     string
+	ProjectDomainID string
+	UserDomainID    string
+	Region          string
+	AllowReauth     bool
+
+	// RateLimiter throttles outgoing requests; share one limiter between
+	// clients to give them a common budget
+	RateLimiter *common.RateLimiter
+	// ConcurrencyLimiter bounds the number of requests in flight
+	ConcurrencyLimiter *common.ConcurrencyLimiter
+	// RateLimitRetries is how many times a 429 response is retried
+	RateLimitRetries int
+	// Cache serves repeated reads from memory, see common.ResponseCache
+	Cache *common.ResponseCache
+	// MaxResponseSize bounds the size of response bodies (0 for no limit)
+	MaxResponseSize int64
+	// Compression configures compressed responses and request bodies
+	Compression common.CompressionConfig
+	// Transport performs the requests, defaults to http.DefaultTransport
+	Transport http.RoundTripper
+}
+
+// NewClient creates a new Safir Migration client
+func NewClient(opts ClientOptions) (*Client, error) {
+	authOpts := common.BuildAuthOptions(common.ClientOptions{
+		AuthURL:         opts.AuthURL,
+		Username:        opts.Username,
+		Password:        opts.Password,
+		ProjectName:     opts.ProjectName,
+		ProjectDomainID: opts.ProjectDomainID,
+		UserDomainID:    opts.UserDomainID,
+		Region:          opts.Region,
+		AllowReauth:     opts.AllowReauth,
+	})
+
+	auth, err := common.NewAuthenticator(authOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create authenticator: %w", err)
+	}
+
+	return NewClientWithAuthenticator(auth, opts)
+}
+
+// NewClientWithAuthenticator creates a client with existing authenticator.
+// The optional opts configure limiting, caching, compression and the
+// transport; their authentication fields are ignored.
+func NewClientWithAuthenticator(auth *common.Authenticator, opts ...ClientOptions) (*Client, error) {
+	endpoint, err := auth.GetEndpoint(common.ServiceTypeMigration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get migration endpoint: %w", err)
+	}
+
+	return newClient(endpoint, auth, opts), nil
+}
+
+// NewClientWithToken creates a new Migration client with existing token and
+// endpoint. The optional opts are applied as for NewClientWithAuthenticator.
+func NewClientWithToken(endpoint, token string, opts ...ClientOptions) *Client {
+	tokenAuth := common.NewTokenAuthenticator(endpoint, token)
+
+	return newClient(common.NormalizeEndpoint(endpoint), tokenAuth, opts)
+}
+
+// newClient creates the base client with /api/v1 prefix
+func newClient(endpoint string, auth interface{ GetToken() (string, error) }, opts []ClientOptions) *Client {
+	var o ClientOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	return &Client{
+		BaseClient: common.NewBaseClient(common.BaseClientConfig{
+			Endpoint:      endpoint + "/api",
+			Authenticator: auth,
+			ServiceType:   common.ServiceTypeMigration,
+			APIVersion:    "v1",
+
+			RateLimiter:        o.RateLimiter,
+			ConcurrencyLimiter: o.ConcurrencyLimiter,
+			RateLimitRetries:   o.RateLimitRetries,
+			Cache:              o.Cache,
+			MaxResponseSize:    o.MaxResponseSize,
+			Compression:        o.Compression,
+			Transport:          o.Transport,
+		}),
+	}
+}
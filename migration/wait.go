@@ -0,0 +1,23 @@
+package migration
+
+import (
+	"context"
+
+	"github.com/overwatch144/golang-safirclient/common"
+)
+
+// JobWatcher reports the status of migration jobs. Like
+// optimization.Evacuator, it is implemented with the migration backend of the
+// deployment.
+type JobWatcher interface {
+	JobStatus(ctx context.Context, jobID string) (string, error)
+}
+
+// WaitForMigration waits until the migration job reaches one of targets and
+// returns that status. Reaching one of failures ends the wait with a
+// common.StatusError.
+func WaitForMigration(ctx context.Context, jobs JobWatcher, jobID string, targets, failures []string, opts common.WaitOptions) (string, error) {
+	return common.WaitForStatus(ctx, opts, func() (string, error) {
+		return jobs.JobStatus(ctx, jobID)
+	}, targets, failures)
+}
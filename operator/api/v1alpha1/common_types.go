@@ -0,0 +1,60 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DeletionPolicy decides what happens to the Safir resource when its custom
+// resource is deleted
+// +kubebuilder:validation:Enum=Delete;Orphan
+type DeletionPolicy string
+
+// Deletion policies
+const (
+	// DeletionPolicyDelete deletes the Safir resource
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyOrphan leaves the Safir resource in place
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
+// ConditionReady reports whether the Safir resource matches the spec
+const ConditionReady = "Ready"
+
+// Reasons of the Ready condition
+const (
+	ReasonSynced          = "Synced"
+	ReasonSyncFailed      = "SyncFailed"
+	ReasonInvalidSpec     = "InvalidSpec"
+	ReasonClusterNotReady = "ClusterNotReady"
+	ReasonAlreadyManaged  = "AlreadyManaged"
+)
+
+// ClusterReference selects the Safir cluster of a policy. Exactly one of
+// name and id must be set.
+// +kubebuilder:validation:XValidation:rule="has(self.name) != has(self.id)",message="exactly one of name and id must be set"
+type ClusterReference struct {
+	// Name of a SafirCluster in the namespace of the policy
+	// +optional
+	Name string `json:"name,omitempty"`
+	// ID of a Safir cluster that is not managed by the operator
+	// +optional
+	ID string `json:"id,omitempty"`
+}
+
+// PolicyStatus is the observed state of a policy
+type PolicyStatus struct {
+	// ID of the policy in Safir
+	// +optional
+	ID string `json:"id,omitempty"`
+	// ClusterID is the Safir cluster the policy belongs to
+	// +optional
+	ClusterID string `json:"clusterID,omitempty"`
+	// ObservedGeneration is the generation of the spec last synced
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions of the policy
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
@@ -0,0 +1,20 @@
+// Package v1alpha1 contains the custom resources of the Safir operator
+// +kubebuilder:object:generate=true
+// +groupName=safir.openstack.org
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/scheme"
+)
+
+var (
+	// GroupVersion is the group and version of the custom resources
+	GroupVersion = schema.GroupVersion{Group: "safir.openstack.org", Version: "v1alpha1"}
+
+	// SchemeBuilder registers the custom resources with a scheme
+	SchemeBuilder = &scheme.Builder{GroupVersion: GroupVersion}
+
+	// AddToScheme adds the custom resources to a scheme
+	AddToScheme = SchemeBuilder.AddToScheme
+)
@@ -0,0 +1,67 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HostMaintenancePolicySpec is the desired state of a host maintenance policy
+type HostMaintenancePolicySpec struct {
+	// ClusterRef selects the cluster of the policy
+	ClusterRef ClusterReference `json:"clusterRef"`
+	// Name of the policy in Safir, defaults to the name of the resource. An
+	// existing policy of the cluster with this name is adopted unless another
+	// resource manages it.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Enabled policies are run by Safir
+	// +kubebuilder:default=true
+	Enabled bool `json:"enabled"`
+	// DeletionPolicy decides whether the Safir policy is deleted together
+	// with the resource
+	// +kubebuilder:default=Delete
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// HostMaintenancePolicy is a Safir Optimization host maintenance policy
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="ID",type=string,JSONPath=`.status.id`
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.status.clusterID`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type HostMaintenancePolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HostMaintenancePolicySpec `json:"spec,omitempty"`
+	Status PolicyStatus              `json:"status,omitempty"`
+}
+
+// SafirID returns the ID of the Safir policy recorded in the status
+func (p *HostMaintenancePolicy) SafirID() string {
+	return p.Status.ID
+}
+
+// SafirClusterID returns the ID of the Safir cluster recorded in the status
+func (p *HostMaintenancePolicy) SafirClusterID() string {
+	return p.Status.ClusterID
+}
+
+// Orphaned reports whether the Safir policy is kept when the resource is
+// deleted
+func (p *HostMaintenancePolicy) Orphaned() bool {
+	return p.Spec.DeletionPolicy == DeletionPolicyOrphan
+}
+
+// HostMaintenancePolicyList is a list of HostMaintenancePolicys
+// +kubebuilder:object:root=true
+type HostMaintenancePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HostMaintenancePolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HostMaintenancePolicy{}, &HostMaintenancePolicyList{})
+}
@@ -0,0 +1,94 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SafirClusterSpec is the desired state of a Safir cluster and its hosts
+type SafirClusterSpec struct {
+	// Name of the cluster in Safir, defaults to the name of the resource.
+	// An existing cluster with this name is adopted unless another
+	// SafirCluster manages it.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Description of the cluster
+	// +optional
+	Description string `json:"description,omitempty"`
+	// Hosts of the cluster. Hosts of the Safir cluster that are not listed
+	// are removed.
+	// +optional
+	// +listType=map
+	// +listMapKey=hostname
+	Hosts []SafirClusterHost `json:"hosts,omitempty"`
+	// DeletionPolicy decides whether the Safir cluster is deleted together
+	// with the resource. Deleting a cluster also deletes its policies, so a
+	// cluster with a policy whose deletion policy is Orphan is kept.
+	// +kubebuilder:default=Delete
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// SafirClusterHost is the desired state of a cluster host
+type SafirClusterHost struct {
+	// Hostname of the compute host
+	Hostname string `json:"hostname"`
+	// Enabled hosts take part in optimization
+	// +kubebuilder:default=true
+	Enabled bool `json:"enabled"`
+}
+
+// SafirClusterHostStatus is the observed state of a cluster host
+type SafirClusterHostStatus struct {
+	Hostname string `json:"hostname"`
+	ID       string `json:"id"`
+	Enabled  bool   `json:"enabled"`
+}
+
+// SafirClusterStatus is the observed state of a Safir cluster
+type SafirClusterStatus struct {
+	// ID of the cluster in Safir
+	// +optional
+	ID string `json:"id,omitempty"`
+	// Hosts of the cluster in Safir
+	// +optional
+	Hosts []SafirClusterHostStatus `json:"hosts,omitempty"`
+	// ObservedGeneration is the generation of the spec last synced
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions of the cluster
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// SafirCluster is a Safir Optimization cluster
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="ID",type=string,JSONPath=`.status.id`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type SafirCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SafirClusterSpec   `json:"spec,omitempty"`
+	Status SafirClusterStatus `json:"status,omitempty"`
+}
+
+// SafirID returns the ID of the Safir cluster recorded in the status
+func (c *SafirCluster) SafirID() string {
+	return c.Status.ID
+}
+
+// SafirClusterList is a list of SafirClusters
+// +kubebuilder:object:root=true
+type SafirClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SafirCluster `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SafirCluster{}, &SafirClusterList{})
+}
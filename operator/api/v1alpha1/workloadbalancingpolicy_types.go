@@ -0,0 +1,79 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WorkloadBalancingPolicySpec is the desired state of a workload balancing policy
+type WorkloadBalancingPolicySpec struct {
+	// ClusterRef selects the cluster of the policy
+	ClusterRef ClusterReference `json:"clusterRef"`
+	// Name of the policy in Safir, defaults to the name of the resource. An
+	// existing policy of the cluster with this name is adopted unless another
+	// resource manages it.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// BalancingMode is one of conservative, moderate or aggressive
+	// +kubebuilder:validation:Enum=conservative;moderate;aggressive
+	BalancingMode string `json:"balancingMode"`
+	// CPUBalancing balances CPU load
+	// +optional
+	CPUBalancing bool `json:"cpuBalancing,omitempty"`
+	// MemoryBalancing balances memory load
+	// +optional
+	MemoryBalancing bool `json:"memoryBalancing,omitempty"`
+	// Period between two runs, in seconds
+	// +kubebuilder:validation:Minimum=1
+	Period int32 `json:"period"`
+	// Enabled policies are run by Safir
+	// +kubebuilder:default=true
+	Enabled bool `json:"enabled"`
+	// DeletionPolicy decides whether the Safir policy is deleted together
+	// with the resource
+	// +kubebuilder:default=Delete
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// WorkloadBalancingPolicy is a Safir Optimization workload balancing policy
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="ID",type=string,JSONPath=`.status.id`
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.status.clusterID`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type WorkloadBalancingPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   WorkloadBalancingPolicySpec `json:"spec,omitempty"`
+	Status PolicyStatus                `json:"status,omitempty"`
+}
+
+// SafirID returns the ID of the Safir policy recorded in the status
+func (p *WorkloadBalancingPolicy) SafirID() string {
+	return p.Status.ID
+}
+
+// SafirClusterID returns the ID of the Safir cluster recorded in the status
+func (p *WorkloadBalancingPolicy) SafirClusterID() string {
+	return p.Status.ClusterID
+}
+
+// Orphaned reports whether the Safir policy is kept when the resource is
+// deleted
+func (p *WorkloadBalancingPolicy) Orphaned() bool {
+	return p.Spec.DeletionPolicy == DeletionPolicyOrphan
+}
+
+// WorkloadBalancingPolicyList is a list of WorkloadBalancingPolicys
+// +kubebuilder:object:root=true
+type WorkloadBalancingPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WorkloadBalancingPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&WorkloadBalancingPolicy{}, &WorkloadBalancingPolicyList{})
+}
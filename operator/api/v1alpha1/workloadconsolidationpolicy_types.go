@@ -0,0 +1,70 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WorkloadConsolidationPolicySpec is the desired state of a workload consolidation policy
+type WorkloadConsolidationPolicySpec struct {
+	// ClusterRef selects the cluster of the policy
+	ClusterRef ClusterReference `json:"clusterRef"`
+	// Name of the policy in Safir, defaults to the name of the resource. An
+	// existing policy of the cluster with this name is adopted unless another
+	// resource manages it.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Period between two runs, in seconds
+	// +kubebuilder:validation:Minimum=1
+	Period int32 `json:"period"`
+	// Enabled policies are run by Safir
+	// +kubebuilder:default=true
+	Enabled bool `json:"enabled"`
+	// DeletionPolicy decides whether the Safir policy is deleted together
+	// with the resource
+	// +kubebuilder:default=Delete
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// WorkloadConsolidationPolicy is a Safir Optimization workload consolidation policy
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="ID",type=string,JSONPath=`.status.id`
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.status.clusterID`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type WorkloadConsolidationPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   WorkloadConsolidationPolicySpec `json:"spec,omitempty"`
+	Status PolicyStatus                    `json:"status,omitempty"`
+}
+
+// SafirID returns the ID of the Safir policy recorded in the status
+func (p *WorkloadConsolidationPolicy) SafirID() string {
+	return p.Status.ID
+}
+
+// SafirClusterID returns the ID of the Safir cluster recorded in the status
+func (p *WorkloadConsolidationPolicy) SafirClusterID() string {
+	return p.Status.ClusterID
+}
+
+// Orphaned reports whether the Safir policy is kept when the resource is
+// deleted
+func (p *WorkloadConsolidationPolicy) Orphaned() bool {
+	return p.Spec.DeletionPolicy == DeletionPolicyOrphan
+}
+
+// WorkloadConsolidationPolicyList is a list of WorkloadConsolidationPolicys
+// +kubebuilder:object:root=true
+type WorkloadConsolidationPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WorkloadConsolidationPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&WorkloadConsolidationPolicy{}, &WorkloadConsolidationPolicyList{})
+}
@@ -0,0 +1,245 @@
+// Package controllers reconciles the Safir custom resources into Safir
+// Optimization.
+//
+// Every resource is matched to its Safir counterpart by the ID in its status
+// and, when that is missing or stale, by name, so that existing clusters and
+// policies are adopted instead of duplicated. A Safir resource is adopted by
+// at most one custom resource. A finalizer deletes the Safir resource
+// together with the custom resource unless its deletion policy is Orphan.
+// Resources are synced again every SyncPeriod to undo changes made outside of
+// Kubernetes.
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/operator/api/v1alpha1"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+// Finalizer keeps a custom resource until its Safir resource is deleted
+const Finalizer = "safir.openstack.org/finalizer"
+
+// DefaultSyncPeriod is the default interval between two syncs of a resource
+const DefaultSyncPeriod = 5 * time.Minute
+
+// clusterRetryInterval is how long a policy waits for its cluster
+const clusterRetryInterval = 15 * time.Second
+
+// clusterNotReadyError reports a policy whose SafirCluster has no Safir ID
+type clusterNotReadyError struct {
+	Name   string
+	Reason string
+}
+
+func (e *clusterNotReadyError) Error() string {
+	return fmt.Sprintf("SafirCluster %s %s", e.Name, e.Reason)
+}
+
+// alreadyManagedError reports a Safir resource found by name whose ID is
+// recorded in the status of another custom resource
+type alreadyManagedError struct {
+	ID      string
+	Manager string
+}
+
+func (e *alreadyManagedError) Error() string {
+	return fmt.Sprintf("Safir resource %s is already managed by %s; set a different name in the spec", e.ID, e.Manager)
+}
+
+// checkUnmanaged fails with an alreadyManagedError if a custom resource of
+// the list's kind other than obj records the Safir ID in its status, so that
+// resources with the same name in different namespaces do not adopt, and
+// later delete, the same Safir resource. A resource that keeps the ID it
+// already recorded is not checked.
+func checkUnmanaged(ctx context.Context, c client.Client, list client.ObjectList, obj interface {
+	client.Object
+	SafirID() string
+}, id string) error {
+	if id == obj.SafirID() {
+		return nil
+	}
+
+	if err := c.List(ctx, list); err != nil {
+		return err
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		other, ok := item.(interface {
+			client.Object
+			SafirID() string
+		})
+		if !ok || other.SafirID() != id || client.ObjectKeyFromObject(other) == client.ObjectKeyFromObject(obj) {
+			continue
+		}
+		return &alreadyManagedError{ID: id, Manager: client.ObjectKeyFromObject(other).String()}
+	}
+	return nil
+}
+
+// resolveClusterID returns the Safir ID of the cluster a policy refers to
+func resolveClusterID(ctx context.Context, c client.Client, namespace string, ref v1alpha1.ClusterReference) (string, error) {
+	switch {
+	case ref.Name != "" && ref.ID != "":
+		return "", &common.ValidationError{Field: "clusterRef", Message: "must not set both name and id"}
+	case ref.ID != "":
+		return ref.ID, nil
+	case ref.Name == "":
+		return "", &common.ValidationError{Field: "clusterRef", Message: "requires name or id"}
+	}
+
+	var cluster v1alpha1.SafirCluster
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, &cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", &clusterNotReadyError{Name: ref.Name, Reason: "does not exist"}
+		}
+		return "", err
+	}
+
+	switch {
+	case !cluster.DeletionTimestamp.IsZero():
+		return "", &clusterNotReadyError{Name: ref.Name, Reason: "is being deleted"}
+	case cluster.Status.ID == "":
+		return "", &clusterNotReadyError{Name: ref.Name, Reason: "has not been created in Safir yet"}
+	}
+	return cluster.Status.ID, nil
+}
+
+// safirName returns the name of the Safir resource: the name in the spec or
+// else the name of the custom resource
+func safirName(specName string, obj client.Object) string {
+	if specName != "" {
+		return specName
+	}
+	return obj.GetName()
+}
+
+// observe returns the live Safir resource by ID, falling back to find when
+// there is no ID or the resource with that ID is gone. It returns nil if
+// neither finds one.
+func observe[T any](id string, get func(string) (*T, error), find func() (*T, error)) (*T, error) {
+	if id != "" {
+		live, err := get(id)
+		if !common.IsNotFound(err) {
+			return live, err
+		}
+	}
+
+	live, err := find()
+	if common.IsNotFound(err) {
+		return nil, nil
+	}
+	return live, err
+}
+
+// addFinalizer makes sure the custom resource has the finalizer
+func addFinalizer(ctx context.Context, c client.Client, obj client.Object) error {
+	if !controllerutil.AddFinalizer(obj, Finalizer) {
+		return nil
+	}
+	return c.Update(ctx, obj)
+}
+
+// finalize deletes the Safir resource of a custom resource that is being
+// deleted, unless it is orphaned, and releases the custom resource
+func finalize(ctx context.Context, c client.Client, obj client.Object, id string, policy v1alpha1.DeletionPolicy, del func(string) error) error {
+	if !controllerutil.ContainsFinalizer(obj, Finalizer) {
+		return nil
+	}
+
+	if id != "" && policy != v1alpha1.DeletionPolicyOrphan {
+		if err := del(id); err != nil && !common.IsNotFound(err) {
+			return err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(obj, Finalizer)
+	return c.Update(ctx, obj)
+}
+
+// finish records the outcome of a sync in the status and decides when to
+// reconcile again. Invalid specs wait for the next change of the resource.
+func finish(ctx context.Context, c client.Client, obj client.Object, observedGeneration *int64, conditions *[]metav1.Condition, syncErr error, syncPeriod time.Duration) (ctrl.Result, error) {
+	*observedGeneration = obj.GetGeneration()
+	meta.SetStatusCondition(conditions, readyCondition(obj.GetGeneration(), syncErr))
+	if err := c.Status().Update(ctx, obj); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if syncPeriod <= 0 {
+		syncPeriod = DefaultSyncPeriod
+	}
+
+	var notReady *clusterNotReadyError
+	var managed *alreadyManagedError
+	switch {
+	case syncErr == nil, errors.As(syncErr, &managed):
+		return ctrl.Result{RequeueAfter: syncPeriod}, nil
+	case errors.As(syncErr, &notReady):
+		return ctrl.Result{RequeueAfter: clusterRetryInterval}, nil
+	case common.IsValidationError(syncErr):
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{}, syncErr
+}
+
+func readyCondition(generation int64, err error) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               v1alpha1.ConditionReady,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             v1alpha1.ReasonSynced,
+		Message:            "Safir resource matches the spec",
+	}
+	if err == nil {
+		return condition
+	}
+
+	var notReady *clusterNotReadyError
+	var managed *alreadyManagedError
+	condition.Status = metav1.ConditionFalse
+	condition.Message = err.Error()
+	switch {
+	case errors.As(err, &notReady):
+		condition.Reason = v1alpha1.ReasonClusterNotReady
+	case errors.As(err, &managed):
+		condition.Reason = v1alpha1.ReasonAlreadyManaged
+	case common.IsValidationError(err):
+		condition.Reason = v1alpha1.ReasonInvalidSpec
+	default:
+		condition.Reason = v1alpha1.ReasonSyncFailed
+	}
+	return condition
+}
+
+// SetupWithManager registers the reconcilers of all custom resources
+func SetupWithManager(mgr ctrl.Manager, safir *optimization.Client, syncPeriod time.Duration) error {
+	reconcilers := []interface{ SetupWithManager(ctrl.Manager) error }{
+		&SafirClusterReconciler{Client: mgr.GetClient(), Safir: safir, SyncPeriod: syncPeriod},
+		&WorkloadBalancingPolicyReconciler{Client: mgr.GetClient(), Safir: safir, SyncPeriod: syncPeriod},
+		&WorkloadConsolidationPolicyReconciler{Client: mgr.GetClient(), Safir: safir, SyncPeriod: syncPeriod},
+		&HostMaintenancePolicyReconciler{Client: mgr.GetClient(), Safir: safir, SyncPeriod: syncPeriod},
+	}
+	for _, r := range reconcilers {
+		if err := r.SetupWithManager(mgr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
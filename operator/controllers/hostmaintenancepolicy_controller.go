@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/overwatch144/golang-safirclient/operator/api/v1alpha1"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+// HostMaintenancePolicyReconciler reconciles HostMaintenancePolicys into Safir host maintenance policies
+type HostMaintenancePolicyReconciler struct {
+	client.Client
+	Safir *optimization.Client
+	// SyncPeriod is the interval between two syncs (default 5m)
+	SyncPeriod time.Duration
+}
+
+// +kubebuilder:rbac:groups=safir.openstack.org,resources=hostmaintenancepolicies,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=safir.openstack.org,resources=hostmaintenancepolicies/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=safir.openstack.org,resources=hostmaintenancepolicies/finalizers,verbs=update
+
+// Reconcile syncs one HostMaintenancePolicy
+func (r *HostMaintenancePolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var policy v1alpha1.HostMaintenancePolicy
+	if err := r.Get(ctx, req.NamespacedName, &policy); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !policy.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, finalize(ctx, r.Client, &policy, policy.Status.ID, policy.Spec.DeletionPolicy, r.Safir.DeleteHostMaintenancePolicy)
+	}
+
+	if err := addFinalizer(ctx, r.Client, &policy); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	err := r.sync(ctx, &policy)
+	return finish(ctx, r.Client, &policy, &policy.Status.ObservedGeneration, &policy.Status.Conditions, err, r.SyncPeriod)
+}
+
+func (r *HostMaintenancePolicyReconciler) sync(ctx context.Context, policy *v1alpha1.HostMaintenancePolicy) error {
+	clusterID, err := resolveClusterID(ctx, r.Client, policy.Namespace, policy.Spec.ClusterRef)
+	if err != nil {
+		return err
+	}
+
+	desired := optimization.HostMaintenancePolicyCreate{
+		ClusterID: clusterID,
+		Name:      safirName(policy.Spec.Name, policy),
+		Enabled:   policy.Spec.Enabled,
+	}
+
+	live, err := observe(policy.Status.ID, r.Safir.GetHostMaintenancePolicy, func() (*optimization.HostMaintenancePolicy, error) {
+		return r.Safir.FindHostMaintenancePolicyByName(&clusterID, desired.Name)
+	})
+	if err != nil {
+		return err
+	}
+	if live != nil {
+		if err := checkUnmanaged(ctx, r.Client, &v1alpha1.HostMaintenancePolicyList{}, policy, live.ID); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case live == nil:
+		live, err = r.Safir.CreateHostMaintenancePolicy(&desired)
+	case live.ClusterID != desired.ClusterID || live.Name != desired.Name || live.Enabled != desired.Enabled:
+		live, err = r.Safir.UpdateHostMaintenancePolicy(live.ID, &optimization.HostMaintenancePolicyUpdate{
+			ClusterID: &desired.ClusterID,
+			Name:      &desired.Name,
+			Enabled:   &desired.Enabled,
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	policy.Status.ID = live.ID
+	policy.Status.ClusterID = live.ClusterID
+	return nil
+}
+
+// policiesOfCluster requests a reconcile of the policies referring to a
+// SafirCluster, so that they follow it without waiting for their retry
+func (r *HostMaintenancePolicyReconciler) policiesOfCluster(ctx context.Context, cluster client.Object) []reconcile.Request {
+	var policies v1alpha1.HostMaintenancePolicyList
+	if err := r.List(ctx, &policies, client.InNamespace(cluster.GetNamespace())); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, policy := range policies.Items {
+		if policy.Spec.ClusterRef.Name == cluster.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&policy)})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager registers the reconciler with a manager
+func (r *HostMaintenancePolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.HostMaintenancePolicy{}).
+		Watches(&v1alpha1.SafirCluster{}, handler.EnqueueRequestsFromMapFunc(r.policiesOfCluster)).
+		Complete(r)
+}
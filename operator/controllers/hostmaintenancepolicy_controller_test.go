@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/overwatch144/golang-safirclient/operator/api/v1alpha1"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+func TestHostMaintenancePolicyAdoptsAndOrphans(t *testing.T) {
+	e := newTestEnv(t)
+	r := &HostMaintenancePolicyReconciler{Client: e.k8s, Safir: e.safir}
+
+	// A cluster and policy that exist in Safir only
+	cluster, err := e.safir.CreateCluster(&optimization.ClusterCreate{Name: "unmanaged"})
+	if err != nil {
+		t.Fatalf("CreateCluster: %v", err)
+	}
+	existing, err := e.safir.CreateHostMaintenancePolicy(&optimization.HostMaintenancePolicyCreate{
+		ClusterID: cluster.ID,
+		Name:      "maintenance",
+		Enabled:   false,
+	})
+	if err != nil {
+		t.Fatalf("CreateHostMaintenancePolicy: %v", err)
+	}
+
+	policy := &v1alpha1.HostMaintenancePolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: e.objectName("maintenance")},
+		Spec: v1alpha1.HostMaintenancePolicySpec{
+			ClusterRef:     v1alpha1.ClusterReference{ID: cluster.ID},
+			Name:           "maintenance",
+			Enabled:        true,
+			DeletionPolicy: v1alpha1.DeletionPolicyOrphan,
+		},
+	}
+	e.create(policy)
+	e.reconcile(r, policy)
+
+	checkReady(t, policy.Name, policy.Status.Conditions, v1alpha1.ReasonSynced)
+	if policy.Status.ID != existing.ID {
+		t.Errorf("status ID = %q, want the adopted policy %q", policy.Status.ID, existing.ID)
+	}
+	if live, _ := e.safir.GetHostMaintenancePolicy(existing.ID); !live.Enabled {
+		t.Error("adopted policy was not enabled")
+	}
+
+	e.delete(policy)
+	e.reconcile(r, policy)
+	if e.reload(policy) {
+		t.Error("HostMaintenancePolicy still exists after its finalizer ran")
+	}
+	if _, err := e.safir.GetHostMaintenancePolicy(existing.ID); err != nil {
+		t.Errorf("orphaned policy: %v", err)
+	}
+}
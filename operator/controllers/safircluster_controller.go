@@ -0,0 +1,187 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/operator/api/v1alpha1"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+// SafirClusterReconciler reconciles SafirClusters into Safir clusters and
+// their hosts
+type SafirClusterReconciler struct {
+	client.Client
+	Safir *optimization.Client
+	// SyncPeriod is the interval between two syncs (default 5m)
+	SyncPeriod time.Duration
+}
+
+// +kubebuilder:rbac:groups=safir.openstack.org,resources=safirclusters,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=safir.openstack.org,resources=safirclusters/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=safir.openstack.org,resources=safirclusters/finalizers,verbs=update
+
+// Reconcile syncs one SafirCluster
+func (r *SafirClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var cluster v1alpha1.SafirCluster
+	if err := r.Get(ctx, req.NamespacedName, &cluster); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// Deleting a cluster in Safir also removes its policies and hosts, so
+	// it is kept for policies that are to be orphaned
+	if !cluster.DeletionTimestamp.IsZero() {
+		deletionPolicy := cluster.Spec.DeletionPolicy
+		if cluster.Status.ID != "" && deletionPolicy != v1alpha1.DeletionPolicyOrphan {
+			orphaned, err := r.hasOrphanedPolicies(ctx, cluster.Status.ID)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if orphaned {
+				deletionPolicy = v1alpha1.DeletionPolicyOrphan
+			}
+		}
+
+		return ctrl.Result{}, finalize(ctx, r.Client, &cluster, cluster.Status.ID, deletionPolicy, func(id string) error {
+			_, err := r.Safir.DeleteClusterCascade(id, optimization.CascadeDeleteOptions{})
+			return err
+		})
+	}
+
+	if err := addFinalizer(ctx, r.Client, &cluster); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	err := r.sync(ctx, &cluster)
+	return finish(ctx, r.Client, &cluster, &cluster.Status.ObservedGeneration, &cluster.Status.Conditions, err, r.SyncPeriod)
+}
+
+func (r *SafirClusterReconciler) sync(ctx context.Context, cluster *v1alpha1.SafirCluster) error {
+	name := safirName(cluster.Spec.Name, cluster)
+
+	live, err := observe(cluster.Status.ID, r.Safir.GetCluster, func() (*optimization.Cluster, error) {
+		return r.Safir.FindClusterByName(name)
+	})
+	if err != nil {
+		return err
+	}
+	if live != nil {
+		if err := checkUnmanaged(ctx, r.Client, &v1alpha1.SafirClusterList{}, cluster, live.ID); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case live == nil:
+		live, err = r.Safir.CreateCluster(&optimization.ClusterCreate{
+			Name:        name,
+			Description: cluster.Spec.Description,
+		})
+	case live.Name != name || live.Description != cluster.Spec.Description:
+		live, err = r.Safir.UpdateCluster(live.ID, &optimization.ClusterUpdate{
+			Name:        &name,
+			Description: &cluster.Spec.Description,
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	cluster.Status.ID = live.ID
+	return r.syncHosts(cluster)
+}
+
+// hasOrphanedPolicies reports whether a policy resource of the Safir
+// cluster has the deletion policy Orphan
+func (r *SafirClusterReconciler) hasOrphanedPolicies(ctx context.Context, clusterID string) (bool, error) {
+	for _, list := range []client.ObjectList{
+		&v1alpha1.WorkloadBalancingPolicyList{},
+		&v1alpha1.WorkloadConsolidationPolicyList{},
+		&v1alpha1.HostMaintenancePolicyList{},
+	} {
+		if err := r.List(ctx, list); err != nil {
+			return false, err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return false, err
+		}
+
+		for _, item := range items {
+			policy, ok := item.(interface {
+				SafirClusterID() string
+				Orphaned() bool
+			})
+			if ok && policy.SafirClusterID() == clusterID && policy.Orphaned() {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// syncHosts creates, updates and deletes hosts until the Safir cluster has
+// exactly the hosts of the spec
+func (r *SafirClusterReconciler) syncHosts(cluster *v1alpha1.SafirCluster) error {
+	clusterID := cluster.Status.ID
+
+	hosts, err := r.Safir.ListClusterHosts(clusterID)
+	if err != nil {
+		return err
+	}
+
+	live := make(map[string]optimization.ClusterHost, len(hosts))
+	for _, host := range hosts {
+		live[host.Hostname] = host
+	}
+
+	cluster.Status.Hosts = nil
+	for _, desired := range cluster.Spec.Hosts {
+		host, ok := live[desired.Hostname]
+		delete(live, desired.Hostname)
+
+		var synced *optimization.ClusterHost
+		switch {
+		case !ok:
+			synced, err = r.Safir.CreateClusterHost(clusterID, &optimization.ClusterHostCreate{
+				Hostname: desired.Hostname,
+				Enabled:  desired.Enabled,
+			})
+		case host.Enabled != desired.Enabled:
+			synced, err = r.Safir.UpdateClusterHost(clusterID, host.ID, &optimization.ClusterHostUpdate{
+				Enabled: common.Ptr(desired.Enabled),
+			})
+		default:
+			synced = &host
+		}
+		if err != nil {
+			return err
+		}
+
+		cluster.Status.Hosts = append(cluster.Status.Hosts, v1alpha1.SafirClusterHostStatus{
+			Hostname: synced.Hostname,
+			ID:       synced.ID,
+			Enabled:  synced.Enabled,
+		})
+	}
+
+	for _, host := range live {
+		if err := r.Safir.DeleteClusterHost(clusterID, host.ID); err != nil && !common.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetupWithManager registers the reconciler with a manager
+func (r *SafirClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.SafirCluster{}).
+		Complete(r)
+}
@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/overwatch144/golang-safirclient/operator/api/v1alpha1"
+)
+
+// newSafirCluster returns a SafirCluster with two enabled hosts
+func newSafirCluster(e *testEnv) *v1alpha1.SafirCluster {
+	return &v1alpha1.SafirCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: e.objectName("cluster")},
+		Spec: v1alpha1.SafirClusterSpec{
+			Description: "managed by the operator",
+			Hosts: []v1alpha1.SafirClusterHost{
+				{Hostname: "compute-01", Enabled: true},
+				{Hostname: "compute-02", Enabled: true},
+			},
+		},
+	}
+}
+
+// createReadyCluster creates a SafirCluster and reconciles it into Safir
+func createReadyCluster(e *testEnv) *v1alpha1.SafirCluster {
+	e.t.Helper()
+
+	cluster := newSafirCluster(e)
+	e.create(cluster)
+	e.reconcile(&SafirClusterReconciler{Client: e.k8s, Safir: e.safir}, cluster)
+	if cluster.Status.ID == "" {
+		e.t.Fatalf("SafirCluster %s has no ID after reconcile", cluster.Name)
+	}
+	return cluster
+}
+
+func TestSafirClusterLifecycle(t *testing.T) {
+	e := newTestEnv(t)
+	r := &SafirClusterReconciler{Client: e.k8s, Safir: e.safir}
+
+	cluster := newSafirCluster(e)
+	e.create(cluster)
+	result := e.reconcile(r, cluster)
+
+	checkReady(t, cluster.Name, cluster.Status.Conditions, v1alpha1.ReasonSynced)
+	if result.RequeueAfter != DefaultSyncPeriod {
+		t.Errorf("RequeueAfter = %v, want the sync period", result.RequeueAfter)
+	}
+	if cluster.Status.ObservedGeneration != cluster.Generation {
+		t.Errorf("ObservedGeneration = %d, want %d", cluster.Status.ObservedGeneration, cluster.Generation)
+	}
+	live, err := e.safir.GetCluster(cluster.Status.ID)
+	if err != nil {
+		t.Fatalf("GetCluster: %v", err)
+	}
+	if live.Name != cluster.Name || live.Description != "managed by the operator" {
+		t.Errorf("Safir cluster = %+v", live)
+	}
+	if len(cluster.Status.Hosts) != 2 || e.srv.Count("hosts") != 2 {
+		t.Errorf("status hosts = %+v, Safir has %d hosts, want 2", cluster.Status.Hosts, e.srv.Count("hosts"))
+	}
+
+	// Drop one host, disable the other and change the description
+	cluster.Spec.Description = "changed"
+	cluster.Spec.Hosts = []v1alpha1.SafirClusterHost{{Hostname: "compute-01", Enabled: false}}
+	e.update(cluster)
+	e.reconcile(r, cluster)
+
+	hosts, err := e.safir.ListClusterHosts(cluster.Status.ID)
+	if err != nil {
+		t.Fatalf("ListClusterHosts: %v", err)
+	}
+	if len(hosts) != 1 || hosts[0].Hostname != "compute-01" || hosts[0].Enabled {
+		t.Errorf("Safir hosts after update = %+v", hosts)
+	}
+	if live, _ := e.safir.GetCluster(cluster.Status.ID); live.Description != "changed" {
+		t.Errorf("Safir description = %q", live.Description)
+	}
+
+	e.delete(cluster)
+	e.reconcile(r, cluster)
+	if e.srv.Count("clusters") != 0 || e.srv.Count("hosts") != 0 {
+		t.Errorf("Safir still has %d clusters and %d hosts", e.srv.Count("clusters"), e.srv.Count("hosts"))
+	}
+	if e.reload(cluster) {
+		t.Error("SafirCluster still exists after its finalizer ran")
+	}
+}
+
+func TestSafirClusterRecreatedAfterOutOfBandDelete(t *testing.T) {
+	e := newTestEnv(t)
+	r := &SafirClusterReconciler{Client: e.k8s, Safir: e.safir}
+	cluster := createReadyCluster(e)
+	oldID := cluster.Status.ID
+
+	if err := e.safir.DeleteCluster(oldID); err != nil {
+		t.Fatalf("DeleteCluster: %v", err)
+	}
+	e.reconcile(r, cluster)
+
+	if cluster.Status.ID == "" || cluster.Status.ID == oldID {
+		t.Errorf("ID after recreate = %q, old ID %q", cluster.Status.ID, oldID)
+	}
+	if e.srv.Count("clusters") != 1 {
+		t.Errorf("Safir has %d clusters, want 1", e.srv.Count("clusters"))
+	}
+}
+
+func TestSafirClusterNotAdoptedTwice(t *testing.T) {
+	e := newTestEnv(t)
+	r := &SafirClusterReconciler{Client: e.k8s, Safir: e.safir}
+	cluster := createReadyCluster(e)
+
+	// A resource of the same name in another namespace finds the same Safir
+	// cluster by name, which it must neither adopt nor delete
+	other := newSafirCluster(e)
+	other.Namespace = "other"
+	e.create(other)
+	e.reconcile(r, other)
+
+	checkReady(t, other.Name, other.Status.Conditions, v1alpha1.ReasonAlreadyManaged)
+	if other.Status.ID != "" || e.srv.Count("clusters") != 1 {
+		t.Errorf("status ID = %q with %d Safir clusters, want no ID and 1 cluster", other.Status.ID, e.srv.Count("clusters"))
+	}
+
+	e.delete(other)
+	e.reconcile(r, other)
+	if _, err := e.safir.GetCluster(cluster.Status.ID); err != nil {
+		t.Errorf("Safir cluster after deleting the other resource: %v", err)
+	}
+}
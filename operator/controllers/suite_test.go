@@ -0,0 +1,214 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/v2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/fakesafir"
+	"github.com/overwatch144/golang-safirclient/operator/api/v1alpha1"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+var (
+	scheme = runtime.NewScheme()
+
+	// envClient talks to the envtest API server. Without KUBEBUILDER_ASSETS
+	// the tests use the controller-runtime fake client instead, which does
+	// not enforce the CRD schemas.
+	envClient client.Client
+)
+
+func TestMain(m *testing.M) {
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		panic(err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		panic(err)
+	}
+
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		os.Exit(m.Run())
+	}
+
+	env := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "config", "crd", "bases")},
+		ErrorIfCRDPathMissing: true,
+	}
+	config, err := env.Start()
+	if err != nil {
+		panic(err)
+	}
+
+	envClient, err = client.New(config, client.Options{Scheme: scheme})
+	if err != nil {
+		panic(err)
+	}
+
+	code := m.Run()
+	if err := env.Stop(); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to stop envtest: %v\n", err)
+	}
+	os.Exit(code)
+}
+
+// testEnv is a Kubernetes client and a fake Safir for one test
+type testEnv struct {
+	t     *testing.T
+	ctx   context.Context
+	k8s   client.Client
+	srv   *fakesafir.Server
+	safir *optimization.Client
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+
+	srv := fakesafir.NewServer()
+	t.Cleanup(srv.Close)
+
+	auth, err := common.NewAuthenticator(&common.AuthOptions{
+		IdentityEndpoint: srv.IdentityEndpoint(),
+		Username:         fakesafir.Username,
+		Password:         fakesafir.Password,
+		DomainID:         fakesafir.DomainID,
+		AllowReauth:      true,
+		Scope: &gophercloud.AuthScope{
+			ProjectName: fakesafir.ProjectName,
+			DomainID:    fakesafir.DomainID,
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewAuthenticator: %v", err)
+	}
+	safir, err := optimization.NewClientWithAuthenticator(auth)
+	if err != nil {
+		t.Fatalf("NewClientWithAuthenticator: %v", err)
+	}
+
+	k8s := envClient
+	if k8s == nil {
+		k8s = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithStatusSubresource(&v1alpha1.SafirCluster{}, &v1alpha1.WorkloadBalancingPolicy{},
+				&v1alpha1.WorkloadConsolidationPolicy{}, &v1alpha1.HostMaintenancePolicy{}).
+			Build()
+	}
+
+	return &testEnv{t: t, ctx: context.Background(), k8s: k8s, srv: srv, safir: safir}
+}
+
+// objectName returns a name for a custom resource that is unique to the test,
+// since envtest shares its API server between tests
+func (e *testEnv) objectName(suffix string) string {
+	name := strings.ToLower(strings.TrimPrefix(e.t.Name(), "Test"))
+	return strings.NewReplacer("/", "-", "_", "-").Replace(name) + "-" + suffix
+}
+
+// create stores a custom resource, by default in the default namespace, and
+// deletes it when the test ends
+func (e *testEnv) create(obj client.Object) {
+	e.t.Helper()
+
+	if obj.GetNamespace() == "" {
+		obj.SetNamespace("default")
+	} else {
+		e.createNamespace(obj.GetNamespace())
+	}
+	if err := e.k8s.Create(e.ctx, obj); err != nil {
+		e.t.Fatalf("Create %s: %v", obj.GetName(), err)
+	}
+	e.t.Cleanup(func() {
+		// Drop the finalizer so that the resource does not outlive the test
+		if err := e.k8s.Get(e.ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			return
+		}
+		obj.SetFinalizers(nil)
+		_ = e.k8s.Update(e.ctx, obj)
+		_ = e.k8s.Delete(e.ctx, obj)
+	})
+}
+
+// createNamespace makes sure the namespace exists, which envtest requires
+func (e *testEnv) createNamespace(name string) {
+	e.t.Helper()
+
+	err := e.k8s.Create(e.ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		e.t.Fatalf("Create namespace %s: %v", name, err)
+	}
+}
+
+// reconcile runs r for obj and reloads obj
+func (e *testEnv) reconcile(r interface {
+	Reconcile(context.Context, ctrl.Request) (ctrl.Result, error)
+}, obj client.Object) ctrl.Result {
+	e.t.Helper()
+
+	key := client.ObjectKeyFromObject(obj)
+	result, err := r.Reconcile(e.ctx, ctrl.Request{NamespacedName: key})
+	if err != nil {
+		e.t.Fatalf("Reconcile %s: %v", key, err)
+	}
+	e.reload(obj)
+	return result
+}
+
+// reload reads obj again; it is left unchanged once it is gone
+func (e *testEnv) reload(obj client.Object) bool {
+	e.t.Helper()
+
+	err := e.k8s.Get(e.ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}, obj)
+	if client.IgnoreNotFound(err) != nil {
+		e.t.Fatalf("Get %s: %v", obj.GetName(), err)
+	}
+	return err == nil
+}
+
+// update writes a changed spec
+func (e *testEnv) update(obj client.Object) {
+	e.t.Helper()
+
+	if err := e.k8s.Update(e.ctx, obj); err != nil {
+		e.t.Fatalf("Update %s: %v", obj.GetName(), err)
+	}
+}
+
+// delete deletes obj; with the finalizer it only gets a deletion timestamp
+func (e *testEnv) delete(obj client.Object) {
+	e.t.Helper()
+
+	if err := e.k8s.Delete(e.ctx, obj); err != nil {
+		e.t.Fatalf("Delete %s: %v", obj.GetName(), err)
+	}
+}
+
+// checkReady fails the test unless the Ready condition has the reason
+func checkReady(t *testing.T, name string, conditions []metav1.Condition, reason string) {
+	t.Helper()
+
+	condition := meta.FindStatusCondition(conditions, v1alpha1.ConditionReady)
+	if condition == nil {
+		t.Fatalf("%s has no Ready condition", name)
+	}
+	if condition.Reason != reason {
+		t.Errorf("%s Ready reason = %s (%s), want %s", name, condition.Reason, condition.Message, reason)
+	}
+}
@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/operator/api/v1alpha1"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+func newWorkloadBalancingPolicy(e *testEnv, clusterName string) *v1alpha1.WorkloadBalancingPolicy {
+	return &v1alpha1.WorkloadBalancingPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: e.objectName("balancing")},
+		Spec: v1alpha1.WorkloadBalancingPolicySpec{
+			ClusterRef:    v1alpha1.ClusterReference{Name: clusterName},
+			BalancingMode: string(optimization.BalancingModeModerate),
+			CPUBalancing:  true,
+			Period:        3600,
+			Enabled:       true,
+		},
+	}
+}
+
+func TestWorkloadBalancingPolicyWaitsForCluster(t *testing.T) {
+	e := newTestEnv(t)
+	r := &WorkloadBalancingPolicyReconciler{Client: e.k8s, Safir: e.safir}
+
+	cluster := newSafirCluster(e)
+	policy := newWorkloadBalancingPolicy(e, cluster.Name)
+	e.create(policy)
+	result := e.reconcile(r, policy)
+
+	checkReady(t, policy.Name, policy.Status.Conditions, v1alpha1.ReasonClusterNotReady)
+	if result.RequeueAfter != clusterRetryInterval {
+		t.Errorf("RequeueAfter = %v, want %v", result.RequeueAfter, clusterRetryInterval)
+	}
+	if e.srv.Count("workload_balancing") != 0 {
+		t.Error("policy was created without a cluster")
+	}
+
+	e.create(cluster)
+	e.reconcile(&SafirClusterReconciler{Client: e.k8s, Safir: e.safir}, cluster)
+	requests := r.policiesOfCluster(e.ctx, cluster)
+	if len(requests) != 1 || requests[0].Name != policy.Name {
+		t.Errorf("policiesOfCluster = %v, want the policy", requests)
+	}
+
+	e.reconcile(r, policy)
+	checkReady(t, policy.Name, policy.Status.Conditions, v1alpha1.ReasonSynced)
+	if policy.Status.ClusterID != cluster.Status.ID {
+		t.Errorf("status cluster ID = %q, want %q", policy.Status.ClusterID, cluster.Status.ID)
+	}
+	live, err := e.safir.GetWorkloadBalancingPolicy(policy.Status.ID)
+	if err != nil {
+		t.Fatalf("GetWorkloadBalancingPolicy: %v", err)
+	}
+	if live.Name != policy.Name || live.Period != 3600 || !live.CPUBalancing || live.MemoryBalancing {
+		t.Errorf("Safir policy = %+v", live)
+	}
+}
+
+func TestWorkloadBalancingPolicyUndoesDrift(t *testing.T) {
+	e := newTestEnv(t)
+	r := &WorkloadBalancingPolicyReconciler{Client: e.k8s, Safir: e.safir}
+	cluster := createReadyCluster(e)
+
+	policy := newWorkloadBalancingPolicy(e, cluster.Name)
+	e.create(policy)
+	e.reconcile(r, policy)
+
+	// Changes made outside of Kubernetes are reverted
+	if _, err := e.safir.UpdateWorkloadBalancingPolicy(policy.Status.ID, &optimization.WorkloadBalancingPolicyUpdate{
+		Enabled: common.Ptr(false),
+		Period:  common.Ptr(60),
+	}); err != nil {
+		t.Fatalf("UpdateWorkloadBalancingPolicy: %v", err)
+	}
+	e.reconcile(r, policy)
+
+	live, err := e.safir.GetWorkloadBalancingPolicy(policy.Status.ID)
+	if err != nil {
+		t.Fatalf("GetWorkloadBalancingPolicy: %v", err)
+	}
+	if !live.Enabled || live.Period != 3600 {
+		t.Errorf("Safir policy after resync = %+v", live)
+	}
+
+	// Spec changes are applied
+	policy.Spec.BalancingMode = string(optimization.BalancingModeAggressive)
+	e.update(policy)
+	e.reconcile(r, policy)
+	if live, _ := e.safir.GetWorkloadBalancingPolicy(policy.Status.ID); live.BalancingMode != optimization.BalancingModeAggressive {
+		t.Errorf("Safir balancing mode = %q", live.BalancingMode)
+	}
+	if e.srv.Count("workload_balancing") != 1 {
+		t.Errorf("Safir has %d policies, want 1", e.srv.Count("workload_balancing"))
+	}
+
+	e.delete(policy)
+	e.reconcile(r, policy)
+	if e.srv.Count("workload_balancing") != 0 {
+		t.Error("Safir policy was not deleted with the resource")
+	}
+}
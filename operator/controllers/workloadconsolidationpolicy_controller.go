@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/overwatch144/golang-safirclient/operator/api/v1alpha1"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+// WorkloadConsolidationPolicyReconciler reconciles WorkloadConsolidationPolicys into Safir workload consolidation policies
+type WorkloadConsolidationPolicyReconciler struct {
+	client.Client
+	Safir *optimization.Client
+	// SyncPeriod is the interval between two syncs (default 5m)
+	SyncPeriod time.Duration
+}
+
+// +kubebuilder:rbac:groups=safir.openstack.org,resources=workloadconsolidationpolicies,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=safir.openstack.org,resources=workloadconsolidationpolicies/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=safir.openstack.org,resources=workloadconsolidationpolicies/finalizers,verbs=update
+
+// Reconcile syncs one WorkloadConsolidationPolicy
+func (r *WorkloadConsolidationPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var policy v1alpha1.WorkloadConsolidationPolicy
+	if err := r.Get(ctx, req.NamespacedName, &policy); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !policy.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, finalize(ctx, r.Client, &policy, policy.Status.ID, policy.Spec.DeletionPolicy, r.Safir.DeleteWorkloadConsolidationPolicy)
+	}
+
+	if err := addFinalizer(ctx, r.Client, &policy); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	err := r.sync(ctx, &policy)
+	return finish(ctx, r.Client, &policy, &policy.Status.ObservedGeneration, &policy.Status.Conditions, err, r.SyncPeriod)
+}
+
+func (r *WorkloadConsolidationPolicyReconciler) sync(ctx context.Context, policy *v1alpha1.WorkloadConsolidationPolicy) error {
+	clusterID, err := resolveClusterID(ctx, r.Client, policy.Namespace, policy.Spec.ClusterRef)
+	if err != nil {
+		return err
+	}
+
+	desired := optimization.WorkloadConsolidationPolicyCreate{
+		ClusterID: clusterID,
+		Name:      safirName(policy.Spec.Name, policy),
+		Period:    int(policy.Spec.Period),
+		Enabled:   policy.Spec.Enabled,
+	}
+
+	live, err := observe(policy.Status.ID, r.Safir.GetWorkloadConsolidationPolicy, func() (*optimization.WorkloadConsolidationPolicy, error) {
+		return r.Safir.FindWorkloadConsolidationPolicyByName(&clusterID, desired.Name)
+	})
+	if err != nil {
+		return err
+	}
+	if live != nil {
+		if err := checkUnmanaged(ctx, r.Client, &v1alpha1.WorkloadConsolidationPolicyList{}, policy, live.ID); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case live == nil:
+		live, err = r.Safir.CreateWorkloadConsolidationPolicy(&desired)
+	case live.ClusterID != desired.ClusterID || live.Name != desired.Name || live.Period != desired.Period || live.Enabled != desired.Enabled:
+		live, err = r.Safir.UpdateWorkloadConsolidationPolicy(live.ID, &optimization.WorkloadConsolidationPolicyUpdate{
+			ClusterID: &desired.ClusterID,
+			Name:      &desired.Name,
+			Period:    &desired.Period,
+			Enabled:   &desired.Enabled,
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	policy.Status.ID = live.ID
+	policy.Status.ClusterID = live.ClusterID
+	return nil
+}
+
+// policiesOfCluster requests a reconcile of the policies referring to a
+// SafirCluster, so that they follow it without waiting for their retry
+func (r *WorkloadConsolidationPolicyReconciler) policiesOfCluster(ctx context.Context, cluster client.Object) []reconcile.Request {
+	var policies v1alpha1.WorkloadConsolidationPolicyList
+	if err := r.List(ctx, &policies, client.InNamespace(cluster.GetNamespace())); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, policy := range policies.Items {
+		if policy.Spec.ClusterRef.Name == cluster.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&policy)})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager registers the reconciler with a manager
+func (r *WorkloadConsolidationPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.WorkloadConsolidationPolicy{}).
+		Watches(&v1alpha1.SafirCluster{}, handler.EnqueueRequestsFromMapFunc(r.policiesOfCluster)).
+		Complete(r)
+}
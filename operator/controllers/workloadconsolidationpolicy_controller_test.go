@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/operator/api/v1alpha1"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+func newWorkloadConsolidationPolicy(e *testEnv, clusterName string) *v1alpha1.WorkloadConsolidationPolicy {
+	return &v1alpha1.WorkloadConsolidationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: e.objectName("consolidation")},
+		Spec: v1alpha1.WorkloadConsolidationPolicySpec{
+			ClusterRef: v1alpha1.ClusterReference{Name: clusterName},
+			Period:     3600,
+			Enabled:    true,
+		},
+	}
+}
+
+func TestWorkloadConsolidationPolicyLifecycle(t *testing.T) {
+	e := newTestEnv(t)
+	r := &WorkloadConsolidationPolicyReconciler{Client: e.k8s, Safir: e.safir}
+	cluster := createReadyCluster(e)
+
+	policy := newWorkloadConsolidationPolicy(e, cluster.Name)
+	e.create(policy)
+	e.reconcile(r, policy)
+
+	checkReady(t, policy.Name, policy.Status.Conditions, v1alpha1.ReasonSynced)
+	if policy.Status.ClusterID != cluster.Status.ID {
+		t.Errorf("status cluster ID = %q, want %q", policy.Status.ClusterID, cluster.Status.ID)
+	}
+	live, err := e.safir.GetWorkloadConsolidationPolicy(policy.Status.ID)
+	if err != nil {
+		t.Fatalf("GetWorkloadConsolidationPolicy: %v", err)
+	}
+	if live.Name != policy.Name || live.Period != 3600 || !live.Enabled {
+		t.Errorf("Safir policy = %+v", live)
+	}
+
+	// Changes made outside of Kubernetes are reverted and spec changes are
+	// applied
+	if _, err := e.safir.UpdateWorkloadConsolidationPolicy(policy.Status.ID, &optimization.WorkloadConsolidationPolicyUpdate{
+		Enabled: common.Ptr(false),
+	}); err != nil {
+		t.Fatalf("UpdateWorkloadConsolidationPolicy: %v", err)
+	}
+	policy.Spec.Period = 600
+	e.update(policy)
+	e.reconcile(r, policy)
+
+	live, err = e.safir.GetWorkloadConsolidationPolicy(policy.Status.ID)
+	if err != nil {
+		t.Fatalf("GetWorkloadConsolidationPolicy: %v", err)
+	}
+	if !live.Enabled || live.Period != 600 {
+		t.Errorf("Safir policy after update = %+v", live)
+	}
+	if e.srv.Count("workload_consolidation") != 1 {
+		t.Errorf("Safir has %d policies, want 1", e.srv.Count("workload_consolidation"))
+	}
+
+	e.delete(policy)
+	e.reconcile(r, policy)
+	if e.srv.Count("workload_consolidation") != 0 {
+		t.Error("Safir policy was not deleted with the resource")
+	}
+	if e.reload(policy) {
+		t.Error("WorkloadConsolidationPolicy still exists after its finalizer ran")
+	}
+}
+
+func TestWorkloadConsolidationPolicyOrphanKeepsCluster(t *testing.T) {
+	e := newTestEnv(t)
+	r := &WorkloadConsolidationPolicyReconciler{Client: e.k8s, Safir: e.safir}
+	cluster := createReadyCluster(e)
+
+	policy := newWorkloadConsolidationPolicy(e, cluster.Name)
+	policy.Spec.DeletionPolicy = v1alpha1.DeletionPolicyOrphan
+	e.create(policy)
+	e.reconcile(r, policy)
+	checkReady(t, policy.Name, policy.Status.Conditions, v1alpha1.ReasonSynced)
+
+	// Deleting the cluster would delete the orphaned policy with it
+	e.delete(cluster)
+	e.reconcile(&SafirClusterReconciler{Client: e.k8s, Safir: e.safir}, cluster)
+	if e.reload(cluster) {
+		t.Error("SafirCluster still exists after its finalizer ran")
+	}
+	if _, err := e.safir.GetCluster(cluster.Status.ID); err != nil {
+		t.Errorf("Safir cluster with an orphaned policy: %v", err)
+	}
+	if _, err := e.safir.GetWorkloadConsolidationPolicy(policy.Status.ID); err != nil {
+		t.Errorf("orphaned policy: %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+// Command operator runs the controllers that reconcile the Safir custom
+// resources. It authenticates with the OS_* environment variables or, with
+// -os-cloud, an entry of clouds.yaml.
+package main
+
+//go:generate controller-gen object paths=./api/...
+//go:generate controller-gen crd rbac:roleName=safir-operator paths=./... output:crd:artifacts:config=config/crd/bases output:rbac:artifacts:config=config/rbac
+
+import (
+	"flag"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/operator/api/v1alpha1"
+	"github.com/overwatch144/golang-safirclient/operator/controllers"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch;create;update;patch;delete
+
+func main() {
+	var (
+		metricsAddr string
+		probeAddr   string
+		leaderElect bool
+		cloud       string
+		syncPeriod  = controllers.DefaultSyncPeriod
+		readyPeriod = time.Minute
+	)
+	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "address of the metrics endpoint")
+	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "address of the health probes")
+	flag.BoolVar(&leaderElect, "leader-elect", false, "elect a leader so that only one replica is active")
+	flag.StringVar(&cloud, "os-cloud", os.Getenv("OS_CLOUD"), "clouds.yaml entry to authenticate with")
+	flag.DurationVar(&syncPeriod, "sync-period", syncPeriod, "interval between two syncs of a resource")
+	flag.DurationVar(&readyPeriod, "ready-check-period", readyPeriod, "interval between two Safir health checks of the readiness probe")
+	opts := zap.Options{}
+	opts.BindFlags(flag.CommandLine)
+	flag.Parse()
+
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	log := ctrl.Log.WithName("setup")
+
+	safir, err := newSafirClient(cloud)
+	if err != nil {
+		log.Error(err, "failed to create Safir client")
+		os.Exit(1)
+	}
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		log.Error(err, "failed to build scheme")
+		os.Exit(1)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		log.Error(err, "failed to build scheme")
+		os.Exit(1)
+	}
+
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+		Scheme:                 scheme,
+		Metrics:                metricsserver.Options{BindAddress: metricsAddr},
+		HealthProbeBindAddress: probeAddr,
+		LeaderElection:         leaderElect,
+		LeaderElectionID:       "safir-operator.safir.openstack.org",
+	})
+	if err != nil {
+		log.Error(err, "failed to create manager")
+		os.Exit(1)
+	}
+
+	if err := controllers.SetupWithManager(mgr, safir, syncPeriod); err != nil {
+		log.Error(err, "failed to set up controllers")
+		os.Exit(1)
+	}
+
+	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
+		log.Error(err, "failed to add health check")
+		os.Exit(1)
+	}
+	// Not ready while Keystone or the Safir Optimization API is unavailable
+	if err := mgr.AddReadyzCheck("safir", cachedHealthCheck(safir, readyPeriod)); err != nil {
+		log.Error(err, "failed to add ready check")
+		os.Exit(1)
+	}
+
+	log.Info("starting manager")
+	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+		log.Error(err, "manager stopped")
+		os.Exit(1)
+	}
+}
+
+func newSafirClient(cloud string) (*optimization.Client, error) {
+	var authOpts *common.AuthOptions
+	var err error
+	if cloud != "" {
+		authOpts, err = common.AuthOptionsFromCloud(cloud)
+	} else {
+		authOpts, err = common.AuthOptionsFromEnv()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	auth, err := common.NewAuthenticator(authOpts)
+	if err != nil {
+		return nil, err
+	}
+	return optimization.NewClientWithAuthenticator(auth)
+}
+
+// cachedHealthCheck runs the Safir health check at most once per period and
+// answers the probes in between with its result, as a full check validates
+// the token with Keystone and probes every Safir endpoint
+func cachedHealthCheck(client *optimization.Client, period time.Duration) healthz.Checker {
+	var (
+		mutex   sync.Mutex
+		checked time.Time
+		result  error
+	)
+
+	return func(req *http.Request) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if !checked.IsZero() && time.Since(checked) < period {
+			return result
+		}
+
+		err := client.HealthCheck(req.Context()).Err()
+		// A probe that gave up says nothing about Safir
+		if req.Context().Err() == nil {
+			checked, result = time.Now(), err
+		}
+		return err
+	}
+}
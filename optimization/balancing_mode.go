@@ -0,0 +1,79 @@
+package optimization
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// BalancingMode controls how aggressively a workload balancing policy
+// migrates VMs. Create and update requests only accept the modes returned by
+// BalancingModes. Modes received from the server are kept as they are, even
+// if this client does not know them, and reported by Unknown.
+type BalancingMode string
+
+// Balancing modes supported by the Safir Optimization API
+const (
+	BalancingModeConservative BalancingMode = "conservative"
+	BalancingModeModerate     BalancingMode = "moderate"
+	BalancingModeAggressive   BalancingMode = "aggressive"
+)
+
+// BalancingModes returns the balancing modes supported by Safir
+func BalancingModes() []BalancingMode {
+	return []BalancingMode{
+		BalancingModeConservative,
+		BalancingModeModerate,
+		BalancingModeAggressive,
+	}
+}
+
+// ParseBalancingMode converts a string to a BalancingMode, rejecting
+// unsupported modes
+func ParseBalancingMode(s string) (BalancingMode, error) {
+	mode := BalancingMode(s)
+	if !mode.IsValid() {
+		return "", fmt.Errorf("unknown balancing mode %q (valid modes: %s)", s, joinBalancingModes())
+	}
+	return mode, nil
+}
+
+// IsValid reports whether the mode is supported by Safir
+func (m BalancingMode) IsValid() bool {
+	for _, mode := range BalancingModes() {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// Unknown reports whether the mode was received from the server but is not
+// known to this client, e.g. because the server is newer
+func (m BalancingMode) Unknown() bool {
+	return m != "" && !m.IsValid()
+}
+
+func (m BalancingMode) String() string {
+	return string(m)
+}
+
+// UnmarshalJSON accepts any mode; modes not known to this client are
+// preserved and reported by Unknown, and encoded again as they are
+func (m *BalancingMode) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("balancing mode must be a string: %w", err)
+	}
+	*m = BalancingMode(s)
+	return nil
+}
+
+func joinBalancingModes() string {
+	modes := BalancingModes()
+	names := make([]string, len(modes))
+	for i, mode := range modes {
+		names[i] = string(mode)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,153 @@
+package optimization
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultBulkConcurrency is the default number of parallel requests in bulk operations
+const DefaultBulkConcurrency = 4
+
+// ErrBulkSkipped is reported for items that were not attempted because an
+// earlier item failed and BulkOptions.StopOnError was set
+var ErrBulkSkipped = errors.New("skipped after an earlier failure")
+
+// BulkOptions controls how bulk operations are executed
+type BulkOptions struct {
+	// Concurrency is the maximum number of parallel requests (default 4)
+	Concurrency int
+	// StopOnError stops starting new items after the first failure.
+	// Without it every item is attempted (best effort).
+	StopOnError bool
+	// Rollback deletes every item created by the call if any item failed
+	Rollback bool
+}
+
+// BulkHostResult is the outcome of one item of BulkCreateClusterHosts
+type BulkHostResult struct {
+	Index       int
+	Request     ClusterHostCreate
+	Host        *ClusterHost
+	Err         error
+	RolledBack  bool
+	RollbackErr error
+}
+
+// BulkError summarizes a bulk operation in which at least one item failed
+type BulkError struct {
+	Total          int
+	Failed         int
+	Skipped        int
+	RolledBack     int
+	RollbackFailed int
+}
+
+func (e *BulkError) Error() string {
+	msg := fmt.Sprintf("bulk operation failed: %d of %d items failed", e.Failed, e.Total)
+	if e.Skipped > 0 {
+		msg += fmt.Sprintf(", %d skipped", e.Skipped)
+	}
+	if e.RolledBack > 0 || e.RollbackFailed > 0 {
+		msg += fmt.Sprintf(", %d rolled back", e.RolledBack)
+	}
+	if e.RollbackFailed > 0 {
+		msg += fmt.Sprintf(", %d rollbacks failed", e.RollbackFailed)
+	}
+	return msg
+}
+
+// BulkCreateClusterHosts adds several hosts to a cluster with bounded
+// concurrency. A result is returned for every request, in input order.
+// The error is a *BulkError if any item failed.
+func (c *Client) BulkCreateClusterHosts(clusterID string, hosts []ClusterHostCreate, opts BulkOptions) ([]BulkHostResult, error) {
+	results := make([]BulkHostResult, len(hosts))
+	for i, req := range hosts {
+		results[i] = BulkHostResult{Index: i, Request: req}
+	}
+
+	var stopped atomic.Bool
+	runBulk(len(hosts), opts.Concurrency, func(i int) {
+		if opts.StopOnError && stopped.Load() {
+			results[i].Err = ErrBulkSkipped
+			return
+		}
+
+		req := hosts[i]
+		host, err := c.CreateClusterHost(clusterID, &req)
+		if err != nil {
+			results[i].Err = err
+			stopped.Store(true)
+			return
+		}
+		results[i].Host = host
+	})
+
+	summary := &BulkError{Total: len(hosts)}
+	for _, result := range results {
+		switch {
+		case errors.Is(result.Err, ErrBulkSkipped):
+			summary.Skipped++
+		case result.Err != nil:
+			summary.Failed++
+		}
+	}
+
+	if summary.Failed == 0 {
+		return results, nil
+	}
+
+	if opts.Rollback {
+		runBulk(len(results), opts.Concurrency, func(i int) {
+			if results[i].Host == nil {
+				return
+			}
+			if err := c.DeleteClusterHost(clusterID, results[i].Host.ID); err != nil {
+				results[i].RollbackErr = err
+				return
+			}
+			results[i].RolledBack = true
+		})
+
+		for _, result := range results {
+			if result.RolledBack {
+				summary.RolledBack++
+			}
+			if result.RollbackErr != nil {
+				summary.RollbackFailed++
+			}
+		}
+	}
+
+	return results, summary
+}
+
+// runBulk calls fn for every index in [0, n) using at most concurrency
+// goroutines. Indexes are started in order.
+func runBulk(n, concurrency int, fn func(i int)) {
+	if concurrency <= 0 {
+		concurrency = DefaultBulkConcurrency
+	}
+	if concurrency > n {
+		concurrency = n
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
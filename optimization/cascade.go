@@ -0,0 +1,247 @@
+package optimization
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/overwatch144/golang-safirclient/common"
+)
+
+// Default cascade delete settings
+const (
+	DefaultCascadeRetries    = 2
+	DefaultCascadeRetryDelay = time.Second
+)
+
+// CascadeDeleteOptions controls DeleteClusterCascade
+type CascadeDeleteOptions struct {
+	// DryRun only lists what would be deleted
+	DryRun bool
+	// Concurrency is the maximum number of parallel deletes (default 4)
+	Concurrency int
+	// Retries is how many more times a failed delete is attempted (default 2,
+	// negative disables retries)
+	Retries int
+	// RetryDelay is the pause before the first retry, growing linearly (default 1s)
+	RetryDelay time.Duration
+}
+
+// CascadeItem is one resource removed by DeleteClusterCascade
+type CascadeItem struct {
+	Kind     ResourceKind `json:"kind"`
+	ID       string       `json:"id"`
+	Name     string       `json:"name"`
+	Deleted  bool         `json:"deleted"`
+	Attempts int          `json:"attempts,omitempty"`
+	Err      error        `json:"-"`
+}
+
+// MarshalJSON adds the error message, if any
+func (i CascadeItem) MarshalJSON() ([]byte, error) {
+	type plain CascadeItem
+	var message string
+	if i.Err != nil {
+		message = i.Err.Error()
+	}
+	return json.Marshal(struct {
+		plain
+		Error string `json:"error,omitempty"`
+	}{plain(i), message})
+}
+
+// CascadeDeleteResult lists the resources of a cascade delete in the order
+// they are removed: policies, then hosts and excluded VMs, then the cluster
+type CascadeDeleteResult struct {
+	ClusterID string        `json:"cluster_id"`
+	DryRun    bool          `json:"dry_run"`
+	Items     []CascadeItem `json:"items"`
+}
+
+// String renders the result as one line per resource, suitable as dry-run
+// output
+func (r *CascadeDeleteResult) String() string {
+	var b strings.Builder
+	for _, item := range r.Items {
+		state := "deleted"
+		switch {
+		case r.DryRun:
+			state = "would delete"
+		case errors.Is(item.Err, ErrBulkSkipped):
+			state = "skipped"
+		case item.Err != nil:
+			state = fmt.Sprintf("failed after %d attempts: %v", item.Attempts, item.Err)
+		}
+		fmt.Fprintf(&b, "%s %s %s (%s)\n", state, item.Kind, item.Name, item.ID)
+	}
+	return b.String()
+}
+
+// DeleteClusterCascade deletes a cluster together with its policies, hosts
+// and excluded VMs. Dependent resources are removed first, in parallel
+// within each stage, and failed deletes are retried. If a stage still has
+// failures the later stages, including the cluster itself, are skipped and
+// the error is a *BulkError. Resources that are already gone count as
+// deleted.
+func (c *Client) DeleteClusterCascade(clusterID string, opts CascadeDeleteOptions) (*CascadeDeleteResult, error) {
+	if err := requireID("cluster_id", clusterID); err != nil {
+		return nil, err
+	}
+
+	cluster, err := c.GetCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Listing must not miss resources created through other clients
+	stages, err := c.uncached().cascadeStages(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CascadeDeleteResult{ClusterID: clusterID, DryRun: opts.DryRun}
+	for _, stage := range stages {
+		result.Items = append(result.Items, stage...)
+	}
+	if opts.DryRun {
+		return result, nil
+	}
+
+	if opts.Retries == 0 {
+		opts.Retries = DefaultCascadeRetries
+	}
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = DefaultCascadeRetryDelay
+	}
+
+	summary := &BulkError{Total: len(result.Items)}
+	offset := 0
+	for _, stage := range stages {
+		items := result.Items[offset : offset+len(stage)]
+		offset += len(stage)
+
+		if summary.Failed > 0 {
+			for i := range items {
+				items[i].Err = ErrBulkSkipped
+				summary.Skipped++
+			}
+			continue
+		}
+
+		runBulk(len(items), opts.Concurrency, func(i int) {
+			c.cascadeDelete(clusterID, &items[i], opts)
+		})
+
+		for _, item := range items {
+			if item.Err != nil {
+				summary.Failed++
+			}
+		}
+	}
+
+	if summary.Failed > 0 {
+		return result, summary
+	}
+	return result, nil
+}
+
+// cascadeStages lists the dependent resources of a cluster in deletion order.
+// Policy types the deployment does not offer have no policies to delete.
+func (c *Client) cascadeStages(cluster *Cluster) ([][]CascadeItem, error) {
+	clusterID := cluster.ID
+	var policies []CascadeItem
+
+	balancing, err := emptyIfNotSupported(c.ListWorkloadBalancingPolicies(&clusterID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workload balancing policies: %w", err)
+	}
+	for _, policy := range balancing {
+		if policy.ClusterID == clusterID {
+			policies = append(policies, CascadeItem{Kind: KindWorkloadBalancingPolicy, ID: policy.ID, Name: policy.Name})
+		}
+	}
+
+	consolidation, err := emptyIfNotSupported(c.ListWorkloadConsolidationPolicies(&clusterID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workload consolidation policies: %w", err)
+	}
+	for _, policy := range consolidation {
+		if policy.ClusterID == clusterID {
+			policies = append(policies, CascadeItem{Kind: KindWorkloadConsolidationPolicy, ID: policy.ID, Name: policy.Name})
+		}
+	}
+
+	maintenance, err := emptyIfNotSupported(c.ListHostMaintenancePolicies(&clusterID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list host maintenance policies: %w", err)
+	}
+	for _, policy := range maintenance {
+		if policy.ClusterID == clusterID {
+			policies = append(policies, CascadeItem{Kind: KindHostMaintenancePolicy, ID: policy.ID, Name: policy.Name})
+		}
+	}
+
+	var members []CascadeItem
+
+	hosts, err := c.ListClusterHosts(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list hosts: %w", err)
+	}
+	for _, host := range hosts {
+		members = append(members, CascadeItem{Kind: KindHost, ID: host.ID, Name: host.Hostname})
+	}
+
+	vms, err := c.ListClusterExcludedVMs(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list excluded VMs: %w", err)
+	}
+	for _, vm := range vms {
+		members = append(members, CascadeItem{Kind: KindExcludedVM, ID: vm.ID, Name: vm.VMName})
+	}
+
+	return [][]CascadeItem{
+		policies,
+		members,
+		{{Kind: KindCluster, ID: cluster.ID, Name: cluster.Name}},
+	}, nil
+}
+
+// cascadeDelete deletes one item, retrying failures
+func (c *Client) cascadeDelete(clusterID string, item *CascadeItem, opts CascadeDeleteOptions) {
+	for {
+		item.Attempts++
+		err := c.deleteCascadeItem(clusterID, item)
+		if err == nil || common.IsNotFound(err) {
+			item.Deleted = true
+			item.Err = nil
+			return
+		}
+
+		item.Err = err
+		if item.Attempts > opts.Retries {
+			return
+		}
+		time.Sleep(time.Duration(item.Attempts) * opts.RetryDelay)
+	}
+}
+
+func (c *Client) deleteCascadeItem(clusterID string, item *CascadeItem) error {
+	switch item.Kind {
+	case KindWorkloadBalancingPolicy:
+		return c.DeleteWorkloadBalancingPolicy(item.ID)
+	case KindWorkloadConsolidationPolicy:
+		return c.DeleteWorkloadConsolidationPolicy(item.ID)
+	case KindHostMaintenancePolicy:
+		return c.DeleteHostMaintenancePolicy(item.ID)
+	case KindHost:
+		return c.DeleteClusterHost(clusterID, item.ID)
+	case KindExcludedVM:
+		return c.DeleteClusterExcludedVM(clusterID, item.ID)
+	case KindCluster:
+		return c.DeleteCluster(item.ID)
+	}
+
+	return fmt.Errorf("unknown resource kind %q", item.Kind)
+}
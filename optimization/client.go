@@ -1,11 +1,17 @@
 package optimization
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 
 	"github.com/overwatch144/golang-safirclient/common"
 )
 
+// MaxMicroversion is the highest microversion of the Safir Optimization API
+// this client implements. Versions are only negotiated once it is above 1.0.
+var MaxMicroversion = common.Microversion{Major: 1, Minor: 0}
+
 // Client represents the Safir Optimization API client
 type Client struct {
 	*common.BaseClient
@@ -21,6 +27,25 @@ type ClientOptions struct {
 	UserDomainID    string
 	Region          string
 	AllowReauth     bool
+
+	// RateLimiter throttles outgoing requests; share one limiter between
+	// clients to give them a common budget
+	RateLimiter *common.RateLimiter
+	// ConcurrencyLimiter bounds the number of requests in flight
+	ConcurrencyLimiter *common.ConcurrencyLimiter
+	// RateLimitRetries is how many times a 429 response is retried
+	RateLimitRetries int
+	// Cache serves repeated reads from memory, see common.ResponseCache
+	Cache *common.ResponseCache
+	// Microversion pins the API microversion; by default the highest one
+	// both the client and the server implement is negotiated
+	Microversion common.Microversion
+	// MaxResponseSize bounds the size of response bodies (0 for no limit)
+	MaxResponseSize int64
+	// Compression configures compressed responses and request bodies
+	Compression common.CompressionConfig
+	// Transport performs the requests, defaults to http.DefaultTransport
+	Transport http.RoundTripper
 }
 
 // NewClient creates a new Safir Optimization client
@@ -43,67 +68,78 @@ func NewClient(opts ClientOptions) (*Client, error) {
 		return nil, fmt.Errorf("failed to create authenticator: %w", err)
 	}
 
+	return NewClientWithAuthenticator(auth, opts)
+}
+
+// NewClientWithAuthenticator creates a client with existing authenticator.
+// The optional opts configure limiting, caching and the transport; their
+// authentication fields are ignored.
+func NewClientWithAuthenticator(auth *common.Authenticator, opts ...ClientOptions) (*Client, error) {
 	// Get Safir Optimization endpoint
 	endpoint, err := auth.GetEndpoint(common.ServiceTypeOptimization)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get optimization endpoint: %w", err)
 	}
 
-	// Create base client with /api/v1 prefix
-	baseConfig := common.BaseClientConfig{
-		Endpoint:      endpoint + "/api",
-		Authenticator: auth,
-		ServiceType:   common.ServiceTypeOptimization,
-		APIVersion:    "v1",
-	}
+	return newClient(endpoint, auth, opts), nil
+}
 
-	baseClient := common.NewBaseClient(baseConfig)
+// NewClientWithToken creates a new Optimization client with existing token and endpoint
+// Use this when you already have a valid token and know the endpoint. The
+// optional opts are applied as for NewClientWithAuthenticator.
+func NewClientWithToken(endpoint, token string, opts ...ClientOptions) *Client {
+	// Create token authenticator
+	tokenAuth := common.NewTokenAuthenticator(endpoint, token)
 
-	return &Client{
-		BaseClient: baseClient,
-	}, nil
+	return newClient(common.NormalizeEndpoint(endpoint), tokenAuth, opts)
 }
 
-// NewClientWithAuthenticator creates a client with existing authenticator
-func NewClientWithAuthenticator(auth *common.Authenticator) (*Client, error) {
-	// Get Safir Optimization endpoint
-	endpoint, err := auth.GetEndpoint(common.ServiceTypeOptimization)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get optimization endpoint: %w", err)
+// newClient creates the base client with /api/v1 prefix
+func newClient(endpoint string, auth interface{ GetToken() (string, error) }, opts []ClientOptions) *Client {
+	var o ClientOptions
+	if len(opts) > 0 {
+		o = opts[0]
 	}
 
-	// Create base client with /api/v1 prefix
 	baseConfig := common.BaseClientConfig{
 		Endpoint:      endpoint + "/api",
 		Authenticator: auth,
 		ServiceType:   common.ServiceTypeOptimization,
 		APIVersion:    "v1",
-	}
 
-	baseClient := common.NewBaseClient(baseConfig)
+		RateLimiter:        o.RateLimiter,
+		ConcurrencyLimiter: o.ConcurrencyLimiter,
+		RateLimitRetries:   o.RateLimitRetries,
+		Cache:              o.Cache,
+		MaxMicroversion:    MaxMicroversion,
+		Microversion:       o.Microversion,
+		MaxResponseSize:    o.MaxResponseSize,
+		Compression:        o.Compression,
+		Transport:          o.Transport,
+	}
 
 	return &Client{
-		BaseClient: baseClient,
-	}, nil
+		BaseClient: common.NewBaseClient(baseConfig),
+	}
 }
 
-// NewClientWithToken creates a new Optimization client with existing token and endpoint
-// Use this when you already have a valid token and know the endpoint
-func NewClientWithToken(endpoint, token string) *Client {
-	// Create token authenticator
-	tokenAuth := common.NewTokenAuthenticator(endpoint, token)
-
-	// Create base client with /api/v1 prefix
-	baseConfig := common.BaseClientConfig{
-		Endpoint:      common.NormalizeEndpoint(endpoint) + "/api",
-		Authenticator: tokenAuth,
-		ServiceType:   common.ServiceTypeOptimization,
-		APIVersion:    "v1",
-	}
+// uncached returns a client that bypasses the response cache, for reads
+// that must see the current server state
+func (c *Client) uncached() *Client {
+	return &Client{BaseClient: c.WithoutCache()}
+}
 
-	baseClient := common.NewBaseClient(baseConfig)
+// requireFeature fails fast with common.ErrNotSupported if the deployment
+// does not offer the feature
+func (c *Client) requireFeature(operation string, feature common.Feature) error {
+	return c.RequireFeature(context.Background(), operation, feature)
+}
 
-	return &Client{
-		BaseClient: baseClient,
+// emptyIfNotSupported turns the result of a list call for a feature the
+// deployment does not offer into an empty list
+func emptyIfNotSupported[T any](list []T, err error) ([]T, error) {
+	if common.IsNotSupported(err) {
+		return []T{}, nil
 	}
+	return list, err
 }
@@ -3,27 +3,52 @@ package optimization
 import (
 	"fmt"
 	"net/http"
+	"net/url"
+
+	"github.com/overwatch144/golang-safirclient/common"
 )
 
 // ListClusterExcludedVMs retrieves all excluded VMs for a specific cluster
 func (c *Client) ListClusterExcludedVMs(clusterID string) ([]ClusterExcludedVM, error) {
-	path := fmt.Sprintf("/clusters/%s/excluded-vms", clusterID)
-	resp, err := c.DoRequest(http.MethodGet, path, nil)
+	vms := []ClusterExcludedVM{}
+	err := c.ForEachClusterExcludedVM(clusterID, func(vm ClusterExcludedVM) error {
+		vms = append(vms, vm)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	var vms []ClusterExcludedVM
-	if err := c.ParseResponse(resp, &vms); err != nil {
-		return nil, err
+	return vms, nil
+}
+
+// ForEachClusterExcludedVM calls fn for each excluded VM of a cluster as it
+// is decoded from the response, see common.DecodeList. An error returned by
+// fn stops the iteration and is returned.
+func (c *Client) ForEachClusterExcludedVM(clusterID string, fn func(ClusterExcludedVM) error) error {
+	if err := requireID("cluster_id", clusterID); err != nil {
+		return err
 	}
 
-	return vms, nil
+	path := fmt.Sprintf("/clusters/%s/excluded-vms", url.PathEscape(clusterID))
+	resp, err := c.DoRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+
+	return common.DecodeList(resp, fn)
 }
 
 // GetClusterExcludedVM retrieves a specific excluded VM by ID
 func (c *Client) GetClusterExcludedVM(clusterID, vmID string) (*ClusterExcludedVM, error) {
-	path := fmt.Sprintf("/clusters/%s/excluded-vms/%s", clusterID, vmID)
+	if err := requireID("cluster_id", clusterID); err != nil {
+		return nil, err
+	}
+	if err := requireID("vm_id", vmID); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/clusters/%s/excluded-vms/%s", url.PathEscape(clusterID), url.PathEscape(vmID))
 	resp, err := c.DoRequest(http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
@@ -39,7 +64,14 @@ func (c *Client) GetClusterExcludedVM(clusterID, vmID string) (*ClusterExcludedV
 
 // CreateClusterExcludedVM adds a VM to the excluded list
 func (c *Client) CreateClusterExcludedVM(clusterID string, req *ClusterExcludedVMCreate) (*ClusterExcludedVM, error) {
-	path := fmt.Sprintf("/clusters/%s/excluded-vms", clusterID)
+	if err := requireID("cluster_id", clusterID); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/clusters/%s/excluded-vms", url.PathEscape(clusterID))
 	resp, err := c.DoRequest(http.MethodPost, path, req)
 	if err != nil {
 		return nil, err
@@ -55,7 +87,14 @@ func (c *Client) CreateClusterExcludedVM(clusterID string, req *ClusterExcludedV
 
 // DeleteClusterExcludedVM removes a VM from the excluded list
 func (c *Client) DeleteClusterExcludedVM(clusterID, vmID string) error {
-	path := fmt.Sprintf("/clusters/%s/excluded-vms/%s", clusterID, vmID)
+	if err := requireID("cluster_id", clusterID); err != nil {
+		return err
+	}
+	if err := requireID("vm_id", vmID); err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/clusters/%s/excluded-vms/%s", url.PathEscape(clusterID), url.PathEscape(vmID))
 	resp, err := c.DoRequest(http.MethodDelete, path, nil)
 	if err != nil {
 		return err
@@ -1,29 +1,55 @@
 package optimization
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"net/url"
+
+	"github.com/overwatch144/golang-safirclient/common"
 )
 
 // ListClusterHosts retrieves all hosts for a specific cluster
 func (c *Client) ListClusterHosts(clusterID string) ([]ClusterHost, error) {
-	path := fmt.Sprintf("/clusters/%s/hosts", clusterID)
-	resp, err := c.DoRequest(http.MethodGet, path, nil)
+	hosts := []ClusterHost{}
+	err := c.ForEachClusterHost(clusterID, func(host ClusterHost) error {
+		hosts = append(hosts, host)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	var hosts []ClusterHost
-	if err := c.ParseResponse(resp, &hosts); err != nil {
-		return nil, err
+	return hosts, nil
+}
+
+// ForEachClusterHost calls fn for each host of a cluster as it is decoded
+// from the response, see common.DecodeList. An error returned by fn stops
+// the iteration and is returned.
+func (c *Client) ForEachClusterHost(clusterID string, fn func(ClusterHost) error) error {
+	if err := requireID("cluster_id", clusterID); err != nil {
+		return err
 	}
 
-	return hosts, nil
+	path := fmt.Sprintf("/clusters/%s/hosts", url.PathEscape(clusterID))
+	resp, err := c.DoRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+
+	return common.DecodeList(resp, fn)
 }
 
 // GetClusterHost retrieves a specific host by ID
 func (c *Client) GetClusterHost(clusterID, hostID string) (*ClusterHost, error) {
-	path := fmt.Sprintf("/clusters/%s/hosts/%s", clusterID, hostID)
+	if err := requireID("cluster_id", clusterID); err != nil {
+		return nil, err
+	}
+	if err := requireID("host_id", hostID); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/clusters/%s/hosts/%s", url.PathEscape(clusterID), url.PathEscape(hostID))
 	resp, err := c.DoRequest(http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
@@ -34,12 +60,20 @@ func (c *Client) GetClusterHost(clusterID, hostID string) (*ClusterHost, error)
 		return nil, err
 	}
 
+	host.ETag = resp.Header.Get("ETag")
 	return &host, nil
 }
 
 // CreateClusterHost adds a new host to a cluster
 func (c *Client) CreateClusterHost(clusterID string, req *ClusterHostCreate) (*ClusterHost, error) {
-	path := fmt.Sprintf("/clusters/%s/hosts", clusterID)
+	if err := requireID("cluster_id", clusterID); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/clusters/%s/hosts", url.PathEscape(clusterID))
 	resp, err := c.DoRequest(http.MethodPost, path, req)
 	if err != nil {
 		return nil, err
@@ -55,8 +89,23 @@ func (c *Client) CreateClusterHost(clusterID string, req *ClusterHostCreate) (*C
 
 // UpdateClusterHost updates a cluster host
 func (c *Client) UpdateClusterHost(clusterID, hostID string, req *ClusterHostUpdate) (*ClusterHost, error) {
-	path := fmt.Sprintf("/clusters/%s/hosts/%s", clusterID, hostID)
-	resp, err := c.DoRequest(http.MethodPut, path, req)
+	return c.updateClusterHost(clusterID, hostID, req, nil)
+}
+
+// updateClusterHost performs the update, sending the given extra headers
+func (c *Client) updateClusterHost(clusterID, hostID string, req *ClusterHostUpdate, header http.Header) (*ClusterHost, error) {
+	if err := requireID("cluster_id", clusterID); err != nil {
+		return nil, err
+	}
+	if err := requireID("host_id", hostID); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/clusters/%s/hosts/%s", url.PathEscape(clusterID), url.PathEscape(hostID))
+	resp, err := c.DoRequestWithHeaders(context.Background(), http.MethodPut, path, req, header)
 	if err != nil {
 		return nil, err
 	}
@@ -66,12 +115,20 @@ func (c *Client) UpdateClusterHost(clusterID, hostID string, req *ClusterHostUpd
 		return nil, err
 	}
 
+	response.Host.ETag = resp.Header.Get("ETag")
 	return &response.Host, nil
 }
 
 // DeleteClusterHost removes a host from a cluster
 func (c *Client) DeleteClusterHost(clusterID, hostID string) error {
-	path := fmt.Sprintf("/clusters/%s/hosts/%s", clusterID, hostID)
+	if err := requireID("cluster_id", clusterID); err != nil {
+		return err
+	}
+	if err := requireID("host_id", hostID); err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/clusters/%s/hosts/%s", url.PathEscape(clusterID), url.PathEscape(hostID))
 	resp, err := c.DoRequest(http.MethodDelete, path, nil)
 	if err != nil {
 		return err
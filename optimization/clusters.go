@@ -1,28 +1,47 @@
 package optimization
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"net/url"
+
+	"github.com/overwatch144/golang-safirclient/common"
 )
 
 // ListClusters retrieves all clusters
 func (c *Client) ListClusters() ([]Cluster, error) {
-	resp, err := c.DoRequest(http.MethodGet, "/clusters", nil)
+	clusters := []Cluster{}
+	err := c.ForEachCluster(func(cluster Cluster) error {
+		clusters = append(clusters, cluster)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	var clusters []Cluster
-	if err := c.ParseResponse(resp, &clusters); err != nil {
-		return nil, err
+	return clusters, nil
+}
+
+// ForEachCluster calls fn for each cluster as it is decoded from the
+// response, see common.DecodeList. An error returned by fn stops the
+// iteration and is returned.
+func (c *Client) ForEachCluster(fn func(Cluster) error) error {
+	resp, err := c.DoRequest(http.MethodGet, "/clusters", nil)
+	if err != nil {
+		return err
 	}
 
-	return clusters, nil
+	return common.DecodeList(resp, fn)
 }
 
 // GetCluster retrieves a specific cluster by ID
 func (c *Client) GetCluster(clusterID string) (*Cluster, error) {
-	path := fmt.Sprintf("/clusters/%s", clusterID)
+	if err := requireID("cluster_id", clusterID); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/clusters/%s", url.PathEscape(clusterID))
 	resp, err := c.DoRequest(http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
@@ -33,11 +52,16 @@ func (c *Client) GetCluster(clusterID string) (*Cluster, error) {
 		return nil, err
 	}
 
+	cluster.ETag = resp.Header.Get("ETag")
 	return &cluster, nil
 }
 
 // CreateCluster creates a new cluster
 func (c *Client) CreateCluster(req *ClusterCreate) (*Cluster, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
 	resp, err := c.DoRequest(http.MethodPost, "/clusters", req)
 	if err != nil {
 		return nil, err
@@ -53,8 +77,20 @@ func (c *Client) CreateCluster(req *ClusterCreate) (*Cluster, error) {
 
 // UpdateCluster updates an existing cluster
 func (c *Client) UpdateCluster(clusterID string, req *ClusterUpdate) (*Cluster, error) {
-	path := fmt.Sprintf("/clusters/%s", clusterID)
-	resp, err := c.DoRequest(http.MethodPut, path, req)
+	return c.updateCluster(clusterID, req, nil)
+}
+
+// updateCluster performs the update, sending the given extra headers
+func (c *Client) updateCluster(clusterID string, req *ClusterUpdate, header http.Header) (*Cluster, error) {
+	if err := requireID("cluster_id", clusterID); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/clusters/%s", url.PathEscape(clusterID))
+	resp, err := c.DoRequestWithHeaders(context.Background(), http.MethodPut, path, req, header)
 	if err != nil {
 		return nil, err
 	}
@@ -64,12 +100,17 @@ func (c *Client) UpdateCluster(clusterID string, req *ClusterUpdate) (*Cluster,
 		return nil, err
 	}
 
+	response.Cluster.ETag = resp.Header.Get("ETag")
 	return &response.Cluster, nil
 }
 
 // DeleteCluster deletes a cluster
 func (c *Client) DeleteCluster(clusterID string) error {
-	path := fmt.Sprintf("/clusters/%s", clusterID)
+	if err := requireID("cluster_id", clusterID); err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/clusters/%s", url.PathEscape(clusterID))
 	resp, err := c.DoRequest(http.MethodDelete, path, nil)
 	if err != nil {
 		return err
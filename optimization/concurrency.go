@@ -0,0 +1,248 @@
+package optimization
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/overwatch144/golang-safirclient/common"
+)
+
+// DefaultModifyRetries is how many times a ModifyX helper starts over after
+// a conflict before giving up
+const DefaultModifyRetries = 5
+
+// modifyBackoff is the pause before the first retry, growing linearly
+const modifyBackoff = 100 * time.Millisecond
+
+// version returns what a conditional update of a resource is checked
+// against: its ID, its ETag if the server sent one and its UpdatedAt
+func (r *Cluster) version() (id, etag, updatedAt string) { return r.ID, r.ETag, r.UpdatedAt }
+
+func (r *ClusterHost) version() (id, etag, updatedAt string) { return r.ID, r.ETag, r.UpdatedAt }
+
+func (r *HostMaintenancePolicy) version() (id, etag, updatedAt string) {
+	return r.ID, r.ETag, r.UpdatedAt
+}
+
+func (r *WorkloadBalancingPolicy) version() (id, etag, updatedAt string) {
+	return r.ID, r.ETag, r.UpdatedAt
+}
+
+func (r *WorkloadConsolidationPolicy) version() (id, etag, updatedAt string) {
+	return r.ID, r.ETag, r.UpdatedAt
+}
+
+// updateIfUnchanged runs update with a condition on the version current was
+// read at. With an ETag the server checks it through If-Match; otherwise the
+// UpdatedAt of the resource fetched again with get is compared here, which
+// leaves a short window between the check and the update.
+func updateIfUnchanged[T any](resource string, current *T, version func(*T) (id, etag, updatedAt string), get func() (*T, error), update func(header http.Header) (*T, error)) (*T, error) {
+	if current == nil {
+		return nil, &common.ValidationError{Field: "current", Message: "cannot be nil"}
+	}
+
+	id, etag, updatedAt := version(current)
+	if etag != "" {
+		return update(http.Header{"If-Match": []string{etag}})
+	}
+
+	live, err := get()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, _, actual := version(live); actual != updatedAt {
+		return nil, &common.ConflictError{
+			Resource:          resource,
+			ID:                id,
+			ExpectedUpdatedAt: updatedAt,
+			ActualUpdatedAt:   actual,
+		}
+	}
+
+	return update(nil)
+}
+
+// retryOnConflict calls attempt until it succeeds, fails with an error other
+// than a conflict or DefaultModifyRetries retries are used up
+func retryOnConflict(attempt func() error) error {
+	err := attempt()
+	for retry := 1; retry <= DefaultModifyRetries && common.IsConflict(err); retry++ {
+		time.Sleep(time.Duration(retry) * modifyBackoff)
+		err = attempt()
+	}
+	return err
+}
+
+// modifyResource reads a resource with get, applies modify to a copy of it
+// and writes the result back with update unless nothing changed, starting
+// over on conflicts
+func modifyResource[T comparable](get func() (*T, error), modify func(*T) error, update func(current, modified *T) (*T, error)) (*T, error) {
+	var result *T
+	err := retryOnConflict(func() error {
+		current, err := get()
+		if err != nil {
+			return err
+		}
+
+		modified := *current
+		if err := modify(&modified); err != nil {
+			return err
+		}
+		if modified == *current {
+			result = current
+			return nil
+		}
+
+		result, err = update(current, &modified)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateClusterIfUnchanged updates the cluster only if it was not modified
+// since current was read. Concurrent modifications fail with an error for
+// which common.IsConflict is true.
+func (c *Client) UpdateClusterIfUnchanged(current *Cluster, req *ClusterUpdate) (*Cluster, error) {
+	return updateIfUnchanged("cluster", current, (*Cluster).version,
+		func() (*Cluster, error) { return c.uncached().GetCluster(current.ID) },
+		func(header http.Header) (*Cluster, error) { return c.updateCluster(current.ID, req, header) })
+}
+
+// ModifyCluster reads the cluster, applies modify to it and writes the result
+// back with UpdateClusterIfUnchanged, starting over on conflicts
+func (c *Client) ModifyCluster(clusterID string, modify func(*Cluster) error) (*Cluster, error) {
+	return modifyResource(
+		func() (*Cluster, error) { return c.uncached().GetCluster(clusterID) },
+		modify,
+		func(current, modified *Cluster) (*Cluster, error) {
+			return c.UpdateClusterIfUnchanged(current, &ClusterUpdate{
+				Name:        &modified.Name,
+				Description: &modified.Description,
+			})
+		})
+}
+
+// UpdateClusterHostIfUnchanged updates the host only if it was not modified
+// since current was read. Concurrent modifications fail with an error for
+// which common.IsConflict is true.
+func (c *Client) UpdateClusterHostIfUnchanged(current *ClusterHost, req *ClusterHostUpdate) (*ClusterHost, error) {
+	return updateIfUnchanged("host", current, (*ClusterHost).version,
+		func() (*ClusterHost, error) { return c.uncached().GetClusterHost(current.ClusterID, current.ID) },
+		func(header http.Header) (*ClusterHost, error) {
+			return c.updateClusterHost(current.ClusterID, current.ID, req, header)
+		})
+}
+
+// ModifyClusterHost reads the host, applies modify to it and writes the
+// result back with UpdateClusterHostIfUnchanged, starting over on conflicts
+func (c *Client) ModifyClusterHost(clusterID, hostID string, modify func(*ClusterHost) error) (*ClusterHost, error) {
+	return modifyResource(
+		func() (*ClusterHost, error) { return c.uncached().GetClusterHost(clusterID, hostID) },
+		modify,
+		func(current, modified *ClusterHost) (*ClusterHost, error) {
+			return c.UpdateClusterHostIfUnchanged(current, &ClusterHostUpdate{
+				Hostname: &modified.Hostname,
+				Enabled:  &modified.Enabled,
+			})
+		})
+}
+
+// UpdateHostMaintenancePolicyIfUnchanged updates the policy only if it was
+// not modified since current was read. Concurrent modifications fail with an
+// error for which common.IsConflict is true.
+func (c *Client) UpdateHostMaintenancePolicyIfUnchanged(current *HostMaintenancePolicy, req *HostMaintenancePolicyUpdate) (*HostMaintenancePolicy, error) {
+	return updateIfUnchanged("host maintenance policy", current, (*HostMaintenancePolicy).version,
+		func() (*HostMaintenancePolicy, error) { return c.uncached().GetHostMaintenancePolicy(current.ID) },
+		func(header http.Header) (*HostMaintenancePolicy, error) {
+			return c.updateHostMaintenancePolicy(current.ID, req, header)
+		})
+}
+
+// ModifyHostMaintenancePolicy reads the policy, applies modify to it and
+// writes the result back with UpdateHostMaintenancePolicyIfUnchanged,
+// starting over on conflicts
+func (c *Client) ModifyHostMaintenancePolicy(policyID string, modify func(*HostMaintenancePolicy) error) (*HostMaintenancePolicy, error) {
+	return modifyResource(
+		func() (*HostMaintenancePolicy, error) { return c.uncached().GetHostMaintenancePolicy(policyID) },
+		modify,
+		func(current, modified *HostMaintenancePolicy) (*HostMaintenancePolicy, error) {
+			return c.UpdateHostMaintenancePolicyIfUnchanged(current, &HostMaintenancePolicyUpdate{
+				ClusterID: &modified.ClusterID,
+				Name:      &modified.Name,
+				Enabled:   &modified.Enabled,
+			})
+		})
+}
+
+// UpdateWorkloadBalancingPolicyIfUnchanged updates the policy only if it was
+// not modified since current was read. Concurrent modifications fail with an
+// error for which common.IsConflict is true.
+func (c *Client) UpdateWorkloadBalancingPolicyIfUnchanged(current *WorkloadBalancingPolicy, req *WorkloadBalancingPolicyUpdate) (*WorkloadBalancingPolicy, error) {
+	return updateIfUnchanged("workload balancing policy", current, (*WorkloadBalancingPolicy).version,
+		func() (*WorkloadBalancingPolicy, error) { return c.uncached().GetWorkloadBalancingPolicy(current.ID) },
+		func(header http.Header) (*WorkloadBalancingPolicy, error) {
+			return c.updateWorkloadBalancingPolicy(current.ID, req, header)
+		})
+}
+
+// ModifyWorkloadBalancingPolicy reads the policy, applies modify to it and
+// writes the result back with UpdateWorkloadBalancingPolicyIfUnchanged,
+// starting over on conflicts
+func (c *Client) ModifyWorkloadBalancingPolicy(policyID string, modify func(*WorkloadBalancingPolicy) error) (*WorkloadBalancingPolicy, error) {
+	return modifyResource(
+		func() (*WorkloadBalancingPolicy, error) { return c.uncached().GetWorkloadBalancingPolicy(policyID) },
+		modify,
+		func(current, modified *WorkloadBalancingPolicy) (*WorkloadBalancingPolicy, error) {
+			update := &WorkloadBalancingPolicyUpdate{
+				ClusterID:       &modified.ClusterID,
+				Name:            &modified.Name,
+				CPUBalancing:    &modified.CPUBalancing,
+				MemoryBalancing: &modified.MemoryBalancing,
+				Period:          &modified.Period,
+				Enabled:         &modified.Enabled,
+			}
+			// A mode unknown to this client is only sent back if modify
+			// changed it, as validation would reject it otherwise
+			if modified.BalancingMode != current.BalancingMode {
+				update.BalancingMode = &modified.BalancingMode
+			}
+			return c.UpdateWorkloadBalancingPolicyIfUnchanged(current, update)
+		})
+}
+
+// UpdateWorkloadConsolidationPolicyIfUnchanged updates the policy only if it
+// was not modified since current was read. Concurrent modifications fail with
+// an error for which common.IsConflict is true.
+func (c *Client) UpdateWorkloadConsolidationPolicyIfUnchanged(current *WorkloadConsolidationPolicy, req *WorkloadConsolidationPolicyUpdate) (*WorkloadConsolidationPolicy, error) {
+	return updateIfUnchanged("workload consolidation policy", current, (*WorkloadConsolidationPolicy).version,
+		func() (*WorkloadConsolidationPolicy, error) {
+			return c.uncached().GetWorkloadConsolidationPolicy(current.ID)
+		},
+		func(header http.Header) (*WorkloadConsolidationPolicy, error) {
+			return c.updateWorkloadConsolidationPolicy(current.ID, req, header)
+		})
+}
+
+// ModifyWorkloadConsolidationPolicy reads the policy, applies modify to it
+// and writes the result back with UpdateWorkloadConsolidationPolicyIfUnchanged,
+// starting over on conflicts
+func (c *Client) ModifyWorkloadConsolidationPolicy(policyID string, modify func(*WorkloadConsolidationPolicy) error) (*WorkloadConsolidationPolicy, error) {
+	return modifyResource(
+		func() (*WorkloadConsolidationPolicy, error) {
+			return c.uncached().GetWorkloadConsolidationPolicy(policyID)
+		},
+		modify,
+		func(current, modified *WorkloadConsolidationPolicy) (*WorkloadConsolidationPolicy, error) {
+			return c.UpdateWorkloadConsolidationPolicyIfUnchanged(current, &WorkloadConsolidationPolicyUpdate{
+				ClusterID: &modified.ClusterID,
+				Name:      &modified.Name,
+				Period:    &modified.Period,
+				Enabled:   &modified.Enabled,
+			})
+		})
+}
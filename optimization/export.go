@@ -0,0 +1,216 @@
+package optimization
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/overwatch144/golang-safirclient/common"
+)
+
+// ClusterDocumentAPIVersion is the version of the cluster export format
+const ClusterDocumentAPIVersion = "safir.optimization/v1"
+
+// ClusterDocumentKind is the kind of a cluster export document
+const ClusterDocumentKind = "ClusterExport"
+
+// DocumentFormat is a serialization format for cluster documents
+type DocumentFormat string
+
+const (
+	FormatJSON DocumentFormat = "json"
+	FormatYAML DocumentFormat = "yaml"
+)
+
+// ClusterDocument is a versioned snapshot of a cluster's optimization setup
+type ClusterDocument struct {
+	APIVersion                    string                        `json:"api_version"`
+	Kind                          string                        `json:"kind"`
+	ExportedAt                    string                        `json:"exported_at,omitempty"`
+	Cluster                       Cluster                       `json:"cluster"`
+	Hosts                         []ClusterHost                 `json:"hosts"`
+	ExcludedVMs                   []ClusterExcludedVM           `json:"excluded_vms"`
+	WorkloadBalancingPolicies     []WorkloadBalancingPolicy     `json:"workload_balancing_policies"`
+	WorkloadConsolidationPolicies []WorkloadConsolidationPolicy `json:"workload_consolidation_policies"`
+	HostMaintenancePolicies       []HostMaintenancePolicy       `json:"host_maintenance_policies"`
+}
+
+// ImportResult reports the resources created by ImportCluster
+type ImportResult struct {
+	Cluster *Cluster
+	// IDMap maps the IDs found in the document to the IDs of the created resources
+	IDMap map[string]string
+}
+
+// ExportCluster gathers a cluster, its hosts, excluded VMs and policies into
+// a single document. Policy types the deployment does not offer are
+// exported as empty lists.
+func (c *Client) ExportCluster(clusterID string) (*ClusterDocument, error) {
+	cluster, err := c.GetCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster: %w", err)
+	}
+
+	doc := &ClusterDocument{
+		APIVersion: ClusterDocumentAPIVersion,
+		Kind:       ClusterDocumentKind,
+		ExportedAt: time.Now().UTC().Format(time.RFC3339),
+		Cluster:    *cluster,
+	}
+
+	if doc.Hosts, err = c.ListClusterHosts(clusterID); err != nil {
+		return nil, fmt.Errorf("failed to list cluster hosts: %w", err)
+	}
+
+	if doc.ExcludedVMs, err = c.ListClusterExcludedVMs(clusterID); err != nil {
+		return nil, fmt.Errorf("failed to list excluded VMs: %w", err)
+	}
+
+	if doc.WorkloadBalancingPolicies, err = emptyIfNotSupported(c.ListWorkloadBalancingPolicies(&clusterID)); err != nil {
+		return nil, fmt.Errorf("failed to list workload balancing policies: %w", err)
+	}
+
+	if doc.WorkloadConsolidationPolicies, err = emptyIfNotSupported(c.ListWorkloadConsolidationPolicies(&clusterID)); err != nil {
+		return nil, fmt.Errorf("failed to list workload consolidation policies: %w", err)
+	}
+
+	if doc.HostMaintenancePolicies, err = emptyIfNotSupported(c.ListHostMaintenancePolicies(&clusterID)); err != nil {
+		return nil, fmt.Errorf("failed to list host maintenance policies: %w", err)
+	}
+
+	return doc, nil
+}
+
+// ImportCluster recreates the cluster described by doc, with all its hosts,
+// excluded VMs and policies. New IDs are assigned by the server and policies
+// are attached to the new cluster. If an error occurs, the returned result
+// lists what was created so far.
+func (c *Client) ImportCluster(doc *ClusterDocument) (*ImportResult, error) {
+	if err := doc.Validate(); err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{IDMap: make(map[string]string)}
+
+	cluster, err := c.CreateCluster(&ClusterCreate{
+		Name:        doc.Cluster.Name,
+		Description: doc.Cluster.Description,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cluster: %w", err)
+	}
+	result.Cluster = cluster
+	result.IDMap[doc.Cluster.ID] = cluster.ID
+
+	for _, host := range doc.Hosts {
+		created, err := c.CreateClusterHost(cluster.ID, &ClusterHostCreate{
+			Hostname: host.Hostname,
+			Enabled:  host.Enabled,
+		})
+		if err != nil {
+			return result, fmt.Errorf("failed to create host %s: %w", host.Hostname, err)
+		}
+		result.IDMap[host.ID] = created.ID
+	}
+
+	for _, vm := range doc.ExcludedVMs {
+		created, err := c.CreateClusterExcludedVM(cluster.ID, &ClusterExcludedVMCreate{
+			VMName: vm.VMName,
+		})
+		if err != nil {
+			return result, fmt.Errorf("failed to create excluded VM %s: %w", vm.VMName, err)
+		}
+		result.IDMap[vm.ID] = created.ID
+	}
+
+	for _, policy := range doc.WorkloadBalancingPolicies {
+		created, err := c.CreateWorkloadBalancingPolicy(&WorkloadBalancingPolicyCreate{
+			ClusterID:       cluster.ID,
+			Name:            policy.Name,
+			BalancingMode:   policy.BalancingMode,
+			CPUBalancing:    policy.CPUBalancing,
+			MemoryBalancing: policy.MemoryBalancing,
+			Period:          policy.Period,
+			Enabled:         policy.Enabled,
+		})
+		if err != nil {
+			return result, fmt.Errorf("failed to create workload balancing policy %s: %w", policy.Name, err)
+		}
+		result.IDMap[policy.ID] = created.ID
+	}
+
+	for _, policy := range doc.WorkloadConsolidationPolicies {
+		created, err := c.CreateWorkloadConsolidationPolicy(&WorkloadConsolidationPolicyCreate{
+			ClusterID: cluster.ID,
+			Name:      policy.Name,
+			Period:    policy.Period,
+			Enabled:   policy.Enabled,
+		})
+		if err != nil {
+			return result, fmt.Errorf("failed to create workload consolidation policy %s: %w", policy.Name, err)
+		}
+		result.IDMap[policy.ID] = created.ID
+	}
+
+	for _, policy := range doc.HostMaintenancePolicies {
+		created, err := c.CreateHostMaintenancePolicy(&HostMaintenancePolicyCreate{
+			ClusterID: cluster.ID,
+			Name:      policy.Name,
+			Enabled:   policy.Enabled,
+		})
+		if err != nil {
+			return result, fmt.Errorf("failed to create host maintenance policy %s: %w", policy.Name, err)
+		}
+		result.IDMap[policy.ID] = created.ID
+	}
+
+	return result, nil
+}
+
+// Validate checks the document version and kind and that it names a
+// cluster. The error is a common.ValidationErrors listing every problem.
+func (d *ClusterDocument) Validate() error {
+	var errs common.ValidationErrors
+
+	if d.APIVersion != ClusterDocumentAPIVersion {
+		errs.Add("api_version", fmt.Sprintf("unsupported version %q (expected %q)", d.APIVersion, ClusterDocumentAPIVersion))
+	}
+
+	if d.Kind != ClusterDocumentKind {
+		errs.Add("kind", fmt.Sprintf("unsupported kind %q (expected %q)", d.Kind, ClusterDocumentKind))
+	}
+
+	if d.Cluster.Name == "" {
+		errs.Add("cluster.name", "is required")
+	}
+
+	return errs.Err()
+}
+
+// Encode serializes the document in the given format
+func (d *ClusterDocument) Encode(format DocumentFormat) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		return json.MarshalIndent(d, "", "  ")
+	case FormatYAML:
+		return yaml.Marshal(d)
+	}
+
+	return nil, fmt.Errorf("unsupported document format %q", format)
+}
+
+// DecodeClusterDocument parses a JSON or YAML cluster document
+func DecodeClusterDocument(data []byte) (*ClusterDocument, error) {
+	var doc ClusterDocument
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse cluster document: %w", err)
+	}
+
+	if err := doc.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &doc, nil
+}
@@ -0,0 +1,132 @@
+package optimization
+
+import (
+	"github.com/overwatch144/golang-safirclient/common"
+)
+
+// FindClusterByName returns the cluster with the given name. It fails with
+// a *common.NotFoundError if there is none and a *common.AmbiguousError if
+// there are several.
+func (c *Client) FindClusterByName(name string) (*Cluster, error) {
+	clusters, err := c.ListClusters()
+	if err != nil {
+		return nil, err
+	}
+
+	return findOne("cluster", name, clusters, func(cluster Cluster) (string, string) {
+		return cluster.Name, cluster.ID
+	})
+}
+
+// ResolveClusterID returns the ID of the cluster identified by nameOrID.
+// Like the openstack CLI it tries the value as an ID first and falls back to
+// a lookup by name.
+func (c *Client) ResolveClusterID(nameOrID string) (string, error) {
+	if err := requireID("cluster", nameOrID); err != nil {
+		return "", err
+	}
+
+	cluster, err := c.GetCluster(nameOrID)
+	if err == nil {
+		return cluster.ID, nil
+	}
+	if !common.IsNotFound(err) && !common.IsBadRequest(err) {
+		return "", err
+	}
+
+	cluster, err = c.FindClusterByName(nameOrID)
+	if err != nil {
+		return "", err
+	}
+	return cluster.ID, nil
+}
+
+// FindClusterHostByHostname returns the host of the cluster with the given
+// hostname
+func (c *Client) FindClusterHostByHostname(clusterID, hostname string) (*ClusterHost, error) {
+	hosts, err := c.ListClusterHosts(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	return findOne("host", hostname, hosts, func(host ClusterHost) (string, string) {
+		return host.Hostname, host.ID
+	})
+}
+
+// FindExcludedVMByName returns the excluded VM of the cluster with the given
+// VM name
+func (c *Client) FindExcludedVMByName(clusterID, vmName string) (*ClusterExcludedVM, error) {
+	vms, err := c.ListClusterExcludedVMs(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	return findOne("excluded VM", vmName, vms, func(vm ClusterExcludedVM) (string, string) {
+		return vm.VMName, vm.ID
+	})
+}
+
+// FindWorkloadBalancingPolicyByName returns the workload balancing policy
+// with the given name, optionally restricted to a cluster
+func (c *Client) FindWorkloadBalancingPolicyByName(clusterID *string, name string) (*WorkloadBalancingPolicy, error) {
+	policies, err := c.ListWorkloadBalancingPolicies(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	return findOne("workload balancing policy", name, policies, func(policy WorkloadBalancingPolicy) (string, string) {
+		return policy.Name, policy.ID
+	})
+}
+
+// FindWorkloadConsolidationPolicyByName returns the workload consolidation
+// policy with the given name, optionally restricted to a cluster
+func (c *Client) FindWorkloadConsolidationPolicyByName(clusterID *string, name string) (*WorkloadConsolidationPolicy, error) {
+	policies, err := c.ListWorkloadConsolidationPolicies(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	return findOne("workload consolidation policy", name, policies, func(policy WorkloadConsolidationPolicy) (string, string) {
+		return policy.Name, policy.ID
+	})
+}
+
+// FindHostMaintenancePolicyByName returns the host maintenance policy with
+// the given name, optionally restricted to a cluster
+func (c *Client) FindHostMaintenancePolicyByName(clusterID *string, name string) (*HostMaintenancePolicy, error) {
+	policies, err := c.ListHostMaintenancePolicies(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	return findOne("host maintenance policy", name, policies, func(policy HostMaintenancePolicy) (string, string) {
+		return policy.Name, policy.ID
+	})
+}
+
+// findOne returns the only item whose name matches. key returns the name and
+// the ID of an item.
+func findOne[T any](resource, name string, items []T, key func(T) (string, string)) (*T, error) {
+	var match *T
+	var candidates []string
+
+	for i := range items {
+		itemName, id := key(items[i])
+		if itemName != name {
+			continue
+		}
+		match = &items[i]
+		candidates = append(candidates, id)
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, &common.NotFoundError{Resource: resource, Name: name}
+	case 1:
+		return match, nil
+	}
+
+	return nil, &common.AmbiguousError{Resource: resource, Name: name, Candidates: candidates}
+}
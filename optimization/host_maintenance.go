@@ -1,12 +1,20 @@
 package optimization
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"net/url"
+
+	"github.com/overwatch144/golang-safirclient/common"
 )
 
 // ListHostMaintenancePolicies retrieves all host maintenance policies
 func (c *Client) ListHostMaintenancePolicies(clusterID *string) ([]HostMaintenancePolicy, error) {
+	if err := c.requireFeature("ListHostMaintenancePolicies", common.FeatureHostMaintenance); err != nil {
+		return nil, err
+	}
+
 	path := "/host-maintenance"
 	if clusterID != nil {
 		path = fmt.Sprintf("%s?cluster_id=%s", path, *clusterID)
@@ -27,7 +35,15 @@ func (c *Client) ListHostMaintenancePolicies(clusterID *string) ([]HostMaintenan
 
 // GetHostMaintenancePolicy retrieves a specific host maintenance policy by ID
 func (c *Client) GetHostMaintenancePolicy(policyID string) (*HostMaintenancePolicy, error) {
-	path := fmt.Sprintf("/host-maintenance/%s", policyID)
+	if err := c.requireFeature("GetHostMaintenancePolicy", common.FeatureHostMaintenance); err != nil {
+		return nil, err
+	}
+
+	if err := requireID("policy_id", policyID); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/host-maintenance/%s", url.PathEscape(policyID))
 	resp, err := c.DoRequest(http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
@@ -38,11 +54,20 @@ func (c *Client) GetHostMaintenancePolicy(policyID string) (*HostMaintenancePoli
 		return nil, err
 	}
 
+	policy.ETag = resp.Header.Get("ETag")
 	return &policy, nil
 }
 
 // CreateHostMaintenancePolicy creates a new host maintenance policy
 func (c *Client) CreateHostMaintenancePolicy(req *HostMaintenancePolicyCreate) (*HostMaintenancePolicy, error) {
+	if err := c.requireFeature("CreateHostMaintenancePolicy", common.FeatureHostMaintenance); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
 	resp, err := c.DoRequest(http.MethodPost, "/host-maintenance", req)
 	if err != nil {
 		return nil, err
@@ -58,8 +83,24 @@ func (c *Client) CreateHostMaintenancePolicy(req *HostMaintenancePolicyCreate) (
 
 // UpdateHostMaintenancePolicy updates a host maintenance policy
 func (c *Client) UpdateHostMaintenancePolicy(policyID string, req *HostMaintenancePolicyUpdate) (*HostMaintenancePolicy, error) {
-	path := fmt.Sprintf("/host-maintenance/%s", policyID)
-	resp, err := c.DoRequest(http.MethodPut, path, req)
+	return c.updateHostMaintenancePolicy(policyID, req, nil)
+}
+
+// updateHostMaintenancePolicy performs the update, sending the given extra headers
+func (c *Client) updateHostMaintenancePolicy(policyID string, req *HostMaintenancePolicyUpdate, header http.Header) (*HostMaintenancePolicy, error) {
+	if err := c.requireFeature("UpdateHostMaintenancePolicy", common.FeatureHostMaintenance); err != nil {
+		return nil, err
+	}
+
+	if err := requireID("policy_id", policyID); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/host-maintenance/%s", url.PathEscape(policyID))
+	resp, err := c.DoRequestWithHeaders(context.Background(), http.MethodPut, path, req, header)
 	if err != nil {
 		return nil, err
 	}
@@ -69,12 +110,21 @@ func (c *Client) UpdateHostMaintenancePolicy(policyID string, req *HostMaintenan
 		return nil, err
 	}
 
+	response.Policy.ETag = resp.Header.Get("ETag")
 	return &response.Policy, nil
 }
 
 // DeleteHostMaintenancePolicy deletes a host maintenance policy
 func (c *Client) DeleteHostMaintenancePolicy(policyID string) error {
-	path := fmt.Sprintf("/host-maintenance/%s", policyID)
+	if err := c.requireFeature("DeleteHostMaintenancePolicy", common.FeatureHostMaintenance); err != nil {
+		return err
+	}
+
+	if err := requireID("policy_id", policyID); err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/host-maintenance/%s", url.PathEscape(policyID))
 	resp, err := c.DoRequest(http.MethodDelete, path, nil)
 	if err != nil {
 		return err
@@ -0,0 +1,369 @@
+// Package informer keeps a local copy of Safir Optimization resources and
+// reports their changes, modeled on Kubernetes informers.
+//
+// Safir has no watch API, so the informer polls the list calls and compares
+// the results with its store. Changes are detected through UpdatedAt:
+//
+//	inf := informer.New(client, informer.Options{Interval: 15 * time.Second})
+//	inf.Start(ctx)
+//	for event := range inf.Events() {
+//		if host, ok := event.Object.(optimization.ClusterHost); ok && event.Type == informer.EventAdded {
+//			log.Printf("host %s added to cluster %s", host.Hostname, host.ClusterID)
+//		}
+//	}
+package informer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+// DefaultInterval is the default interval between two polls
+const DefaultInterval = 30 * time.Second
+
+// DefaultBufferSize is the default capacity of the event channel
+const DefaultBufferSize = 100
+
+// EventType is the kind of change reported by an Event
+type EventType string
+
+// Event types
+const (
+	EventAdded   EventType = "Added"
+	EventUpdated EventType = "Updated"
+	EventDeleted EventType = "Deleted"
+)
+
+// Event reports a change of one resource
+type Event struct {
+	Type EventType
+	Kind optimization.ResourceKind
+	ID   string
+	// Object is the resource as an optimization.Cluster, ClusterHost,
+	// WorkloadBalancingPolicy, WorkloadConsolidationPolicy or
+	// HostMaintenancePolicy value. For deletions it is the last known state.
+	Object any
+	// Old is the previous state of an updated resource
+	Old any
+	// Resync marks the Updated events of a periodic resync, for which Old
+	// and Object are the same
+	Resync bool
+}
+
+// Options holds informer configuration
+type Options struct {
+	// Interval is the time between two polls (default 30s)
+	Interval time.Duration
+	// ResyncPeriod is the time between two resyncs, which send an Updated
+	// event for every stored resource so that handlers can correct missed
+	// work. Zero disables resyncs.
+	ResyncPeriod time.Duration
+	// BufferSize is the capacity of the event channel (default 100). Polls
+	// block while the channel is full.
+	BufferSize int
+	// OnError is called with the error of every failed poll. Resources that
+	// could not be listed keep their previous state.
+	OnError func(error)
+}
+
+// Informer polls Safir Optimization and keeps its Store up to date
+type Informer struct {
+	client *optimization.Client
+	opts   Options
+	store  *Store
+	events chan Event
+	synced atomic.Bool
+
+	// mutex serializes polls and resyncs
+	mutex sync.Mutex
+}
+
+// New creates an informer for the given client. The informer always reads
+// past the client's response cache.
+func New(client *optimization.Client, opts Options) *Informer {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = DefaultBufferSize
+	}
+
+	return &Informer{
+		client: &optimization.Client{BaseClient: client.WithoutCache()},
+		opts:   opts,
+		store:  newStore(),
+		events: make(chan Event, opts.BufferSize),
+	}
+}
+
+// Events returns the channel of changes. It is closed when the context
+// passed to Start is cancelled.
+func (i *Informer) Events() <-chan Event {
+	return i.events
+}
+
+// Store returns the local copy of the resources
+func (i *Informer) Store() *Store {
+	return i.store
+}
+
+// HasSynced reports whether a poll has listed every resource successfully
+func (i *Informer) HasSynced() bool {
+	return i.synced.Load()
+}
+
+// Start polls immediately and then every interval in the background until
+// the context is cancelled. The first poll reports every existing resource
+// as Added.
+func (i *Informer) Start(ctx context.Context) {
+	go func() {
+		defer close(i.events)
+
+		i.poll(ctx)
+
+		ticker := time.NewTicker(i.opts.Interval)
+		defer ticker.Stop()
+
+		var resync <-chan time.Time
+		if i.opts.ResyncPeriod > 0 {
+			resyncTicker := time.NewTicker(i.opts.ResyncPeriod)
+			defer resyncTicker.Stop()
+			resync = resyncTicker.C
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				i.poll(ctx)
+			case <-resync:
+				i.Resync(ctx)
+			}
+		}
+	}()
+}
+
+func (i *Informer) poll(ctx context.Context) {
+	if err := i.Poll(ctx); err != nil && ctx.Err() == nil && i.opts.OnError != nil {
+		i.opts.OnError(err)
+	}
+}
+
+// Poll lists every resource once, updates the store and sends the events of
+// the changes. Start calls it periodically; call it directly only when the
+// informer is not started and the events are consumed concurrently. Policy
+// types the deployment does not offer are skipped.
+func (i *Informer) Poll(ctx context.Context) error {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	var errs []error
+
+	clusters, err := i.client.ListClusters()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("clusters: %w", err))
+	} else {
+		if err := i.apply(ctx, optimization.KindCluster, byID(clusters, clusterID)); err != nil {
+			return err
+		}
+
+		hosts, err := i.listHosts(clusters)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if err := i.apply(ctx, optimization.KindHost, hosts); err != nil {
+			return err
+		}
+	}
+
+	for _, source := range []struct {
+		kind optimization.ResourceKind
+		list func() (map[string]any, error)
+	}{
+		{optimization.KindWorkloadBalancingPolicy, i.listWorkloadBalancingPolicies},
+		{optimization.KindWorkloadConsolidationPolicy, i.listWorkloadConsolidationPolicies},
+		{optimization.KindHostMaintenancePolicy, i.listHostMaintenancePolicies},
+	} {
+		objects, err := source.list()
+		if common.IsNotSupported(err) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", source.kind, err))
+			continue
+		}
+		if err := i.apply(ctx, source.kind, objects); err != nil {
+			return err
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	i.synced.Store(true)
+	return nil
+}
+
+// Resync sends an Updated event for every stored resource
+func (i *Informer) Resync(ctx context.Context) error {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	for _, kind := range []optimization.ResourceKind{
+		optimization.KindCluster,
+		optimization.KindHost,
+		optimization.KindWorkloadBalancingPolicy,
+		optimization.KindWorkloadConsolidationPolicy,
+		optimization.KindHostMaintenancePolicy,
+	} {
+		objects := i.store.snapshot(kind)
+		for _, id := range sortedIDs(objects) {
+			event := Event{Type: EventUpdated, Kind: kind, ID: id, Object: objects[id], Old: objects[id], Resync: true}
+			if err := i.send(ctx, event); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// apply replaces the objects of a kind in the store and sends the events of
+// the differences
+func (i *Informer) apply(ctx context.Context, kind optimization.ResourceKind, objects map[string]any) error {
+	previous := i.store.snapshot(kind)
+	i.store.replace(kind, objects)
+
+	for _, id := range sortedIDs(objects) {
+		old, ok := previous[id]
+		switch {
+		case !ok:
+			if err := i.send(ctx, Event{Type: EventAdded, Kind: kind, ID: id, Object: objects[id]}); err != nil {
+				return err
+			}
+		case changed(old, objects[id]):
+			if err := i.send(ctx, Event{Type: EventUpdated, Kind: kind, ID: id, Object: objects[id], Old: old}); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, id := range sortedIDs(previous) {
+		if _, ok := objects[id]; ok {
+			continue
+		}
+		if err := i.send(ctx, Event{Type: EventDeleted, Kind: kind, ID: id, Object: previous[id]}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i *Informer) send(ctx context.Context, event Event) error {
+	select {
+	case i.events <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// listHosts lists the hosts of every cluster. The hosts of a cluster whose
+// list fails keep their previous state.
+func (i *Informer) listHosts(clusters []optimization.Cluster) (map[string]any, error) {
+	previous := i.store.snapshot(optimization.KindHost)
+	hosts := make(map[string]any)
+	var errs []error
+
+	for _, cluster := range clusters {
+		clusterHosts, err := i.client.ListClusterHosts(cluster.ID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("hosts of cluster %s: %w", cluster.ID, err))
+			for id, host := range previous {
+				if host.(optimization.ClusterHost).ClusterID == cluster.ID {
+					hosts[id] = host
+				}
+			}
+			continue
+		}
+
+		for _, host := range clusterHosts {
+			hosts[host.ID] = host
+		}
+	}
+
+	return hosts, errors.Join(errs...)
+}
+
+func (i *Informer) listWorkloadBalancingPolicies() (map[string]any, error) {
+	policies, err := i.client.ListWorkloadBalancingPolicies(nil)
+	if err != nil {
+		return nil, err
+	}
+	return byID(policies, func(policy optimization.WorkloadBalancingPolicy) string { return policy.ID }), nil
+}
+
+func (i *Informer) listWorkloadConsolidationPolicies() (map[string]any, error) {
+	policies, err := i.client.ListWorkloadConsolidationPolicies(nil)
+	if err != nil {
+		return nil, err
+	}
+	return byID(policies, func(policy optimization.WorkloadConsolidationPolicy) string { return policy.ID }), nil
+}
+
+func (i *Informer) listHostMaintenancePolicies() (map[string]any, error) {
+	policies, err := i.client.ListHostMaintenancePolicies(nil)
+	if err != nil {
+		return nil, err
+	}
+	return byID(policies, func(policy optimization.HostMaintenancePolicy) string { return policy.ID }), nil
+}
+
+func clusterID(cluster optimization.Cluster) string {
+	return cluster.ID
+}
+
+func byID[T any](items []T, id func(T) string) map[string]any {
+	objects := make(map[string]any, len(items))
+	for _, item := range items {
+		objects[id(item)] = item
+	}
+	return objects
+}
+
+// changed compares two states of a resource by UpdatedAt. Resources without
+// UpdatedAt are compared field by field.
+func changed(old, current any) bool {
+	oldUpdatedAt, currentUpdatedAt := updatedAt(old), updatedAt(current)
+	if oldUpdatedAt != "" || currentUpdatedAt != "" {
+		return oldUpdatedAt != currentUpdatedAt
+	}
+	return old != current
+}
+
+func updatedAt(object any) string {
+	switch object := object.(type) {
+	case optimization.Cluster:
+		return object.UpdatedAt
+	case optimization.ClusterHost:
+		return object.UpdatedAt
+	case optimization.WorkloadBalancingPolicy:
+		return object.UpdatedAt
+	case optimization.WorkloadConsolidationPolicy:
+		return object.UpdatedAt
+	case optimization.HostMaintenancePolicy:
+		return object.UpdatedAt
+	}
+	return ""
+}
@@ -0,0 +1,131 @@
+package informer
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+// Store is the local copy of the resources kept by an Informer. It is safe
+// for concurrent use; the returned values are copies.
+type Store struct {
+	mutex   sync.RWMutex
+	objects map[optimization.ResourceKind]map[string]any
+}
+
+func newStore() *Store {
+	return &Store{objects: make(map[optimization.ResourceKind]map[string]any)}
+}
+
+// Clusters returns all clusters, sorted by ID
+func (s *Store) Clusters() []optimization.Cluster {
+	return list[optimization.Cluster](s, optimization.KindCluster, nil)
+}
+
+// Cluster returns the cluster with the given ID
+func (s *Store) Cluster(id string) (optimization.Cluster, bool) {
+	return get[optimization.Cluster](s, optimization.KindCluster, id)
+}
+
+// Hosts returns the hosts of a cluster, sorted by ID
+func (s *Store) Hosts(clusterID string) []optimization.ClusterHost {
+	return list(s, optimization.KindHost, func(host optimization.ClusterHost) bool {
+		return host.ClusterID == clusterID
+	})
+}
+
+// Host returns the host with the given ID
+func (s *Store) Host(id string) (optimization.ClusterHost, bool) {
+	return get[optimization.ClusterHost](s, optimization.KindHost, id)
+}
+
+// WorkloadBalancingPolicies returns all workload balancing policies, sorted
+// by ID
+func (s *Store) WorkloadBalancingPolicies() []optimization.WorkloadBalancingPolicy {
+	return list[optimization.WorkloadBalancingPolicy](s, optimization.KindWorkloadBalancingPolicy, nil)
+}
+
+// WorkloadBalancingPolicy returns the workload balancing policy with the
+// given ID
+func (s *Store) WorkloadBalancingPolicy(id string) (optimization.WorkloadBalancingPolicy, bool) {
+	return get[optimization.WorkloadBalancingPolicy](s, optimization.KindWorkloadBalancingPolicy, id)
+}
+
+// WorkloadConsolidationPolicies returns all workload consolidation policies,
+// sorted by ID
+func (s *Store) WorkloadConsolidationPolicies() []optimization.WorkloadConsolidationPolicy {
+	return list[optimization.WorkloadConsolidationPolicy](s, optimization.KindWorkloadConsolidationPolicy, nil)
+}
+
+// WorkloadConsolidationPolicy returns the workload consolidation policy with
+// the given ID
+func (s *Store) WorkloadConsolidationPolicy(id string) (optimization.WorkloadConsolidationPolicy, bool) {
+	return get[optimization.WorkloadConsolidationPolicy](s, optimization.KindWorkloadConsolidationPolicy, id)
+}
+
+// HostMaintenancePolicies returns all host maintenance policies, sorted by ID
+func (s *Store) HostMaintenancePolicies() []optimization.HostMaintenancePolicy {
+	return list[optimization.HostMaintenancePolicy](s, optimization.KindHostMaintenancePolicy, nil)
+}
+
+// HostMaintenancePolicy returns the host maintenance policy with the given ID
+func (s *Store) HostMaintenancePolicy(id string) (optimization.HostMaintenancePolicy, bool) {
+	return get[optimization.HostMaintenancePolicy](s, optimization.KindHostMaintenancePolicy, id)
+}
+
+func get[T any](s *Store, kind optimization.ResourceKind, id string) (T, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	object, ok := s.objects[kind][id]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return object.(T), true
+}
+
+func list[T any](s *Store, kind optimization.ResourceKind, keep func(T) bool) []T {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	ids := sortedIDs(s.objects[kind])
+	items := make([]T, 0, len(ids))
+	for _, id := range ids {
+		item := s.objects[kind][id].(T)
+		if keep == nil || keep(item) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// snapshot returns a copy of the objects of a kind
+func (s *Store) snapshot(kind optimization.ResourceKind) map[string]any {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	objects := make(map[string]any, len(s.objects[kind]))
+	for id, object := range s.objects[kind] {
+		objects[id] = object
+	}
+	return objects
+}
+
+// replace sets the objects of a kind
+func (s *Store) replace(kind optimization.ResourceKind, objects map[string]any) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.objects[kind] = objects
+}
+
+func sortedIDs(objects map[string]any) []string {
+	ids := make([]string, 0, len(objects))
+	for id := range objects {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,24 @@
+package optimization
+
+// ResourceKind identifies a type of Safir Optimization resource
+type ResourceKind string
+
+// Kinds of resources of the Safir Optimization API
+const (
+	KindCluster                     ResourceKind = "cluster"
+	KindHost                        ResourceKind = "host"
+	KindExcludedVM                  ResourceKind = "excluded_vm"
+	KindWorkloadBalancingPolicy     ResourceKind = "workload_balancing_policy"
+	KindWorkloadConsolidationPolicy ResourceKind = "workload_consolidation_policy"
+	KindHostMaintenancePolicy       ResourceKind = "host_maintenance_policy"
+)
+
+// PolicyType identifies one of the optimization policy types
+type PolicyType string
+
+// Policy types of the Safir Optimization API
+const (
+	PolicyTypeWorkloadBalancing     PolicyType = "workload_balancing"
+	PolicyTypeWorkloadConsolidation PolicyType = "workload_consolidation"
+	PolicyTypeHostMaintenance       PolicyType = "host_maintenance"
+)
@@ -0,0 +1,264 @@
+package optimization
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/overwatch144/golang-safirclient/common"
+)
+
+// Default host maintenance settings
+const (
+	DefaultMaintenancePollInterval = 10 * time.Second
+	DefaultMaintenanceTimeout      = 30 * time.Minute
+	DefaultMaintenancePolicyName   = "host-maintenance"
+)
+
+// Evacuator moves the VMs off a hypervisor. Callers implement it with the
+// migration backend of their deployment, e.g. through the Safir Migration
+// service.
+type Evacuator interface {
+	// Evacuate starts moving every VM off the host. It may return before
+	// the VMs are gone.
+	Evacuate(ctx context.Context, hostname string) error
+	// RemainingVMs returns the number of VMs still on the host
+	RemainingVMs(ctx context.Context, hostname string) (int, error)
+}
+
+// MaintenanceStep identifies a step of the host maintenance workflow
+type MaintenanceStep string
+
+// Steps of the host maintenance workflow, in order
+const (
+	MaintenanceStepDisabled   MaintenanceStep = "disabled"
+	MaintenanceStepPolicy     MaintenanceStep = "policy"
+	MaintenanceStepEvacuating MaintenanceStep = "evacuating"
+	MaintenanceStepWaiting    MaintenanceStep = "waiting"
+	MaintenanceStepDrained    MaintenanceStep = "drained"
+	MaintenanceStepRestored   MaintenanceStep = "restored"
+	MaintenanceStepFailed     MaintenanceStep = "failed"
+)
+
+// MaintenanceEvent reports the progress of a host maintenance
+type MaintenanceEvent struct {
+	Step      MaintenanceStep
+	ClusterID string
+	HostID    string
+	Hostname  string
+	// PolicyID is set for the policy step
+	PolicyID string
+	// RemainingVMs is set for the waiting and drained steps
+	RemainingVMs int
+	Err          error
+	Time         time.Time
+}
+
+// MaintenanceOptions controls the host maintenance workflow
+type MaintenanceOptions struct {
+	// Evacuator moves the VMs off the host. Without it the host is only
+	// disabled and not drained.
+	Evacuator Evacuator
+	// PollInterval is the delay between checks for remaining VMs (default 10s)
+	PollInterval time.Duration
+	// Timeout bounds the wait for the host to become empty (default 30m)
+	Timeout time.Duration
+	// PolicyName names the host maintenance policy created for the cluster
+	// if it has none (default "host-maintenance")
+	PolicyName string
+	// Progress, if set, is called for every step
+	Progress func(MaintenanceEvent)
+}
+
+// MaintenanceTimeoutError reports that a host still had VMs when the
+// maintenance timeout expired
+type MaintenanceTimeoutError struct {
+	Hostname     string
+	RemainingVMs int
+	Timeout      time.Duration
+}
+
+func (e *MaintenanceTimeoutError) Error() string {
+	return fmt.Sprintf("host %s still has %d VMs after %s", e.Hostname, e.RemainingVMs, e.Timeout)
+}
+
+func (o *MaintenanceOptions) emit(host *ClusterHost, step MaintenanceStep, remaining int, err error) {
+	o.emitEvent(host, MaintenanceEvent{Step: step, RemainingVMs: remaining, Err: err})
+}
+
+func (o *MaintenanceOptions) emitEvent(host *ClusterHost, event MaintenanceEvent) {
+	if o.Progress == nil {
+		return
+	}
+	event.ClusterID = host.ClusterID
+	event.HostID = host.ID
+	event.Hostname = host.Hostname
+	event.Time = time.Now()
+	o.Progress(event)
+}
+
+// StartHostMaintenance disables the host so that it is no longer used by the
+// optimization policies, makes sure the cluster has an enabled host
+// maintenance policy, evacuates the host and waits until no VM is left. The
+// host stays disabled, also when draining fails; use EndHostMaintenance to
+// bring it back. The policy is left enabled, as other hosts of the cluster
+// may be in maintenance too.
+func (c *Client) StartHostMaintenance(ctx context.Context, clusterID, hostID string, opts MaintenanceOptions) (*ClusterHost, error) {
+	host, err := c.UpdateClusterHost(clusterID, hostID, &ClusterHostUpdate{Enabled: common.Ptr(false)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to disable host: %w", err)
+	}
+	opts.emit(host, MaintenanceStepDisabled, 0, nil)
+
+	policy, err := c.enableMaintenancePolicy(clusterID, opts.PolicyName)
+	if err != nil {
+		err = fmt.Errorf("failed to enable host maintenance policy: %w", err)
+		opts.emit(host, MaintenanceStepFailed, 0, err)
+		return host, err
+	}
+	if policy != nil {
+		opts.emitEvent(host, MaintenanceEvent{Step: MaintenanceStepPolicy, PolicyID: policy.ID})
+	}
+
+	if opts.Evacuator == nil {
+		return host, nil
+	}
+
+	if err := c.drainHost(ctx, host, opts); err != nil {
+		opts.emit(host, MaintenanceStepFailed, 0, err)
+		return host, err
+	}
+
+	return host, nil
+}
+
+// EndHostMaintenance enables the host again
+func (c *Client) EndHostMaintenance(ctx context.Context, clusterID, hostID string, opts MaintenanceOptions) (*ClusterHost, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	host, err := c.UpdateClusterHost(clusterID, hostID, &ClusterHostUpdate{Enabled: common.Ptr(true)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to enable host: %w", err)
+	}
+	opts.emit(host, MaintenanceStepRestored, 0, nil)
+
+	return host, nil
+}
+
+// RunHostMaintenance drains the host, calls work (e.g. to upgrade the
+// hypervisor) and restores the host. A host that was disabled before stays
+// disabled. If draining or work fails the host is left disabled and the
+// error is returned.
+func (c *Client) RunHostMaintenance(ctx context.Context, clusterID, hostID string, opts MaintenanceOptions, work func(ctx context.Context, host *ClusterHost) error) error {
+	before, err := c.GetClusterHost(clusterID, hostID)
+	if err != nil {
+		return err
+	}
+
+	host, err := c.StartHostMaintenance(ctx, clusterID, hostID, opts)
+	if err != nil {
+		return err
+	}
+
+	if work != nil {
+		if err := work(ctx, host); err != nil {
+			opts.emit(host, MaintenanceStepFailed, 0, err)
+			return err
+		}
+	}
+
+	if !before.Enabled {
+		return nil
+	}
+
+	_, err = c.EndHostMaintenance(ctx, clusterID, hostID, opts)
+	return err
+}
+
+// enableMaintenancePolicy makes sure the cluster has an enabled host
+// maintenance policy, enabling the first one or creating one with the given
+// name. It returns nil if the deployment does not offer host maintenance.
+func (c *Client) enableMaintenancePolicy(clusterID, name string) (*HostMaintenancePolicy, error) {
+	policies, err := c.ListHostMaintenancePolicies(&clusterID)
+	if common.IsNotSupported(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range policies {
+		if policies[i].Enabled {
+			return &policies[i], nil
+		}
+	}
+
+	if len(policies) > 0 {
+		return c.ModifyHostMaintenancePolicy(policies[0].ID, func(policy *HostMaintenancePolicy) error {
+			policy.Enabled = true
+			return nil
+		})
+	}
+
+	if name == "" {
+		name = DefaultMaintenancePolicyName
+	}
+	return c.CreateHostMaintenancePolicy(&HostMaintenancePolicyCreate{
+		ClusterID: clusterID,
+		Name:      name,
+		Enabled:   true,
+	})
+}
+
+// drainHost evacuates the host and polls until it is empty
+func (c *Client) drainHost(ctx context.Context, host *ClusterHost, opts MaintenanceOptions) error {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultMaintenancePollInterval
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultMaintenanceTimeout
+	}
+
+	drainCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	if err := opts.Evacuator.Evacuate(drainCtx, host.Hostname); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("failed to evacuate host %s: %w", host.Hostname, err)
+	}
+	opts.emit(host, MaintenanceStepEvacuating, 0, nil)
+
+	remaining := -1
+	err := common.WaitFor(drainCtx, opts.PollInterval, func() (bool, error) {
+		count, err := opts.Evacuator.RemainingVMs(drainCtx, host.Hostname)
+		if err != nil {
+			if drainCtx.Err() != nil {
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to count VMs on host %s: %w", host.Hostname, err)
+		}
+
+		remaining = count
+		if remaining == 0 {
+			opts.emit(host, MaintenanceStepDrained, 0, nil)
+			return true, nil
+		}
+		opts.emit(host, MaintenanceStepWaiting, remaining, nil)
+		return false, nil
+	})
+
+	// Only the expiry of opts.Timeout is a maintenance timeout; a deadline
+	// of the caller's context is reported as it is
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &MaintenanceTimeoutError{Hostname: host.Hostname, RemainingVMs: remaining, Timeout: opts.Timeout}
+	}
+	return err
+}
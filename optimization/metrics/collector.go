@@ -0,0 +1,329 @@
+// Package metrics exposes Safir Optimization state as Prometheus metrics.
+//
+// The collector gathers its data in the background so that scrapes never
+// block on the Safir API:
+//
+//	collector := metrics.NewCollector(client, metrics.CollectorOptions{})
+//	collector.Start(ctx)
+//	prometheus.MustRegister(collector)
+//	http.Handle("/metrics", promhttp.Handler())
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+// DefaultNamespace is the default metric namespace
+const DefaultNamespace = "safir"
+
+// DefaultInterval is the default interval between two refreshes
+const DefaultInterval = 60 * time.Second
+
+// CollectorOptions holds collector configuration
+type CollectorOptions struct {
+	// Namespace is prepended to every metric name (default "safir")
+	Namespace string
+	// Interval is the time between two background refreshes (default 60s)
+	Interval time.Duration
+	// ConstLabels are attached to every metric, e.g. region or deployment
+	ConstLabels prometheus.Labels
+}
+
+// Collector is a prometheus.Collector reporting Safir Optimization state
+type Collector struct {
+	client   *optimization.Client
+	interval time.Duration
+
+	up              *prometheus.Desc
+	pingDuration    *prometheus.Desc
+	clusters        *prometheus.Desc
+	hosts           *prometheus.Desc
+	policies        *prometheus.Desc
+	refreshDuration *prometheus.Desc
+	lastRefresh     *prometheus.Desc
+	refreshErrors   *prometheus.Desc
+
+	mutex    sync.RWMutex
+	snapshot snapshot
+	errors   map[string]float64
+}
+
+// snapshot holds the values gathered by a single refresh
+type snapshot struct {
+	up              bool
+	pingDuration    time.Duration
+	clusters        int
+	hosts           map[hostKey]int
+	policies        map[policyKey]int
+	refreshDuration time.Duration
+	refreshedAt     time.Time
+}
+
+type hostKey struct {
+	clusterID   string
+	clusterName string
+	enabled     bool
+}
+
+type policyKey struct {
+	policyType optimization.PolicyType
+	enabled    bool
+}
+
+// NewCollector creates a new collector for the given client
+func NewCollector(client *optimization.Client, opts CollectorOptions) *Collector {
+	if opts.Namespace == "" {
+		opts.Namespace = DefaultNamespace
+	}
+
+	if opts.Interval == 0 {
+		opts.Interval = DefaultInterval
+	}
+
+	desc := func(name, help string, labels ...string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(opts.Namespace, "", name), help, labels, opts.ConstLabels)
+	}
+
+	return &Collector{
+		client:   client,
+		interval: opts.Interval,
+
+		up:              desc("up", "Whether the Safir Optimization API answered the last ping (1) or not (0)."),
+		pingDuration:    desc("ping_duration_seconds", "Latency of the last ping against the Safir Optimization API."),
+		clusters:        desc("clusters", "Number of clusters."),
+		hosts:           desc("cluster_hosts", "Number of hosts per cluster.", "cluster_id", "cluster_name", "enabled"),
+		policies:        desc("policies", "Number of optimization policies by type.", "type", "enabled"),
+		refreshDuration: desc("refresh_duration_seconds", "Time spent gathering the last snapshot."),
+		lastRefresh:     desc("last_refresh_timestamp_seconds", "Unix time of the last completed refresh."),
+		refreshErrors:   desc("refresh_errors_total", "Number of failed API calls while refreshing, by section.", "section"),
+
+		errors: make(map[string]float64),
+	}
+}
+
+// Start refreshes the collector in the background, immediately and then
+// every interval until the context is cancelled. It returns at once; until
+// the first refresh completes, the collector reports Safir as down.
+func (c *Collector) Start(ctx context.Context) {
+	go func() {
+		c.Refresh()
+
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.Refresh()
+			}
+		}
+	}()
+}
+
+// Refresh gathers a new snapshot from the Safir API. Sections that fail keep
+// their previous values and increment the refresh error counter.
+func (c *Collector) Refresh() {
+	started := time.Now()
+
+	c.mutex.RLock()
+	next := c.snapshot
+	c.mutex.RUnlock()
+
+	var failed []string
+
+	pingStarted := time.Now()
+	err := c.client.Ping()
+	next.pingDuration = time.Since(pingStarted)
+	next.up = err == nil
+	if err != nil {
+		failed = append(failed, "ping")
+	}
+
+	if clusters, err := c.client.ListClusters(); err != nil {
+		failed = append(failed, "clusters")
+	} else {
+		next.clusters = len(clusters)
+
+		hosts := make(map[hostKey]int)
+		hostsFailed := false
+		for _, cluster := range clusters {
+			clusterHosts, err := c.client.ListClusterHosts(cluster.ID)
+			if err != nil {
+				// Keep the previous values of this cluster
+				hostsFailed = true
+				for _, enabled := range []bool{true, false} {
+					key := hostKey{cluster.ID, cluster.Name, enabled}
+					if count, ok := next.hosts[key]; ok {
+						hosts[key] = count
+					}
+				}
+				continue
+			}
+			// Always report both series so that dashboards see explicit zeros
+			hosts[hostKey{cluster.ID, cluster.Name, true}] = 0
+			hosts[hostKey{cluster.ID, cluster.Name, false}] = 0
+			for _, host := range clusterHosts {
+				hosts[hostKey{cluster.ID, cluster.Name, host.Enabled}]++
+			}
+		}
+		if hostsFailed {
+			failed = append(failed, "hosts")
+		}
+		next.hosts = hosts
+	}
+
+	policies := make(map[policyKey]int)
+	policiesFailed := false
+	for _, source := range []struct {
+		policyType optimization.PolicyType
+		list       func() ([]bool, error)
+	}{
+		{optimization.PolicyTypeWorkloadBalancing, c.workloadBalancingStates},
+		{optimization.PolicyTypeWorkloadConsolidation, c.workloadConsolidationStates},
+		{optimization.PolicyTypeHostMaintenance, c.hostMaintenanceStates},
+	} {
+		enabledKey := policyKey{source.policyType, true}
+		disabledKey := policyKey{source.policyType, false}
+
+		states, err := source.list()
+		if common.IsNotSupported(err) {
+			// The deployment does not offer this policy type
+			states, err = nil, nil
+		}
+		if err != nil {
+			// Keep the previous values of this policy type
+			policiesFailed = true
+			if _, ok := next.policies[enabledKey]; ok {
+				policies[enabledKey] = next.policies[enabledKey]
+				policies[disabledKey] = next.policies[disabledKey]
+			}
+			continue
+		}
+
+		policies[enabledKey] = 0
+		policies[disabledKey] = 0
+		for _, enabled := range states {
+			policies[policyKey{source.policyType, enabled}]++
+		}
+	}
+	if policiesFailed {
+		failed = append(failed, "policies")
+	}
+	next.policies = policies
+
+	next.refreshDuration = time.Since(started)
+	next.refreshedAt = time.Now()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.snapshot = next
+	for _, section := range failed {
+		c.errors[section]++
+	}
+}
+
+// workloadBalancingStates returns the enabled flag of every workload balancing policy
+func (c *Collector) workloadBalancingStates() ([]bool, error) {
+	policies, err := c.client.ListWorkloadBalancingPolicies(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]bool, 0, len(policies))
+	for _, policy := range policies {
+		states = append(states, policy.Enabled)
+	}
+	return states, nil
+}
+
+// workloadConsolidationStates returns the enabled flag of every workload consolidation policy
+func (c *Collector) workloadConsolidationStates() ([]bool, error) {
+	policies, err := c.client.ListWorkloadConsolidationPolicies(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]bool, 0, len(policies))
+	for _, policy := range policies {
+		states = append(states, policy.Enabled)
+	}
+	return states, nil
+}
+
+// hostMaintenanceStates returns the enabled flag of every host maintenance policy
+func (c *Collector) hostMaintenanceStates() ([]bool, error) {
+	policies, err := c.client.ListHostMaintenancePolicies(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]bool, 0, len(policies))
+	for _, policy := range policies {
+		states = append(states, policy.Enabled)
+	}
+	return states, nil
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.up
+	ch <- c.pingDuration
+	ch <- c.clusters
+	ch <- c.hosts
+	ch <- c.policies
+	ch <- c.refreshDuration
+	ch <- c.lastRefresh
+	ch <- c.refreshErrors
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	s := c.snapshot
+
+	up := 0.0
+	if s.up {
+		up = 1
+	}
+	ch <- prometheus.MustNewConstMetric(c.up, prometheus.GaugeValue, up)
+	ch <- prometheus.MustNewConstMetric(c.pingDuration, prometheus.GaugeValue, s.pingDuration.Seconds())
+	ch <- prometheus.MustNewConstMetric(c.clusters, prometheus.GaugeValue, float64(s.clusters))
+
+	for key, count := range s.hosts {
+		ch <- prometheus.MustNewConstMetric(c.hosts, prometheus.GaugeValue, float64(count),
+			key.clusterID, key.clusterName, boolLabel(key.enabled))
+	}
+
+	for key, count := range s.policies {
+		ch <- prometheus.MustNewConstMetric(c.policies, prometheus.GaugeValue, float64(count),
+			string(key.policyType), boolLabel(key.enabled))
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.refreshDuration, prometheus.GaugeValue, s.refreshDuration.Seconds())
+	if !s.refreshedAt.IsZero() {
+		ch <- prometheus.MustNewConstMetric(c.lastRefresh, prometheus.GaugeValue, float64(s.refreshedAt.UnixNano())/1e9)
+	}
+
+	for section, count := range c.errors {
+		ch <- prometheus.MustNewConstMetric(c.refreshErrors, prometheus.CounterValue, count, section)
+	}
+}
+
+func boolLabel(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
@@ -0,0 +1,173 @@
+package metrics_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/overwatch144/golang-safirclient/fakesafir"
+	"github.com/overwatch144/golang-safirclient/optimization"
+	"github.com/overwatch144/golang-safirclient/optimization/metrics"
+)
+
+func newTestClient(t *testing.T, srv *fakesafir.Server) *optimization.Client {
+	t.Helper()
+
+	client, err := optimization.NewClient(optimization.ClientOptions{
+		AuthURL:         srv.IdentityEndpoint(),
+		Username:        fakesafir.Username,
+		Password:        fakesafir.Password,
+		ProjectName:     fakesafir.ProjectName,
+		ProjectDomainID: fakesafir.DomainID,
+		UserDomainID:    fakesafir.DomainID,
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return client
+}
+
+func TestCollector(t *testing.T) {
+	srv := fakesafir.NewServer()
+	defer srv.Close()
+	client := newTestClient(t, srv)
+
+	cluster, err := client.CreateCluster(&optimization.ClusterCreate{Name: "prod"})
+	if err != nil {
+		t.Fatalf("CreateCluster: %v", err)
+	}
+	for _, host := range []optimization.ClusterHostCreate{
+		{Hostname: "compute-01", Enabled: true},
+		{Hostname: "compute-02", Enabled: true},
+		{Hostname: "compute-03"},
+	} {
+		if _, err := client.CreateClusterHost(cluster.ID, &host); err != nil {
+			t.Fatalf("CreateClusterHost: %v", err)
+		}
+	}
+	if _, err := client.CreateWorkloadBalancingPolicy(&optimization.WorkloadBalancingPolicyCreate{
+		ClusterID:     cluster.ID,
+		Name:          "balancing",
+		BalancingMode: optimization.BalancingModeModerate,
+		Period:        3600,
+		Enabled:       true,
+	}); err != nil {
+		t.Fatalf("CreateWorkloadBalancingPolicy: %v", err)
+	}
+
+	collector := metrics.NewCollector(client, metrics.CollectorOptions{})
+	collector.Refresh()
+
+	expected := `
+# HELP safir_up Whether the Safir Optimization API answered the last ping (1) or not (0).
+# TYPE safir_up gauge
+safir_up 1
+# HELP safir_clusters Number of clusters.
+# TYPE safir_clusters gauge
+safir_clusters 1
+# HELP safir_cluster_hosts Number of hosts per cluster.
+# TYPE safir_cluster_hosts gauge
+safir_cluster_hosts{cluster_id="` + cluster.ID + `",cluster_name="prod",enabled="false"} 1
+safir_cluster_hosts{cluster_id="` + cluster.ID + `",cluster_name="prod",enabled="true"} 2
+# HELP safir_policies Number of optimization policies by type.
+# TYPE safir_policies gauge
+safir_policies{enabled="false",type="host_maintenance"} 0
+safir_policies{enabled="false",type="workload_balancing"} 0
+safir_policies{enabled="false",type="workload_consolidation"} 0
+safir_policies{enabled="true",type="host_maintenance"} 0
+safir_policies{enabled="true",type="workload_balancing"} 1
+safir_policies{enabled="true",type="workload_consolidation"} 0
+`
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"safir_up", "safir_clusters", "safir_cluster_hosts", "safir_policies"); err != nil {
+		t.Error(err)
+	}
+	if count := testutil.CollectAndCount(collector, "safir_refresh_errors_total"); count != 0 {
+		t.Errorf("%d refresh error series after a successful refresh", count)
+	}
+
+	// A failed refresh marks Safir down and keeps the previous counts
+	srv.Close()
+	collector.Refresh()
+
+	expected = `
+# HELP safir_up Whether the Safir Optimization API answered the last ping (1) or not (0).
+# TYPE safir_up gauge
+safir_up 0
+# HELP safir_clusters Number of clusters.
+# TYPE safir_clusters gauge
+safir_clusters 1
+# HELP safir_refresh_errors_total Number of failed API calls while refreshing, by section.
+# TYPE safir_refresh_errors_total counter
+safir_refresh_errors_total{section="clusters"} 1
+safir_refresh_errors_total{section="ping"} 1
+safir_refresh_errors_total{section="policies"} 1
+`
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"safir_up", "safir_clusters", "safir_refresh_errors_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCollectorWithoutPolicyType(t *testing.T) {
+	srv := fakesafir.NewServer()
+	defer srv.Close()
+	srv.Disable("host_maintenance")
+	client := newTestClient(t, srv)
+
+	// A policy type the deployment does not offer has no policies
+	collector := metrics.NewCollector(client, metrics.CollectorOptions{})
+	collector.Refresh()
+
+	expected := `
+# HELP safir_policies Number of optimization policies by type.
+# TYPE safir_policies gauge
+safir_policies{enabled="false",type="host_maintenance"} 0
+safir_policies{enabled="false",type="workload_balancing"} 0
+safir_policies{enabled="false",type="workload_consolidation"} 0
+safir_policies{enabled="true",type="host_maintenance"} 0
+safir_policies{enabled="true",type="workload_balancing"} 0
+safir_policies{enabled="true",type="workload_consolidation"} 0
+`
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "safir_policies"); err != nil {
+		t.Error(err)
+	}
+	if count := testutil.CollectAndCount(collector, "safir_refresh_errors_total"); count != 0 {
+		t.Errorf("%d refresh error series without host maintenance", count)
+	}
+}
+
+func TestCollectorStartDoesNotBlock(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	collector := metrics.NewCollector(optimization.NewClientWithToken(srv.URL, "token"), metrics.CollectorOptions{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	started := time.Now()
+	collector.Start(ctx)
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Errorf("Start blocked for %s on a slow API", elapsed)
+	}
+
+	expected := `
+# HELP safir_up Whether the Safir Optimization API answered the last ping (1) or not (0).
+# TYPE safir_up gauge
+safir_up 0
+`
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "safir_up"); err != nil {
+		t.Error(err)
+	}
+}
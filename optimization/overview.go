@@ -0,0 +1,166 @@
+package optimization
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/overwatch144/golang-safirclient/common"
+)
+
+// OverviewSection names one part of a ClusterOverview
+type OverviewSection string
+
+// Sections of a ClusterOverview
+const (
+	OverviewSectionCluster                       OverviewSection = "cluster"
+	OverviewSectionHosts                         OverviewSection = "hosts"
+	OverviewSectionExcludedVMs                   OverviewSection = "excluded_vms"
+	OverviewSectionWorkloadBalancingPolicies     OverviewSection = "workload_balancing_policies"
+	OverviewSectionWorkloadConsolidationPolicies OverviewSection = "workload_consolidation_policies"
+	OverviewSectionHostMaintenancePolicies       OverviewSection = "host_maintenance_policies"
+)
+
+// ClusterOverview is a cluster with everything attached to it
+type ClusterOverview struct {
+	Cluster                       *Cluster                      `json:"cluster"`
+	Hosts                         []ClusterHost                 `json:"hosts"`
+	ExcludedVMs                   []ClusterExcludedVM           `json:"excluded_vms"`
+	WorkloadBalancingPolicies     []WorkloadBalancingPolicy     `json:"workload_balancing_policies"`
+	WorkloadConsolidationPolicies []WorkloadConsolidationPolicy `json:"workload_consolidation_policies"`
+	HostMaintenancePolicies       []HostMaintenancePolicy       `json:"host_maintenance_policies"`
+	Stats                         ClusterOverviewStats          `json:"stats"`
+
+	// Errors holds the error of every section that could not be fetched.
+	// The fields of those sections are empty.
+	Errors map[OverviewSection]error `json:"-"`
+}
+
+// ClusterOverviewStats are derived from the fetched sections
+type ClusterOverviewStats struct {
+	Hosts          int `json:"hosts"`
+	EnabledHosts   int `json:"enabled_hosts"`
+	ExcludedVMs    int `json:"excluded_vms"`
+	Policies       int `json:"policies"`
+	ActivePolicies int `json:"active_policies"`
+}
+
+// OverviewError reports the sections of a ClusterOverview that failed
+type OverviewError struct {
+	ClusterID string
+	Errors    map[OverviewSection]error
+}
+
+func (e *OverviewError) Error() string {
+	sections := make([]string, 0, len(e.Errors))
+	for section := range e.Errors {
+		sections = append(sections, string(section))
+	}
+	sort.Strings(sections)
+
+	messages := make([]string, len(sections))
+	for i, section := range sections {
+		messages[i] = fmt.Sprintf("%s: %v", section, e.Errors[OverviewSection(section)])
+	}
+	return fmt.Sprintf("overview of cluster %s is incomplete: %s", e.ClusterID, strings.Join(messages, "; "))
+}
+
+// GetClusterOverview fetches a cluster, its hosts, excluded VMs and policies
+// concurrently. If some sections fail the overview is still returned, with
+// the failures in Errors, together with an *OverviewError. A cluster that
+// does not exist is reported as the plain not found error. Policy types the
+// deployment does not offer are empty sections.
+func (c *Client) GetClusterOverview(clusterID string) (*ClusterOverview, error) {
+	if err := requireID("cluster_id", clusterID); err != nil {
+		return nil, err
+	}
+
+	overview := &ClusterOverview{Errors: make(map[OverviewSection]error)}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	fetch := func(section OverviewSection, fn func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := fn(); err != nil {
+				mutex.Lock()
+				overview.Errors[section] = err
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	// Every section writes its own field, so only Errors needs the mutex
+	fetch(OverviewSectionCluster, func() (err error) {
+		overview.Cluster, err = c.GetCluster(clusterID)
+		return err
+	})
+	fetch(OverviewSectionHosts, func() (err error) {
+		overview.Hosts, err = c.ListClusterHosts(clusterID)
+		return err
+	})
+	fetch(OverviewSectionExcludedVMs, func() (err error) {
+		overview.ExcludedVMs, err = c.ListClusterExcludedVMs(clusterID)
+		return err
+	})
+	fetch(OverviewSectionWorkloadBalancingPolicies, func() (err error) {
+		overview.WorkloadBalancingPolicies, err = emptyIfNotSupported(c.ListWorkloadBalancingPolicies(&clusterID))
+		return err
+	})
+	fetch(OverviewSectionWorkloadConsolidationPolicies, func() (err error) {
+		overview.WorkloadConsolidationPolicies, err = emptyIfNotSupported(c.ListWorkloadConsolidationPolicies(&clusterID))
+		return err
+	})
+	fetch(OverviewSectionHostMaintenancePolicies, func() (err error) {
+		overview.HostMaintenancePolicies, err = emptyIfNotSupported(c.ListHostMaintenancePolicies(&clusterID))
+		return err
+	})
+	wg.Wait()
+
+	if err := overview.Errors[OverviewSectionCluster]; common.IsNotFound(err) {
+		return nil, err
+	}
+
+	overview.Stats = overview.computeStats()
+
+	if len(overview.Errors) > 0 {
+		return overview, &OverviewError{ClusterID: clusterID, Errors: overview.Errors}
+	}
+	return overview, nil
+}
+
+func (o *ClusterOverview) computeStats() ClusterOverviewStats {
+	stats := ClusterOverviewStats{
+		Hosts:       len(o.Hosts),
+		ExcludedVMs: len(o.ExcludedVMs),
+	}
+
+	for _, host := range o.Hosts {
+		if host.Enabled {
+			stats.EnabledHosts++
+		}
+	}
+
+	for _, policy := range o.WorkloadBalancingPolicies {
+		stats.Policies++
+		if policy.Enabled {
+			stats.ActivePolicies++
+		}
+	}
+	for _, policy := range o.WorkloadConsolidationPolicies {
+		stats.Policies++
+		if policy.Enabled {
+			stats.ActivePolicies++
+		}
+	}
+	for _, policy := range o.HostMaintenancePolicies {
+		stats.Policies++
+		if policy.Enabled {
+			stats.ActivePolicies++
+		}
+	}
+
+	return stats
+}
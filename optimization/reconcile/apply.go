@@ -0,0 +1,208 @@
+package reconcile
+
+import (
+	"fmt"
+
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+// ApplyOptions controls how a plan is applied
+type ApplyOptions struct {
+	// DryRun reports the actions that would be performed without sending
+	// any create, update or delete request
+	DryRun bool
+}
+
+// Result reports what Apply did
+type Result struct {
+	ClusterID string
+	DryRun    bool
+	// Applied lists the actions performed (or that would be performed in
+	// dry-run mode), in order
+	Applied []Action
+}
+
+// Apply executes the plan in order. It stops at the first failing action and
+// returns the actions applied so far together with the error.
+func (r *Reconciler) Apply(plan *Plan, opts ApplyOptions) (*Result, error) {
+	result := &Result{ClusterID: plan.ClusterID, DryRun: opts.DryRun}
+
+	if opts.DryRun {
+		result.Applied = append(result.Applied, plan.Actions...)
+		return result, nil
+	}
+
+	for _, action := range plan.Actions {
+		if action.Kind != optimization.KindCluster && result.ClusterID == "" {
+			return result, fmt.Errorf("%s: cluster %q does not exist", action, plan.ClusterName)
+		}
+
+		clusterID, err := r.apply(result.ClusterID, action)
+		if err != nil {
+			return result, fmt.Errorf("%s: %w", action, err)
+		}
+
+		result.ClusterID = clusterID
+		result.Applied = append(result.Applied, action)
+	}
+
+	return result, nil
+}
+
+// apply performs a single action and returns the (possibly new) cluster ID
+func (r *Reconciler) apply(clusterID string, action Action) (string, error) {
+	switch action.Kind {
+	case optimization.KindCluster:
+		return r.applyCluster(clusterID, action)
+	case optimization.KindHost:
+		return clusterID, r.applyHost(clusterID, action)
+	case optimization.KindExcludedVM:
+		return clusterID, r.applyExcludedVM(clusterID, action)
+	case optimization.KindWorkloadBalancingPolicy:
+		return clusterID, r.applyWorkloadBalancingPolicy(clusterID, action)
+	case optimization.KindWorkloadConsolidationPolicy:
+		return clusterID, r.applyWorkloadConsolidationPolicy(clusterID, action)
+	case optimization.KindHostMaintenancePolicy:
+		return clusterID, r.applyHostMaintenancePolicy(clusterID, action)
+	}
+
+	return clusterID, fmt.Errorf("unknown resource kind %q", action.Kind)
+}
+
+func (r *Reconciler) applyCluster(clusterID string, action Action) (string, error) {
+	spec := action.desired.(*Spec)
+
+	switch action.Type {
+	case ActionCreate:
+		cluster, err := r.client.CreateCluster(&optimization.ClusterCreate{
+			Name:        spec.Name,
+			Description: spec.Description,
+		})
+		if err != nil {
+			return clusterID, err
+		}
+		return cluster.ID, nil
+	case ActionUpdate:
+		_, err := r.client.UpdateCluster(action.ID, &optimization.ClusterUpdate{
+			Description: &spec.Description,
+		})
+		return clusterID, err
+	}
+
+	return clusterID, fmt.Errorf("unsupported action %q", action.Type)
+}
+
+func (r *Reconciler) applyHost(clusterID string, action Action) error {
+	switch action.Type {
+	case ActionCreate:
+		spec := action.desired.(HostSpec)
+		_, err := r.client.CreateClusterHost(clusterID, &optimization.ClusterHostCreate{
+			Hostname: spec.Hostname,
+			Enabled:  spec.Enabled,
+		})
+		return err
+	case ActionUpdate:
+		spec := action.desired.(HostSpec)
+		_, err := r.client.UpdateClusterHost(clusterID, action.ID, &optimization.ClusterHostUpdate{
+			Enabled: &spec.Enabled,
+		})
+		return err
+	case ActionDelete:
+		return r.client.DeleteClusterHost(clusterID, action.ID)
+	}
+
+	return fmt.Errorf("unsupported action %q", action.Type)
+}
+
+func (r *Reconciler) applyExcludedVM(clusterID string, action Action) error {
+	switch action.Type {
+	case ActionCreate:
+		_, err := r.client.CreateClusterExcludedVM(clusterID, &optimization.ClusterExcludedVMCreate{
+			VMName: action.desired.(string),
+		})
+		return err
+	case ActionDelete:
+		return r.client.DeleteClusterExcludedVM(clusterID, action.ID)
+	}
+
+	return fmt.Errorf("unsupported action %q", action.Type)
+}
+
+func (r *Reconciler) applyWorkloadBalancingPolicy(clusterID string, action Action) error {
+	switch action.Type {
+	case ActionCreate:
+		spec := action.desired.(WorkloadBalancingPolicySpec)
+		_, err := r.client.CreateWorkloadBalancingPolicy(&optimization.WorkloadBalancingPolicyCreate{
+			ClusterID:       clusterID,
+			Name:            spec.Name,
+			BalancingMode:   spec.BalancingMode,
+			CPUBalancing:    spec.CPUBalancing,
+			MemoryBalancing: spec.MemoryBalancing,
+			Period:          spec.Period,
+			Enabled:         spec.Enabled,
+		})
+		return err
+	case ActionUpdate:
+		spec := action.desired.(WorkloadBalancingPolicySpec)
+		_, err := r.client.UpdateWorkloadBalancingPolicy(action.ID, &optimization.WorkloadBalancingPolicyUpdate{
+			BalancingMode:   &spec.BalancingMode,
+			CPUBalancing:    &spec.CPUBalancing,
+			MemoryBalancing: &spec.MemoryBalancing,
+			Period:          &spec.Period,
+			Enabled:         &spec.Enabled,
+		})
+		return err
+	case ActionDelete:
+		return r.client.DeleteWorkloadBalancingPolicy(action.ID)
+	}
+
+	return fmt.Errorf("unsupported action %q", action.Type)
+}
+
+func (r *Reconciler) applyWorkloadConsolidationPolicy(clusterID string, action Action) error {
+	switch action.Type {
+	case ActionCreate:
+		spec := action.desired.(WorkloadConsolidationPolicySpec)
+		_, err := r.client.CreateWorkloadConsolidationPolicy(&optimization.WorkloadConsolidationPolicyCreate{
+			ClusterID: clusterID,
+			Name:      spec.Name,
+			Period:    spec.Period,
+			Enabled:   spec.Enabled,
+		})
+		return err
+	case ActionUpdate:
+		spec := action.desired.(WorkloadConsolidationPolicySpec)
+		_, err := r.client.UpdateWorkloadConsolidationPolicy(action.ID, &optimization.WorkloadConsolidationPolicyUpdate{
+			Period:  &spec.Period,
+			Enabled: &spec.Enabled,
+		})
+		return err
+	case ActionDelete:
+		return r.client.DeleteWorkloadConsolidationPolicy(action.ID)
+	}
+
+	return fmt.Errorf("unsupported action %q", action.Type)
+}
+
+func (r *Reconciler) applyHostMaintenancePolicy(clusterID string, action Action) error {
+	switch action.Type {
+	case ActionCreate:
+		spec := action.desired.(HostMaintenancePolicySpec)
+		_, err := r.client.CreateHostMaintenancePolicy(&optimization.HostMaintenancePolicyCreate{
+			ClusterID: clusterID,
+			Name:      spec.Name,
+			Enabled:   spec.Enabled,
+		})
+		return err
+	case ActionUpdate:
+		spec := action.desired.(HostMaintenancePolicySpec)
+		_, err := r.client.UpdateHostMaintenancePolicy(action.ID, &optimization.HostMaintenancePolicyUpdate{
+			Enabled: &spec.Enabled,
+		})
+		return err
+	case ActionDelete:
+		return r.client.DeleteHostMaintenancePolicy(action.ID)
+	}
+
+	return fmt.Errorf("unsupported action %q", action.Type)
+}
@@ -0,0 +1,429 @@
+package reconcile
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+// ActionType is the kind of change an action performs
+type ActionType string
+
+const (
+	ActionCreate ActionType = "create"
+	ActionUpdate ActionType = "update"
+	ActionDelete ActionType = "delete"
+)
+
+// Change describes one field that an update modifies
+type Change struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// Action is a single step of a plan
+type Action struct {
+	Type ActionType
+	Kind optimization.ResourceKind
+	// Name is the natural key of the resource: cluster or policy name,
+	// hostname or VM name
+	Name string
+	// ID is the ID of the existing resource for updates and deletes
+	ID      string
+	Changes []Change
+
+	// desired holds the spec entry used by creates and updates
+	desired interface{}
+}
+
+// String renders the action in a plan-like format
+func (a Action) String() string {
+	var b strings.Builder
+
+	switch a.Type {
+	case ActionCreate:
+		b.WriteString("+ ")
+	case ActionUpdate:
+		b.WriteString("~ ")
+	case ActionDelete:
+		b.WriteString("- ")
+	}
+
+	fmt.Fprintf(&b, "%s %q", a.Kind, a.Name)
+	if a.ID != "" {
+		fmt.Fprintf(&b, " (id: %s)", a.ID)
+	}
+
+	for i, change := range a.Changes {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%s %s -> %s", change.Field, change.Old, change.New)
+	}
+
+	return b.String()
+}
+
+// Plan is the ordered list of actions needed to reach a spec
+type Plan struct {
+	ClusterName string
+	// ClusterID is empty when the cluster does not exist yet
+	ClusterID string
+	Actions   []Action
+}
+
+// IsEmpty reports whether the live state already matches the spec
+func (p *Plan) IsEmpty() bool {
+	return len(p.Actions) == 0
+}
+
+// Count returns the number of actions of the given type
+func (p *Plan) Count(actionType ActionType) int {
+	n := 0
+	for _, action := range p.Actions {
+		if action.Type == actionType {
+			n++
+		}
+	}
+	return n
+}
+
+// String renders the plan with one action per line and a summary
+func (p *Plan) String() string {
+	var b strings.Builder
+
+	for _, action := range p.Actions {
+		b.WriteString(action.String())
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "Plan: %d to create, %d to update, %d to delete.\n",
+		p.Count(ActionCreate), p.Count(ActionUpdate), p.Count(ActionDelete))
+
+	return b.String()
+}
+
+// Reconciler plans and applies specs using an Optimization client
+type Reconciler struct {
+	client *optimization.Client
+}
+
+// New creates a new reconciler
+func New(client *optimization.Client) *Reconciler {
+	return &Reconciler{client: client}
+}
+
+// Plan compares the spec with the live state and returns the actions needed
+// to make them match. Policy types the deployment does not offer are left
+// out of the comparison.
+func (r *Reconciler) Plan(spec *Spec) (*Plan, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	cluster, err := r.findCluster(spec.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{ClusterName: spec.Name}
+
+	if cluster == nil {
+		plan.Actions = append(plan.Actions, Action{Type: ActionCreate, Kind: optimization.KindCluster, Name: spec.Name, desired: spec})
+		plan.Actions = append(plan.Actions, createAll(spec)...)
+		return plan, nil
+	}
+
+	plan.ClusterID = cluster.ID
+	if cluster.Description != spec.Description {
+		plan.Actions = append(plan.Actions, Action{
+			Type:    ActionUpdate,
+			Kind:    optimization.KindCluster,
+			Name:    cluster.Name,
+			ID:      cluster.ID,
+			Changes: []Change{{Field: "description", Old: strconv.Quote(cluster.Description), New: strconv.Quote(spec.Description)}},
+			desired: spec,
+		})
+	}
+
+	var diffs []Action
+
+	hosts, err := r.client.ListClusterHosts(cluster.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cluster hosts: %w", err)
+	}
+	diffs = append(diffs, diffHosts(spec.Hosts, hosts)...)
+
+	vms, err := r.client.ListClusterExcludedVMs(cluster.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list excluded VMs: %w", err)
+	}
+	diffs = append(diffs, diffExcludedVMs(spec.ExcludedVMs, vms)...)
+
+	balancing, err := r.client.ListWorkloadBalancingPolicies(&cluster.ID)
+	if err != nil && !common.IsNotSupported(err) {
+		return nil, fmt.Errorf("failed to list workload balancing policies: %w", err)
+	}
+	if err == nil {
+		diffs = append(diffs, diffWorkloadBalancingPolicies(spec.WorkloadBalancingPolicies, balancing)...)
+	}
+
+	consolidation, err := r.client.ListWorkloadConsolidationPolicies(&cluster.ID)
+	if err != nil && !common.IsNotSupported(err) {
+		return nil, fmt.Errorf("failed to list workload consolidation policies: %w", err)
+	}
+	if err == nil {
+		diffs = append(diffs, diffWorkloadConsolidationPolicies(spec.WorkloadConsolidationPolicies, consolidation)...)
+	}
+
+	maintenance, err := r.client.ListHostMaintenancePolicies(&cluster.ID)
+	if err != nil && !common.IsNotSupported(err) {
+		return nil, fmt.Errorf("failed to list host maintenance policies: %w", err)
+	}
+	if err == nil {
+		diffs = append(diffs, diffHostMaintenancePolicies(spec.HostMaintenancePolicies, maintenance)...)
+	}
+
+	// Deletes go first so that renamed or replaced resources never collide
+	sort.SliceStable(diffs, func(i, j int) bool {
+		return diffs[i].Type == ActionDelete && diffs[j].Type != ActionDelete
+	})
+	plan.Actions = append(plan.Actions, diffs...)
+
+	return plan, nil
+}
+
+// findCluster returns the cluster with the given name, or nil if none exists
+func (r *Reconciler) findCluster(name string) (*optimization.Cluster, error) {
+	clusters, err := r.client.ListClusters()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clusters: %w", err)
+	}
+
+	var found *optimization.Cluster
+	for i := range clusters {
+		if clusters[i].Name != name {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("cluster name %q is ambiguous: matches %s and %s", name, found.ID, clusters[i].ID)
+		}
+		found = &clusters[i]
+	}
+
+	return found, nil
+}
+
+// createAll returns create actions for every child resource of the spec
+func createAll(spec *Spec) []Action {
+	var actions []Action
+
+	for i := range spec.Hosts {
+		actions = append(actions, Action{Type: ActionCreate, Kind: optimization.KindHost, Name: spec.Hosts[i].Hostname, desired: spec.Hosts[i]})
+	}
+	for _, vm := range spec.ExcludedVMs {
+		actions = append(actions, Action{Type: ActionCreate, Kind: optimization.KindExcludedVM, Name: vm, desired: vm})
+	}
+	for i := range spec.WorkloadBalancingPolicies {
+		actions = append(actions, Action{Type: ActionCreate, Kind: optimization.KindWorkloadBalancingPolicy, Name: spec.WorkloadBalancingPolicies[i].Name, desired: spec.WorkloadBalancingPolicies[i]})
+	}
+	for i := range spec.WorkloadConsolidationPolicies {
+		actions = append(actions, Action{Type: ActionCreate, Kind: optimization.KindWorkloadConsolidationPolicy, Name: spec.WorkloadConsolidationPolicies[i].Name, desired: spec.WorkloadConsolidationPolicies[i]})
+	}
+	for i := range spec.HostMaintenancePolicies {
+		actions = append(actions, Action{Type: ActionCreate, Kind: optimization.KindHostMaintenancePolicy, Name: spec.HostMaintenancePolicies[i].Name, desired: spec.HostMaintenancePolicies[i]})
+	}
+
+	return actions
+}
+
+func diffHosts(desired []HostSpec, live []optimization.ClusterHost) []Action {
+	var actions []Action
+	seen := make(map[string]bool)
+
+	byName := make(map[string]HostSpec, len(desired))
+	for _, host := range desired {
+		byName[host.Hostname] = host
+	}
+
+	for _, host := range live {
+		want, ok := byName[host.Hostname]
+		if !ok || seen[host.Hostname] {
+			actions = append(actions, Action{Type: ActionDelete, Kind: optimization.KindHost, Name: host.Hostname, ID: host.ID})
+			continue
+		}
+		seen[host.Hostname] = true
+
+		var changes []Change
+		changes = diffBool(changes, "enabled", host.Enabled, want.Enabled)
+		if len(changes) > 0 {
+			actions = append(actions, Action{Type: ActionUpdate, Kind: optimization.KindHost, Name: host.Hostname, ID: host.ID, Changes: changes, desired: want})
+		}
+	}
+
+	for _, host := range desired {
+		if !seen[host.Hostname] {
+			actions = append(actions, Action{Type: ActionCreate, Kind: optimization.KindHost, Name: host.Hostname, desired: host})
+		}
+	}
+
+	return actions
+}
+
+func diffExcludedVMs(desired []string, live []optimization.ClusterExcludedVM) []Action {
+	var actions []Action
+	seen := make(map[string]bool)
+
+	wanted := make(map[string]bool, len(desired))
+	for _, name := range desired {
+		wanted[name] = true
+	}
+
+	for _, vm := range live {
+		if !wanted[vm.VMName] || seen[vm.VMName] {
+			actions = append(actions, Action{Type: ActionDelete, Kind: optimization.KindExcludedVM, Name: vm.VMName, ID: vm.ID})
+			continue
+		}
+		seen[vm.VMName] = true
+	}
+
+	for _, name := range desired {
+		if !seen[name] {
+			actions = append(actions, Action{Type: ActionCreate, Kind: optimization.KindExcludedVM, Name: name, desired: name})
+		}
+	}
+
+	return actions
+}
+
+func diffWorkloadBalancingPolicies(desired []WorkloadBalancingPolicySpec, live []optimization.WorkloadBalancingPolicy) []Action {
+	var actions []Action
+	seen := make(map[string]bool)
+
+	byName := make(map[string]WorkloadBalancingPolicySpec, len(desired))
+	for _, policy := range desired {
+		byName[policy.Name] = policy
+	}
+
+	for _, policy := range live {
+		want, ok := byName[policy.Name]
+		if !ok || seen[policy.Name] {
+			actions = append(actions, Action{Type: ActionDelete, Kind: optimization.KindWorkloadBalancingPolicy, Name: policy.Name, ID: policy.ID})
+			continue
+		}
+		seen[policy.Name] = true
+
+		var changes []Change
+		changes = diffString(changes, "balancing_mode", string(policy.BalancingMode), string(want.BalancingMode))
+		changes = diffBool(changes, "cpu_balancing", policy.CPUBalancing, want.CPUBalancing)
+		changes = diffBool(changes, "memory_balancing", policy.MemoryBalancing, want.MemoryBalancing)
+		changes = diffInt(changes, "period", policy.Period, want.Period)
+		changes = diffBool(changes, "enabled", policy.Enabled, want.Enabled)
+		if len(changes) > 0 {
+			actions = append(actions, Action{Type: ActionUpdate, Kind: optimization.KindWorkloadBalancingPolicy, Name: policy.Name, ID: policy.ID, Changes: changes, desired: want})
+		}
+	}
+
+	for _, policy := range desired {
+		if !seen[policy.Name] {
+			actions = append(actions, Action{Type: ActionCreate, Kind: optimization.KindWorkloadBalancingPolicy, Name: policy.Name, desired: policy})
+		}
+	}
+
+	return actions
+}
+
+func diffWorkloadConsolidationPolicies(desired []WorkloadConsolidationPolicySpec, live []optimization.WorkloadConsolidationPolicy) []Action {
+	var actions []Action
+	seen := make(map[string]bool)
+
+	byName := make(map[string]WorkloadConsolidationPolicySpec, len(desired))
+	for _, policy := range desired {
+		byName[policy.Name] = policy
+	}
+
+	for _, policy := range live {
+		want, ok := byName[policy.Name]
+		if !ok || seen[policy.Name] {
+			actions = append(actions, Action{Type: ActionDelete, Kind: optimization.KindWorkloadConsolidationPolicy, Name: policy.Name, ID: policy.ID})
+			continue
+		}
+		seen[policy.Name] = true
+
+		var changes []Change
+		changes = diffInt(changes, "period", policy.Period, want.Period)
+		changes = diffBool(changes, "enabled", policy.Enabled, want.Enabled)
+		if len(changes) > 0 {
+			actions = append(actions, Action{Type: ActionUpdate, Kind: optimization.KindWorkloadConsolidationPolicy, Name: policy.Name, ID: policy.ID, Changes: changes, desired: want})
+		}
+	}
+
+	for _, policy := range desired {
+		if !seen[policy.Name] {
+			actions = append(actions, Action{Type: ActionCreate, Kind: optimization.KindWorkloadConsolidationPolicy, Name: policy.Name, desired: policy})
+		}
+	}
+
+	return actions
+}
+
+func diffHostMaintenancePolicies(desired []HostMaintenancePolicySpec, live []optimization.HostMaintenancePolicy) []Action {
+	var actions []Action
+	seen := make(map[string]bool)
+
+	byName := make(map[string]HostMaintenancePolicySpec, len(desired))
+	for _, policy := range desired {
+		byName[policy.Name] = policy
+	}
+
+	for _, policy := range live {
+		want, ok := byName[policy.Name]
+		if !ok || seen[policy.Name] {
+			actions = append(actions, Action{Type: ActionDelete, Kind: optimization.KindHostMaintenancePolicy, Name: policy.Name, ID: policy.ID})
+			continue
+		}
+		seen[policy.Name] = true
+
+		var changes []Change
+		changes = diffBool(changes, "enabled", policy.Enabled, want.Enabled)
+		if len(changes) > 0 {
+			actions = append(actions, Action{Type: ActionUpdate, Kind: optimization.KindHostMaintenancePolicy, Name: policy.Name, ID: policy.ID, Changes: changes, desired: want})
+		}
+	}
+
+	for _, policy := range desired {
+		if !seen[policy.Name] {
+			actions = append(actions, Action{Type: ActionCreate, Kind: optimization.KindHostMaintenancePolicy, Name: policy.Name, desired: policy})
+		}
+	}
+
+	return actions
+}
+
+func diffString(changes []Change, field, live, want string) []Change {
+	if live == want {
+		return changes
+	}
+	return append(changes, Change{Field: field, Old: strconv.Quote(live), New: strconv.Quote(want)})
+}
+
+func diffBool(changes []Change, field string, live, want bool) []Change {
+	if live == want {
+		return changes
+	}
+	return append(changes, Change{Field: field, Old: strconv.FormatBool(live), New: strconv.FormatBool(want)})
+}
+
+func diffInt(changes []Change, field string, live, want int) []Change {
+	if live == want {
+		return changes
+	}
+	return append(changes, Change{Field: field, Old: strconv.Itoa(live), New: strconv.Itoa(want)})
+}
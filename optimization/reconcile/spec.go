@@ -0,0 +1,124 @@
+// Package reconcile applies a desired-state specification to one Safir
+// Optimization cluster.
+//
+// A Spec describes the cluster, its hosts, excluded VMs and policies. The
+// Reconciler compares it with the live state, produces a Plan of creates,
+// updates and deletes, and applies it:
+//
+//	r := reconcile.New(client)
+//	plan, err := r.Plan(spec)
+//	fmt.Print(plan)
+//	result, err := r.Apply(plan, reconcile.ApplyOptions{})
+//
+// The spec is authoritative: hosts, excluded VMs and policies of the cluster
+// that are not listed in it are deleted.
+package reconcile
+
+import (
+	"fmt"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+// Spec is the desired state of one cluster
+type Spec struct {
+	Name                          string                            `json:"name"`
+	Description                   string                            `json:"description,omitempty"`
+	Hosts                         []HostSpec                        `json:"hosts,omitempty"`
+	ExcludedVMs                   []string                          `json:"excluded_vms,omitempty"`
+	WorkloadBalancingPolicies     []WorkloadBalancingPolicySpec     `json:"workload_balancing_policies,omitempty"`
+	WorkloadConsolidationPolicies []WorkloadConsolidationPolicySpec `json:"workload_consolidation_policies,omitempty"`
+	HostMaintenancePolicies       []HostMaintenancePolicySpec       `json:"host_maintenance_policies,omitempty"`
+}
+
+// HostSpec is the desired state of a cluster host
+type HostSpec struct {
+	Hostname string `json:"hostname"`
+	Enabled  bool   `json:"enabled"`
+}
+
+// WorkloadBalancingPolicySpec is the desired state of a workload balancing policy
+type WorkloadBalancingPolicySpec struct {
+	Name            string                     `json:"name"`
+	BalancingMode   optimization.BalancingMode `json:"balancing_mode"`
+	CPUBalancing    bool                       `json:"cpu_balancing"`
+	MemoryBalancing bool                       `json:"memory_balancing"`
+	Period          int                        `json:"period"`
+	Enabled         bool                       `json:"enabled"`
+}
+
+// WorkloadConsolidationPolicySpec is the desired state of a workload consolidation policy
+type WorkloadConsolidationPolicySpec struct {
+	Name    string `json:"name"`
+	Period  int    `json:"period"`
+	Enabled bool   `json:"enabled"`
+}
+
+// HostMaintenancePolicySpec is the desired state of a host maintenance policy
+type HostMaintenancePolicySpec struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+// Validate checks that the spec can be planned: the cluster has a name,
+// hosts, excluded VMs and policies are uniquely named and balancing modes
+// are supported
+func (s *Spec) Validate() error {
+	if s.Name == "" {
+		return &common.ValidationError{Field: "name", Message: "is required"}
+	}
+
+	hostnames := make([]string, 0, len(s.Hosts))
+	for _, host := range s.Hosts {
+		hostnames = append(hostnames, host.Hostname)
+	}
+	if err := checkUnique("hosts", hostnames); err != nil {
+		return err
+	}
+
+	if err := checkUnique("excluded_vms", s.ExcludedVMs); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(s.WorkloadBalancingPolicies))
+	for _, policy := range s.WorkloadBalancingPolicies {
+		if !policy.BalancingMode.IsValid() {
+			return &common.ValidationError{Field: "workload_balancing_policies",
+				Message: fmt.Sprintf("policy %q has unsupported balancing mode %q", policy.Name, policy.BalancingMode)}
+		}
+		names = append(names, policy.Name)
+	}
+	if err := checkUnique("workload_balancing_policies", names); err != nil {
+		return err
+	}
+
+	names = make([]string, 0, len(s.WorkloadConsolidationPolicies))
+	for _, policy := range s.WorkloadConsolidationPolicies {
+		names = append(names, policy.Name)
+	}
+	if err := checkUnique("workload_consolidation_policies", names); err != nil {
+		return err
+	}
+
+	names = make([]string, 0, len(s.HostMaintenancePolicies))
+	for _, policy := range s.HostMaintenancePolicies {
+		names = append(names, policy.Name)
+	}
+	return checkUnique("host_maintenance_policies", names)
+}
+
+// checkUnique returns a validation error for empty or duplicated keys
+func checkUnique(field string, keys []string) error {
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if key == "" {
+			return &common.ValidationError{Field: field, Message: "entries must have a name"}
+		}
+		if seen[key] {
+			return &common.ValidationError{Field: field, Message: fmt.Sprintf("duplicate entry %q", key)}
+		}
+		seen[key] = true
+	}
+	return nil
+}
@@ -15,6 +15,9 @@ type Cluster struct {
 	Description string `json:"description,omitempty"`
 	CreatedAt   string `json:"created_at"`           // String olarak değiştir
 	UpdatedAt   string `json:"updated_at,omitempty"` // String olarak değiştir
+
+	// ETag is the entity tag sent by the server, if it supports them
+	ETag string `json:"-"`
 }
 
 // ClusterCreate represents cluster creation request
@@ -23,10 +26,14 @@ type ClusterCreate struct {
 	Description string `json:"description,omitempty"`
 }
 
-// ClusterUpdate represents cluster update request
+// ClusterUpdate represents cluster update request. Only non-nil fields are
+// sent; a pointer to the zero value clears the field. Like the other update
+// requests it never sends JSON null: Safir resources have no nullable
+// fields, so a field is either left as it is or set, if only to its zero
+// value.
 type ClusterUpdate struct {
-	Name        string `json:"name,omitempty"`
-	Description string `json:"description,omitempty"`
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
 }
 
 // ClusterHost represents a host in a cluster
@@ -37,6 +44,9 @@ type ClusterHost struct {
 	Enabled   bool   `json:"enabled"`
 	CreatedAt string `json:"created_at"`           // String olarak değiştir
 	UpdatedAt string `json:"updated_at,omitempty"` // String olarak değiştir
+
+	// ETag is the entity tag sent by the server, if it supports them
+	ETag string `json:"-"`
 }
 
 // ClusterHostCreate represents host creation request
@@ -45,10 +55,11 @@ type ClusterHostCreate struct {
 	Enabled  bool   `json:"enabled"`
 }
 
-// ClusterHostUpdate represents host update request
+// ClusterHostUpdate represents host update request. Only non-nil fields are
+// sent.
 type ClusterHostUpdate struct {
-	Hostname string `json:"hostname,omitempty"`
-	Enabled  *bool  `json:"enabled,omitempty"`
+	Hostname *string `json:"hostname,omitempty"`
+	Enabled  *bool   `json:"enabled,omitempty"`
 }
 
 // ClusterExcludedVM represents an excluded VM
@@ -73,6 +84,9 @@ type HostMaintenancePolicy struct {
 	Enabled   bool   `json:"enabled"`
 	CreatedAt string `json:"created_at"`           // String olarak değiştir
 	UpdatedAt string `json:"updated_at,omitempty"` // String olarak değiştir
+
+	// ETag is the entity tag sent by the server, if it supports them
+	ETag string `json:"-"`
 }
 
 // HostMaintenancePolicyCreate represents policy creation request
@@ -82,47 +96,52 @@ type HostMaintenancePolicyCreate struct {
 	Enabled   bool   `json:"enabled"`
 }
 
-// HostMaintenancePolicyUpdate represents policy update request
+// HostMaintenancePolicyUpdate represents policy update request. Only non-nil
+// fields are sent.
 type HostMaintenancePolicyUpdate struct {
-	ClusterID string `json:"cluster_id,omitempty"`
-	Name      string `json:"name,omitempty"`
-	Enabled   *bool  `json:"enabled,omitempty"`
+	ClusterID *string `json:"cluster_id,omitempty"`
+	Name      *string `json:"name,omitempty"`
+	Enabled   *bool   `json:"enabled,omitempty"`
 }
 
 // WorkloadBalancingPolicy represents a workload balancing policy
 type WorkloadBalancingPolicy struct {
-	ID              string `json:"id"`
-	ClusterID       string `json:"cluster_id"`
-	Name            string `json:"name"`
-	BalancingMode   string `json:"balancing_mode"`
-	CPUBalancing    bool   `json:"cpu_balancing"`
-	MemoryBalancing bool   `json:"memory_balancing"`
-	Period          int    `json:"period"`
-	Enabled         bool   `json:"enabled"`
-	CreatedAt       string `json:"created_at"`           // String olarak değiştir
-	UpdatedAt       string `json:"updated_at,omitempty"` // String olarak değiştir
+	ID              string        `json:"id"`
+	ClusterID       string        `json:"cluster_id"`
+	Name            string        `json:"name"`
+	BalancingMode   BalancingMode `json:"balancing_mode"`
+	CPUBalancing    bool          `json:"cpu_balancing"`
+	MemoryBalancing bool          `json:"memory_balancing"`
+	Period          int           `json:"period"`
+	Enabled         bool          `json:"enabled"`
+	CreatedAt       string        `json:"created_at"`           // String olarak değiştir
+	UpdatedAt       string        `json:"updated_at,omitempty"` // String olarak değiştir
+
+	// ETag is the entity tag sent by the server, if it supports them
+	ETag string `json:"-"`
 }
 
 // WorkloadBalancingPolicyCreate represents policy creation request
 type WorkloadBalancingPolicyCreate struct {
-	ClusterID       string `json:"cluster_id"`
-	Name            string `json:"name"`
-	BalancingMode   string `json:"balancing_mode"`
-	CPUBalancing    bool   `json:"cpu_balancing"`
-	MemoryBalancing bool   `json:"memory_balancing"`
-	Period          int    `json:"period"`
-	Enabled         bool   `json:"enabled"`
+	ClusterID       string        `json:"cluster_id"`
+	Name            string        `json:"name"`
+	BalancingMode   BalancingMode `json:"balancing_mode"`
+	CPUBalancing    bool          `json:"cpu_balancing"`
+	MemoryBalancing bool          `json:"memory_balancing"`
+	Period          int           `json:"period"`
+	Enabled         bool          `json:"enabled"`
 }
 
-// WorkloadBalancingPolicyUpdate represents policy update request
+// WorkloadBalancingPolicyUpdate represents policy update request. Only
+// non-nil fields are sent.
 type WorkloadBalancingPolicyUpdate struct {
-	ClusterID       string `json:"cluster_id,omitempty"`
-	Name            string `json:"name,omitempty"`
-	BalancingMode   string `json:"balancing_mode,omitempty"`
-	CPUBalancing    *bool  `json:"cpu_balancing,omitempty"`
-	MemoryBalancing *bool  `json:"memory_balancing,omitempty"`
-	Period          int    `json:"period,omitempty"`
-	Enabled         *bool  `json:"enabled,omitempty"`
+	ClusterID       *string        `json:"cluster_id,omitempty"`
+	Name            *string        `json:"name,omitempty"`
+	BalancingMode   *BalancingMode `json:"balancing_mode,omitempty"`
+	CPUBalancing    *bool          `json:"cpu_balancing,omitempty"`
+	MemoryBalancing *bool          `json:"memory_balancing,omitempty"`
+	Period          *int           `json:"period,omitempty"`
+	Enabled         *bool          `json:"enabled,omitempty"`
 }
 
 // WorkloadConsolidationPolicy represents a workload consolidation policy
@@ -134,6 +153,9 @@ type WorkloadConsolidationPolicy struct {
 	Enabled   bool   `json:"enabled"`
 	CreatedAt string `json:"created_at"`           // String olarak değiştir
 	UpdatedAt string `json:"updated_at,omitempty"` // String olarak değiştir
+
+	// ETag is the entity tag sent by the server, if it supports them
+	ETag string `json:"-"`
 }
 
 // WorkloadConsolidationPolicyCreate represents policy creation request
@@ -144,12 +166,13 @@ type WorkloadConsolidationPolicyCreate struct {
 	Enabled   bool   `json:"enabled"`
 }
 
-// WorkloadConsolidationPolicyUpdate represents policy update request
+// WorkloadConsolidationPolicyUpdate represents policy update request. Only
+// non-nil fields are sent.
 type WorkloadConsolidationPolicyUpdate struct {
-	ClusterID string `json:"cluster_id,omitempty"`
-	Name      string `json:"name,omitempty"`
-	Period    int    `json:"period,omitempty"`
-	Enabled   *bool  `json:"enabled,omitempty"`
+	ClusterID *string `json:"cluster_id,omitempty"`
+	Name      *string `json:"name,omitempty"`
+	Period    *int    `json:"period,omitempty"`
+	Enabled   *bool   `json:"enabled,omitempty"`
 }
 
 // Response wrappers for API responses
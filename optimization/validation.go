@@ -0,0 +1,233 @@
+package optimization
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/overwatch144/golang-safirclient/common"
+)
+
+const maxHostnameLength = 253
+
+// Validate checks the cluster creation request
+func (r *ClusterCreate) Validate() error {
+	if r == nil {
+		return nilRequest()
+	}
+
+	var errs common.ValidationErrors
+	requireString(&errs, "name", r.Name)
+	return errs.Err()
+}
+
+// Validate checks the cluster update request
+func (r *ClusterUpdate) Validate() error {
+	if r == nil {
+		return nilRequest()
+	}
+
+	var errs common.ValidationErrors
+	rejectEmpty(&errs, "name", r.Name)
+	return errs.Err()
+}
+
+// Validate checks the host creation request
+func (r *ClusterHostCreate) Validate() error {
+	if r == nil {
+		return nilRequest()
+	}
+
+	var errs common.ValidationErrors
+	if requireString(&errs, "hostname", r.Hostname) {
+		checkHostname(&errs, "hostname", r.Hostname)
+	}
+	return errs.Err()
+}
+
+// Validate checks the host update request
+func (r *ClusterHostUpdate) Validate() error {
+	if r == nil {
+		return nilRequest()
+	}
+
+	var errs common.ValidationErrors
+	if rejectEmpty(&errs, "hostname", r.Hostname) {
+		checkHostname(&errs, "hostname", *r.Hostname)
+	}
+	return errs.Err()
+}
+
+// Validate checks the excluded VM creation request
+func (r *ClusterExcludedVMCreate) Validate() error {
+	if r == nil {
+		return nilRequest()
+	}
+
+	var errs common.ValidationErrors
+	requireString(&errs, "vm_name", r.VMName)
+	return errs.Err()
+}
+
+// Validate checks the host maintenance policy creation request
+func (r *HostMaintenancePolicyCreate) Validate() error {
+	if r == nil {
+		return nilRequest()
+	}
+
+	var errs common.ValidationErrors
+	requireString(&errs, "cluster_id", r.ClusterID)
+	requireString(&errs, "name", r.Name)
+	return errs.Err()
+}
+
+// Validate checks the host maintenance policy update request
+func (r *HostMaintenancePolicyUpdate) Validate() error {
+	if r == nil {
+		return nilRequest()
+	}
+
+	var errs common.ValidationErrors
+	rejectEmpty(&errs, "cluster_id", r.ClusterID)
+	rejectEmpty(&errs, "name", r.Name)
+	return errs.Err()
+}
+
+// Validate checks the workload balancing policy creation request
+func (r *WorkloadBalancingPolicyCreate) Validate() error {
+	if r == nil {
+		return nilRequest()
+	}
+
+	var errs common.ValidationErrors
+	requireString(&errs, "cluster_id", r.ClusterID)
+	requireString(&errs, "name", r.Name)
+	if requireString(&errs, "balancing_mode", string(r.BalancingMode)) {
+		checkBalancingMode(&errs, r.BalancingMode)
+	}
+	if r.Period <= 0 {
+		errs.Add("period", "must be a positive number of seconds")
+	}
+	return errs.Err()
+}
+
+// Validate checks the workload balancing policy update request
+func (r *WorkloadBalancingPolicyUpdate) Validate() error {
+	if r == nil {
+		return nilRequest()
+	}
+
+	var errs common.ValidationErrors
+	rejectEmpty(&errs, "cluster_id", r.ClusterID)
+	rejectEmpty(&errs, "name", r.Name)
+	if r.BalancingMode != nil {
+		checkBalancingMode(&errs, *r.BalancingMode)
+	}
+	if r.Period != nil && *r.Period <= 0 {
+		errs.Add("period", "must be a positive number of seconds")
+	}
+	return errs.Err()
+}
+
+// Validate checks the workload consolidation policy creation request
+func (r *WorkloadConsolidationPolicyCreate) Validate() error {
+	if r == nil {
+		return nilRequest()
+	}
+
+	var errs common.ValidationErrors
+	requireString(&errs, "cluster_id", r.ClusterID)
+	requireString(&errs, "name", r.Name)
+	if r.Period <= 0 {
+		errs.Add("period", "must be a positive number of seconds")
+	}
+	return errs.Err()
+}
+
+// Validate checks the workload consolidation policy update request
+func (r *WorkloadConsolidationPolicyUpdate) Validate() error {
+	if r == nil {
+		return nilRequest()
+	}
+
+	var errs common.ValidationErrors
+	rejectEmpty(&errs, "cluster_id", r.ClusterID)
+	rejectEmpty(&errs, "name", r.Name)
+	if r.Period != nil && *r.Period <= 0 {
+		errs.Add("period", "must be a positive number of seconds")
+	}
+	return errs.Err()
+}
+
+// requireID rejects an empty resource ID before it ends up in a URL path
+func requireID(field, id string) error {
+	if strings.TrimSpace(id) == "" {
+		return &common.ValidationError{Field: field, Message: "is required"}
+	}
+	return nil
+}
+
+func nilRequest() error {
+	return &common.ValidationError{Field: "request", Message: "cannot be nil"}
+}
+
+// requireString records an error for an empty value and reports whether the
+// value was set
+func requireString(errs *common.ValidationErrors, field, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		errs.Add(field, "is required")
+		return false
+	}
+	return true
+}
+
+// rejectEmpty records an error for an optional field that is set to an empty
+// value and reports whether a non-empty value was set
+func rejectEmpty(errs *common.ValidationErrors, field string, value *string) bool {
+	if value == nil {
+		return false
+	}
+	if strings.TrimSpace(*value) == "" {
+		errs.Add(field, "cannot be empty")
+		return false
+	}
+	return true
+}
+
+func checkBalancingMode(errs *common.ValidationErrors, mode BalancingMode) {
+	if !mode.IsValid() {
+		errs.Add("balancing_mode", fmt.Sprintf("must be one of %s, got %q", joinBalancingModes(), string(mode)))
+	}
+}
+
+// checkHostname validates an RFC 1123 host name, optionally fully qualified
+func checkHostname(errs *common.ValidationErrors, field, hostname string) {
+	if len(hostname) > maxHostnameLength {
+		errs.Add(field, fmt.Sprintf("must be at most %d characters", maxHostnameLength))
+		return
+	}
+
+	for _, label := range strings.Split(strings.TrimSuffix(hostname, "."), ".") {
+		if !validHostnameLabel(label) {
+			errs.Add(field, fmt.Sprintf("%q is not a valid host name", hostname))
+			return
+		}
+	}
+}
+
+func validHostnameLabel(label string) bool {
+	if len(label) == 0 || len(label) > 63 {
+		return false
+	}
+	if label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+
+	for _, ch := range label {
+		switch {
+		case ch >= 'a' && ch <= 'z', ch >= 'A' && ch <= 'Z', ch >= '0' && ch <= '9', ch == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
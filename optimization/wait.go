@@ -0,0 +1,65 @@
+package optimization
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/overwatch144/golang-safirclient/common"
+)
+
+// WaitForClusterDeleted waits until the cluster no longer exists
+func (c *Client) WaitForClusterDeleted(ctx context.Context, clusterID string, opts common.WaitOptions) error {
+	c = c.uncached()
+	return common.WaitForWithOptions(ctx, opts, func() (bool, error) {
+		_, err := c.GetCluster(clusterID)
+		if common.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+}
+
+// WaitForClusterHostDeleted waits until the host no longer exists
+func (c *Client) WaitForClusterHostDeleted(ctx context.Context, clusterID, hostID string, opts common.WaitOptions) error {
+	c = c.uncached()
+	return common.WaitForWithOptions(ctx, opts, func() (bool, error) {
+		_, err := c.GetClusterHost(clusterID, hostID)
+		if common.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+}
+
+// WaitForPolicyEnabled waits until the policy of the given type is enabled
+func (c *Client) WaitForPolicyEnabled(ctx context.Context, policyType PolicyType, policyID string, opts common.WaitOptions) error {
+	c = c.uncached()
+	return common.WaitForWithOptions(ctx, opts, func() (bool, error) {
+		return c.policyEnabled(policyType, policyID)
+	})
+}
+
+func (c *Client) policyEnabled(policyType PolicyType, policyID string) (bool, error) {
+	switch policyType {
+	case PolicyTypeWorkloadBalancing:
+		policy, err := c.GetWorkloadBalancingPolicy(policyID)
+		if err != nil {
+			return false, err
+		}
+		return policy.Enabled, nil
+	case PolicyTypeWorkloadConsolidation:
+		policy, err := c.GetWorkloadConsolidationPolicy(policyID)
+		if err != nil {
+			return false, err
+		}
+		return policy.Enabled, nil
+	case PolicyTypeHostMaintenance:
+		policy, err := c.GetHostMaintenancePolicy(policyID)
+		if err != nil {
+			return false, err
+		}
+		return policy.Enabled, nil
+	}
+
+	return false, fmt.Errorf("unknown policy type %q", policyType)
+}
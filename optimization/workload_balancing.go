@@ -1,12 +1,20 @@
 package optimization
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"net/url"
+
+	"github.com/overwatch144/golang-safirclient/common"
 )
 
 // ListWorkloadBalancingPolicies retrieves all workload balancing policies
 func (c *Client) ListWorkloadBalancingPolicies(clusterID *string) ([]WorkloadBalancingPolicy, error) {
+	if err := c.requireFeature("ListWorkloadBalancingPolicies", common.FeatureWorkloadBalancing); err != nil {
+		return nil, err
+	}
+
 	path := "/workload-balancing"
 	if clusterID != nil {
 		path = fmt.Sprintf("%s?cluster_id=%s", path, *clusterID)
@@ -27,7 +35,15 @@ func (c *Client) ListWorkloadBalancingPolicies(clusterID *string) ([]WorkloadBal
 
 // GetWorkloadBalancingPolicy retrieves a specific workload balancing policy by ID
 func (c *Client) GetWorkloadBalancingPolicy(policyID string) (*WorkloadBalancingPolicy, error) {
-	path := fmt.Sprintf("/workload-balancing/%s", policyID)
+	if err := c.requireFeature("GetWorkloadBalancingPolicy", common.FeatureWorkloadBalancing); err != nil {
+		return nil, err
+	}
+
+	if err := requireID("policy_id", policyID); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/workload-balancing/%s", url.PathEscape(policyID))
 	resp, err := c.DoRequest(http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
@@ -38,11 +54,20 @@ func (c *Client) GetWorkloadBalancingPolicy(policyID string) (*WorkloadBalancing
 		return nil, err
 	}
 
+	policy.ETag = resp.Header.Get("ETag")
 	return &policy, nil
 }
 
 // CreateWorkloadBalancingPolicy creates a new workload balancing policy
 func (c *Client) CreateWorkloadBalancingPolicy(req *WorkloadBalancingPolicyCreate) (*WorkloadBalancingPolicy, error) {
+	if err := c.requireFeature("CreateWorkloadBalancingPolicy", common.FeatureWorkloadBalancing); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
 	resp, err := c.DoRequest(http.MethodPost, "/workload-balancing", req)
 	if err != nil {
 		return nil, err
@@ -58,8 +83,24 @@ func (c *Client) CreateWorkloadBalancingPolicy(req *WorkloadBalancingPolicyCreat
 
 // UpdateWorkloadBalancingPolicy updates a workload balancing policy
 func (c *Client) UpdateWorkloadBalancingPolicy(policyID string, req *WorkloadBalancingPolicyUpdate) (*WorkloadBalancingPolicy, error) {
-	path := fmt.Sprintf("/workload-balancing/%s", policyID)
-	resp, err := c.DoRequest(http.MethodPut, path, req)
+	return c.updateWorkloadBalancingPolicy(policyID, req, nil)
+}
+
+// updateWorkloadBalancingPolicy performs the update, sending the given extra headers
+func (c *Client) updateWorkloadBalancingPolicy(policyID string, req *WorkloadBalancingPolicyUpdate, header http.Header) (*WorkloadBalancingPolicy, error) {
+	if err := c.requireFeature("UpdateWorkloadBalancingPolicy", common.FeatureWorkloadBalancing); err != nil {
+		return nil, err
+	}
+
+	if err := requireID("policy_id", policyID); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/workload-balancing/%s", url.PathEscape(policyID))
+	resp, err := c.DoRequestWithHeaders(context.Background(), http.MethodPut, path, req, header)
 	if err != nil {
 		return nil, err
 	}
@@ -69,12 +110,21 @@ func (c *Client) UpdateWorkloadBalancingPolicy(policyID string, req *WorkloadBal
 		return nil, err
 	}
 
+	response.Policy.ETag = resp.Header.Get("ETag")
 	return &response.Policy, nil
 }
 
 // DeleteWorkloadBalancingPolicy deletes a workload balancing policy
 func (c *Client) DeleteWorkloadBalancingPolicy(policyID string) error {
-	path := fmt.Sprintf("/workload-balancing/%s", policyID)
+	if err := c.requireFeature("DeleteWorkloadBalancingPolicy", common.FeatureWorkloadBalancing); err != nil {
+		return err
+	}
+
+	if err := requireID("policy_id", policyID); err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/workload-balancing/%s", url.PathEscape(policyID))
 	resp, err := c.DoRequest(http.MethodDelete, path, nil)
 	if err != nil {
 		return err
@@ -1,12 +1,20 @@
 package optimization
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"net/url"
+
+	"github.com/overwatch144/golang-safirclient/common"
 )
 
 // ListWorkloadConsolidationPolicies retrieves all workload consolidation policies
 func (c *Client) ListWorkloadConsolidationPolicies(clusterID *string) ([]WorkloadConsolidationPolicy, error) {
+	if err := c.requireFeature("ListWorkloadConsolidationPolicies", common.FeatureWorkloadConsolidation); err != nil {
+		return nil, err
+	}
+
 	path := "/workload-consolidation"
 	if clusterID != nil {
 		path = fmt.Sprintf("%s?cluster_id=%s", path, *clusterID)
@@ -27,7 +35,15 @@ func (c *Client) ListWorkloadConsolidationPolicies(clusterID *string) ([]Workloa
 
 // GetWorkloadConsolidationPolicy retrieves a specific workload consolidation policy by ID
 func (c *Client) GetWorkloadConsolidationPolicy(policyID string) (*WorkloadConsolidationPolicy, error) {
-	path := fmt.Sprintf("/workload-consolidation/%s", policyID)
+	if err := c.requireFeature("GetWorkloadConsolidationPolicy", common.FeatureWorkloadConsolidation); err != nil {
+		return nil, err
+	}
+
+	if err := requireID("policy_id", policyID); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/workload-consolidation/%s", url.PathEscape(policyID))
 	resp, err := c.DoRequest(http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
@@ -38,11 +54,20 @@ func (c *Client) GetWorkloadConsolidationPolicy(policyID string) (*WorkloadConso
 		return nil, err
 	}
 
+	policy.ETag = resp.Header.Get("ETag")
 	return &policy, nil
 }
 
 // CreateWorkloadConsolidationPolicy creates a new workload consolidation policy
 func (c *Client) CreateWorkloadConsolidationPolicy(req *WorkloadConsolidationPolicyCreate) (*WorkloadConsolidationPolicy, error) {
+	if err := c.requireFeature("CreateWorkloadConsolidationPolicy", common.FeatureWorkloadConsolidation); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
 	resp, err := c.DoRequest(http.MethodPost, "/workload-consolidation", req)
 	if err != nil {
 		return nil, err
@@ -58,8 +83,24 @@ func (c *Client) CreateWorkloadConsolidationPolicy(req *WorkloadConsolidationPol
 
 // UpdateWorkloadConsolidationPolicy updates a workload consolidation policy
 func (c *Client) UpdateWorkloadConsolidationPolicy(policyID string, req *WorkloadConsolidationPolicyUpdate) (*WorkloadConsolidationPolicy, error) {
-	path := fmt.Sprintf("/workload-consolidation/%s", policyID)
-	resp, err := c.DoRequest(http.MethodPut, path, req)
+	return c.updateWorkloadConsolidationPolicy(policyID, req, nil)
+}
+
+// updateWorkloadConsolidationPolicy performs the update, sending the given extra headers
+func (c *Client) updateWorkloadConsolidationPolicy(policyID string, req *WorkloadConsolidationPolicyUpdate, header http.Header) (*WorkloadConsolidationPolicy, error) {
+	if err := c.requireFeature("UpdateWorkloadConsolidationPolicy", common.FeatureWorkloadConsolidation); err != nil {
+		return nil, err
+	}
+
+	if err := requireID("policy_id", policyID); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/workload-consolidation/%s", url.PathEscape(policyID))
+	resp, err := c.DoRequestWithHeaders(context.Background(), http.MethodPut, path, req, header)
 	if err != nil {
 		return nil, err
 	}
@@ -69,12 +110,21 @@ func (c *Client) UpdateWorkloadConsolidationPolicy(policyID string, req *Workloa
 		return nil, err
 	}
 
+	response.Policy.ETag = resp.Header.Get("ETag")
 	return &response.Policy, nil
 }
 
 // DeleteWorkloadConsolidationPolicy deletes a workload consolidation policy
 func (c *Client) DeleteWorkloadConsolidationPolicy(policyID string) error {
-	path := fmt.Sprintf("/workload-consolidation/%s", policyID)
+	if err := c.requireFeature("DeleteWorkloadConsolidationPolicy", common.FeatureWorkloadConsolidation); err != nil {
+		return err
+	}
+
+	if err := requireID("policy_id", policyID); err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/workload-consolidation/%s", url.PathEscape(policyID))
 	resp, err := c.DoRequest(http.MethodDelete, path, nil)
 	if err != nil {
 		return err
@@ -0,0 +1,50 @@
+// Package safir checks a Safir deployment as a whole, across Keystone and
+// every Safir service.
+package safir
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/overwatch144/golang-safirclient/common"
+)
+
+// HealthCheck authenticates like the openstack CLI, from clouds.yaml if
+// OS_CLOUD is set and from the OS_* environment variables otherwise, and
+// checks the Keystone token and clock skew, and the catalog presence,
+// reachability, latency and TLS certificate of Keystone and every Safir
+// service. A failed authentication is reported as a failed token check.
+func HealthCheck(ctx context.Context) *common.HealthReport {
+	return HealthCheckWithOptions(ctx, common.HealthCheckOptions{})
+}
+
+// HealthCheckWithOptions is HealthCheck with tuned thresholds
+func HealthCheckWithOptions(ctx context.Context, opts common.HealthCheckOptions) *common.HealthReport {
+	auth, err := newAuthenticator()
+	if err != nil {
+		return &common.HealthReport{
+			Status:    common.HealthStatusFailed,
+			CheckedAt: time.Now(),
+			Token:     common.TokenHealth{Status: common.HealthStatusFailed, Message: err.Error()},
+		}
+	}
+
+	return auth.HealthCheckWithOptions(ctx, opts)
+}
+
+// newAuthenticator authenticates with the credentials of the environment
+func newAuthenticator() (*common.Authenticator, error) {
+	var authOpts *common.AuthOptions
+	var err error
+	if cloud := os.Getenv("OS_CLOUD"); cloud != "" {
+		authOpts, err = common.AuthOptionsFromCloud(cloud)
+	} else {
+		authOpts, err = common.AuthOptionsFromEnv()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return common.NewAuthenticator(authOpts)
+}
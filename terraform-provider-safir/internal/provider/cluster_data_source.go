@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+// clusterDataSource looks up a cluster by ID or name
+type clusterDataSource struct {
+	dataSourceBase
+}
+
+func newClusterDataSource() datasource.DataSource {
+	return &clusterDataSource{}
+}
+
+// Metadata implements datasource.DataSource
+func (d *clusterDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cluster"
+}
+
+// Schema implements datasource.DataSource
+func (d *clusterDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Looks up a Safir cluster by ID or by name. A name must match exactly one cluster.",
+		Attributes: map[string]schema.Attribute{
+			"id":          schema.StringAttribute{Optional: true, Computed: true},
+			"name":        schema.StringAttribute{Optional: true, Computed: true},
+			"description": schema.StringAttribute{Computed: true},
+			"created_at":  schema.StringAttribute{Computed: true},
+			"updated_at":  schema.StringAttribute{Computed: true},
+		},
+	}
+}
+
+// ConfigValidators implements datasource.DataSourceWithConfigValidators
+func (d *clusterDataSource) ConfigValidators(_ context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(path.MatchRoot("id"), path.MatchRoot("name")),
+	}
+}
+
+// Read implements datasource.DataSource
+func (d *clusterDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config clusterModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var cluster *optimization.Cluster
+	var err error
+	if !config.ID.IsNull() {
+		cluster, err = d.client.GetCluster(config.ID.ValueString())
+	} else {
+		cluster, err = d.client.FindClusterByName(config.Name.ValueString())
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to look up cluster", err.Error())
+		return
+	}
+
+	config.fromAPI(cluster)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
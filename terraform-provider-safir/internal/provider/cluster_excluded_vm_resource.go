@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+// clusterExcludedVMResource manages a VM excluded from optimization. Safir
+// cannot update excluded VMs, so every change replaces them.
+type clusterExcludedVMResource struct {
+	resourceBase
+}
+
+type clusterExcludedVMModel struct {
+	ID        types.String `tfsdk:"id"`
+	ClusterID types.String `tfsdk:"cluster_id"`
+	VMName    types.String `tfsdk:"vm_name"`
+	CreatedAt types.String `tfsdk:"created_at"`
+}
+
+func newClusterExcludedVMResource() resource.Resource {
+	return &clusterExcludedVMResource{}
+}
+
+func (m *clusterExcludedVMModel) fromAPI(vm *optimization.ClusterExcludedVM) {
+	m.ID = types.StringValue(vm.ID)
+	m.ClusterID = types.StringValue(vm.ClusterID)
+	m.VMName = types.StringValue(vm.VMName)
+	m.CreatedAt = types.StringValue(vm.CreatedAt)
+}
+
+// Metadata implements resource.Resource
+func (r *clusterExcludedVMResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cluster_excluded_vm"
+}
+
+// Schema implements resource.Resource
+func (r *clusterExcludedVMResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "A VM excluded from the optimization of a Safir cluster. Import with <cluster_id>/<vm_id>.",
+		Attributes: map[string]schema.Attribute{
+			"id":         idAttribute(),
+			"cluster_id": clusterIDAttribute(),
+			"vm_name": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"created_at": createdAtAttribute(),
+		},
+	}
+}
+
+// Create implements resource.Resource
+func (r *clusterExcludedVMResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan clusterExcludedVMModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	vm, err := r.client.CreateClusterExcludedVM(plan.ClusterID.ValueString(), &optimization.ClusterExcludedVMCreate{
+		VMName: plan.VMName.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create excluded VM", err.Error())
+		return
+	}
+
+	plan.fromAPI(vm)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read implements resource.Resource
+func (r *clusterExcludedVMResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state clusterExcludedVMModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	vm, err := r.client.GetClusterExcludedVM(state.ClusterID.ValueString(), state.ID.ValueString())
+	if common.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read excluded VM", err.Error())
+		return
+	}
+
+	state.fromAPI(vm)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update implements resource.Resource. Every attribute forces a
+// replacement, so there is never anything to update.
+func (r *clusterExcludedVMResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	resp.Diagnostics.AddError("Excluded VMs cannot be updated", "Safir has no update call for excluded VMs.")
+}
+
+// Delete implements resource.Resource
+func (r *clusterExcludedVMResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state clusterExcludedVMModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteClusterExcludedVM(state.ClusterID.ValueString(), state.ID.ValueString())
+	if err != nil && !common.IsNotFound(err) {
+		resp.Diagnostics.AddError("Failed to delete excluded VM", err.Error())
+	}
+}
+
+// ImportState implements resource.ResourceWithImportState
+func (r *clusterExcludedVMResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importNestedID(ctx, req, resp)
+}
@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+)
+
+// clusterHostDataSource looks up a host of a cluster by hostname
+type clusterHostDataSource struct {
+	dataSourceBase
+}
+
+func newClusterHostDataSource() datasource.DataSource {
+	return &clusterHostDataSource{}
+}
+
+// Metadata implements datasource.DataSource
+func (d *clusterHostDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cluster_host"
+}
+
+// Schema implements datasource.DataSource
+func (d *clusterHostDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Looks up a host of a Safir cluster by hostname.",
+		Attributes: map[string]schema.Attribute{
+			"cluster_id": schema.StringAttribute{Required: true},
+			"hostname":   schema.StringAttribute{Required: true},
+			"id":         schema.StringAttribute{Computed: true},
+			"enabled":    schema.BoolAttribute{Computed: true},
+			"created_at": schema.StringAttribute{Computed: true},
+			"updated_at": schema.StringAttribute{Computed: true},
+		},
+	}
+}
+
+// Read implements datasource.DataSource
+func (d *clusterHostDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config clusterHostModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	host, err := d.client.FindClusterHostByHostname(config.ClusterID.ValueString(), config.Hostname.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to look up cluster host", err.Error())
+		return
+	}
+
+	config.fromAPI(host)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
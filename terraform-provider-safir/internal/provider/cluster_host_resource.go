@@ -0,0 +1,149 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+// clusterHostResource manages a host of a Safir cluster
+type clusterHostResource struct {
+	resourceBase
+}
+
+type clusterHostModel struct {
+	ID        types.String `tfsdk:"id"`
+	ClusterID types.String `tfsdk:"cluster_id"`
+	Hostname  types.String `tfsdk:"hostname"`
+	Enabled   types.Bool   `tfsdk:"enabled"`
+	CreatedAt types.String `tfsdk:"created_at"`
+	UpdatedAt types.String `tfsdk:"updated_at"`
+}
+
+func newClusterHostResource() resource.Resource {
+	return &clusterHostResource{}
+}
+
+func (m *clusterHostModel) fromAPI(host *optimization.ClusterHost) {
+	m.ID = types.StringValue(host.ID)
+	m.ClusterID = types.StringValue(host.ClusterID)
+	m.Hostname = types.StringValue(host.Hostname)
+	m.Enabled = types.BoolValue(host.Enabled)
+	m.CreatedAt = types.StringValue(host.CreatedAt)
+	m.UpdatedAt = types.StringValue(host.UpdatedAt)
+}
+
+// Metadata implements resource.Resource
+func (r *clusterHostResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cluster_host"
+}
+
+// Schema implements resource.Resource
+func (r *clusterHostResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "A compute host of a Safir cluster. Import with <cluster_id>/<host_id>.",
+		Attributes: map[string]schema.Attribute{
+			"id":         idAttribute(),
+			"cluster_id": clusterIDAttribute(),
+			"hostname": schema.StringAttribute{
+				Required: true,
+			},
+			"enabled": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(true),
+			},
+			"created_at": createdAtAttribute(),
+			"updated_at": updatedAtAttribute(),
+		},
+	}
+}
+
+// Create implements resource.Resource
+func (r *clusterHostResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan clusterHostModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	host, err := r.client.CreateClusterHost(plan.ClusterID.ValueString(), &optimization.ClusterHostCreate{
+		Hostname: plan.Hostname.ValueString(),
+		Enabled:  plan.Enabled.ValueBool(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create cluster host", err.Error())
+		return
+	}
+
+	plan.fromAPI(host)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read implements resource.Resource
+func (r *clusterHostResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state clusterHostModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	host, err := r.client.GetClusterHost(state.ClusterID.ValueString(), state.ID.ValueString())
+	if common.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read cluster host", err.Error())
+		return
+	}
+
+	state.fromAPI(host)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update implements resource.Resource
+func (r *clusterHostResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan clusterHostModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	host, err := r.client.UpdateClusterHost(plan.ClusterID.ValueString(), plan.ID.ValueString(), &optimization.ClusterHostUpdate{
+		Hostname: plan.Hostname.ValueStringPointer(),
+		Enabled:  plan.Enabled.ValueBoolPointer(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update cluster host", err.Error())
+		return
+	}
+
+	plan.fromAPI(host)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete implements resource.Resource
+func (r *clusterHostResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state clusterHostModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteClusterHost(state.ClusterID.ValueString(), state.ID.ValueString())
+	if err != nil && !common.IsNotFound(err) {
+		resp.Diagnostics.AddError("Failed to delete cluster host", err.Error())
+	}
+}
+
+// ImportState implements resource.ResourceWithImportState
+func (r *clusterHostResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importNestedID(ctx, req, resp)
+}
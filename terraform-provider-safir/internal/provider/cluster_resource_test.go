@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccClusterResource(t *testing.T) {
+	srv := newAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig(srv) + `
+resource "safir_cluster" "test" {
+  name        = "acc-cluster"
+  description = "created by terraform"
+}
+
+resource "safir_cluster_host" "compute" {
+  cluster_id = safir_cluster.test.id
+  hostname   = "compute-01"
+}
+
+resource "safir_cluster_excluded_vm" "db" {
+  cluster_id = safir_cluster.test.id
+  vm_name    = "database-01"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("safir_cluster.test", "id"),
+					resource.TestCheckResourceAttr("safir_cluster.test", "description", "created by terraform"),
+					resource.TestCheckResourceAttr("safir_cluster_host.compute", "enabled", "true"),
+					resource.TestCheckResourceAttrPair("safir_cluster_excluded_vm.db", "cluster_id", "safir_cluster.test", "id"),
+				),
+			},
+			{
+				ResourceName:      "safir_cluster.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: providerConfig(srv) + `
+resource "safir_cluster" "test" {
+  name = "acc-cluster-renamed"
+}
+
+resource "safir_cluster_host" "compute" {
+  cluster_id = safir_cluster.test.id
+  hostname   = "compute-01"
+  enabled    = false
+}
+
+data "safir_cluster" "by_name" {
+  name = safir_cluster.test.name
+}
+
+data "safir_cluster_host" "compute" {
+  cluster_id = safir_cluster.test.id
+  hostname   = safir_cluster_host.compute.hostname
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("safir_cluster.test", "description", ""),
+					resource.TestCheckResourceAttrPair("data.safir_cluster.by_name", "id", "safir_cluster.test", "id"),
+					resource.TestCheckResourceAttr("data.safir_cluster_host.compute", "enabled", "false"),
+				),
+			},
+		},
+	})
+}
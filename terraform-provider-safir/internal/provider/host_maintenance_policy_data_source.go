@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+)
+
+// hostMaintenancePolicyDataSource looks up a host maintenance policy by name
+type hostMaintenancePolicyDataSource struct {
+	dataSourceBase
+}
+
+func newHostMaintenancePolicyDataSource() datasource.DataSource {
+	return &hostMaintenancePolicyDataSource{}
+}
+
+// Metadata implements datasource.DataSource
+func (d *hostMaintenancePolicyDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_host_maintenance_policy"
+}
+
+// Schema implements datasource.DataSource
+func (d *hostMaintenancePolicyDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Looks up a Safir host maintenance policy by name, optionally within one cluster. The name must match exactly one policy.",
+		Attributes: map[string]schema.Attribute{
+			"name":       schema.StringAttribute{Required: true},
+			"cluster_id": schema.StringAttribute{Optional: true, Computed: true},
+			"id":         schema.StringAttribute{Computed: true},
+			"enabled":    schema.BoolAttribute{Computed: true},
+			"created_at": schema.StringAttribute{Computed: true},
+			"updated_at": schema.StringAttribute{Computed: true},
+		},
+	}
+}
+
+// Read implements datasource.DataSource
+func (d *hostMaintenancePolicyDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config hostMaintenancePolicyModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policy, err := d.client.FindHostMaintenancePolicyByName(config.ClusterID.ValueStringPointer(), config.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to look up host maintenance policy", err.Error())
+		return
+	}
+
+	config.fromAPI(policy)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
@@ -0,0 +1,153 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+// hostMaintenancePolicyResource manages a Safir host maintenance policy
+type hostMaintenancePolicyResource struct {
+	resourceBase
+}
+
+type hostMaintenancePolicyModel struct {
+	ID        types.String `tfsdk:"id"`
+	ClusterID types.String `tfsdk:"cluster_id"`
+	Name      types.String `tfsdk:"name"`
+	Enabled   types.Bool   `tfsdk:"enabled"`
+	CreatedAt types.String `tfsdk:"created_at"`
+	UpdatedAt types.String `tfsdk:"updated_at"`
+}
+
+func newHostMaintenancePolicyResource() resource.Resource {
+	return &hostMaintenancePolicyResource{}
+}
+
+func (m *hostMaintenancePolicyModel) fromAPI(policy *optimization.HostMaintenancePolicy) {
+	m.ID = types.StringValue(policy.ID)
+	m.ClusterID = types.StringValue(policy.ClusterID)
+	m.Name = types.StringValue(policy.Name)
+	m.Enabled = types.BoolValue(policy.Enabled)
+	m.CreatedAt = types.StringValue(policy.CreatedAt)
+	m.UpdatedAt = types.StringValue(policy.UpdatedAt)
+}
+
+// Metadata implements resource.Resource
+func (r *hostMaintenancePolicyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_host_maintenance_policy"
+}
+
+// Schema implements resource.Resource
+func (r *hostMaintenancePolicyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "A Safir host maintenance policy. Import by policy ID.",
+		Attributes: map[string]schema.Attribute{
+			"id": idAttribute(),
+			"cluster_id": schema.StringAttribute{
+				Required: true,
+			},
+			"name": schema.StringAttribute{
+				Required: true,
+			},
+			"enabled": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(true),
+			},
+			"created_at": createdAtAttribute(),
+			"updated_at": updatedAtAttribute(),
+		},
+	}
+}
+
+// Create implements resource.Resource
+func (r *hostMaintenancePolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan hostMaintenancePolicyModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policy, err := r.client.CreateHostMaintenancePolicy(&optimization.HostMaintenancePolicyCreate{
+		ClusterID: plan.ClusterID.ValueString(),
+		Name:      plan.Name.ValueString(),
+		Enabled:   plan.Enabled.ValueBool(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create host maintenance policy", err.Error())
+		return
+	}
+
+	plan.fromAPI(policy)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read implements resource.Resource
+func (r *hostMaintenancePolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state hostMaintenancePolicyModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policy, err := r.client.GetHostMaintenancePolicy(state.ID.ValueString())
+	if common.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read host maintenance policy", err.Error())
+		return
+	}
+
+	state.fromAPI(policy)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update implements resource.Resource
+func (r *hostMaintenancePolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan hostMaintenancePolicyModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policy, err := r.client.UpdateHostMaintenancePolicy(plan.ID.ValueString(), &optimization.HostMaintenancePolicyUpdate{
+		ClusterID: plan.ClusterID.ValueStringPointer(),
+		Name:      plan.Name.ValueStringPointer(),
+		Enabled:   plan.Enabled.ValueBoolPointer(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update host maintenance policy", err.Error())
+		return
+	}
+
+	plan.fromAPI(policy)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete implements resource.Resource
+func (r *hostMaintenancePolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state hostMaintenancePolicyModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteHostMaintenancePolicy(state.ID.ValueString()); err != nil && !common.IsNotFound(err) {
+		resp.Diagnostics.AddError("Failed to delete host maintenance policy", err.Error())
+	}
+}
+
+// ImportState implements resource.ResourceWithImportState
+func (r *hostMaintenancePolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccPolicyResources(t *testing.T) {
+	srv := newAccFakeServer(t)
+
+	cluster := `
+resource "safir_cluster" "test" {
+  name = "acc-policies"
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig(srv) + cluster + `
+resource "safir_workload_balancing_policy" "test" {
+  cluster_id     = safir_cluster.test.id
+  name           = "balancing"
+  balancing_mode = "moderate"
+  cpu_balancing  = true
+  period         = 3600
+}
+
+resource "safir_workload_consolidation_policy" "test" {
+  cluster_id = safir_cluster.test.id
+  name       = "consolidation"
+  period     = 86400
+  enabled    = false
+}
+
+resource "safir_host_maintenance_policy" "test" {
+  cluster_id = safir_cluster.test.id
+  name       = "maintenance"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("safir_workload_balancing_policy.test", "memory_balancing", "false"),
+					resource.TestCheckResourceAttr("safir_workload_balancing_policy.test", "enabled", "true"),
+					resource.TestCheckResourceAttr("safir_workload_consolidation_policy.test", "enabled", "false"),
+					resource.TestCheckResourceAttr("safir_host_maintenance_policy.test", "enabled", "true"),
+				),
+			},
+			{
+				ResourceName:      "safir_workload_balancing_policy.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: providerConfig(srv) + cluster + `
+resource "safir_workload_balancing_policy" "test" {
+  cluster_id     = safir_cluster.test.id
+  name           = "balancing"
+  balancing_mode = "aggressive"
+  period         = 600
+}
+
+data "safir_workload_balancing_policy" "test" {
+  cluster_id = safir_cluster.test.id
+  name       = safir_workload_balancing_policy.test.name
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("safir_workload_balancing_policy.test", "cpu_balancing", "false"),
+					resource.TestCheckResourceAttr("data.safir_workload_balancing_policy.test", "balancing_mode", "aggressive"),
+					resource.TestCheckResourceAttr("data.safir_workload_balancing_policy.test", "period", "600"),
+				),
+			},
+		},
+	})
+}
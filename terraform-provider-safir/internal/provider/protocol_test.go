@@ -0,0 +1,342 @@
+package provider
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/overwatch144/golang-safirclient/fakesafir"
+)
+
+// The tests in this file drive the provider through the plugin protocol the
+// way Terraform does, so that they run without TF_ACC and a terraform
+// binary. They cover less of the Terraform semantics than the acceptance
+// tests.
+
+// protocol calls the provider server like Terraform core
+type protocol struct {
+	t       *testing.T
+	ctx     context.Context
+	server  tfprotov6.ProviderServer
+	schemas *tfprotov6.GetProviderSchemaResponse
+}
+
+func newProtocol(t *testing.T, srv *fakesafir.Server) *protocol {
+	t.Helper()
+
+	server, err := providerserver.NewProtocol6WithError(New("test")())()
+	if err != nil {
+		t.Fatalf("NewProtocol6WithError: %v", err)
+	}
+
+	p := &protocol{t: t, ctx: context.Background(), server: server}
+	p.schemas, err = server.GetProviderSchema(p.ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("GetProviderSchema: %v", err)
+	}
+	p.check("GetProviderSchema", p.schemas.Diagnostics)
+
+	config := p.encode(p.schemas.Provider, map[string]any{
+		"auth_url":       srv.IdentityEndpoint(),
+		"username":       fakesafir.Username,
+		"password":       fakesafir.Password,
+		"user_domain_id": fakesafir.DomainID,
+		"project_name":   fakesafir.ProjectName,
+	})
+	resp, err := server.ConfigureProvider(p.ctx, &tfprotov6.ConfigureProviderRequest{Config: config})
+	if err != nil {
+		t.Fatalf("ConfigureProvider: %v", err)
+	}
+	p.check("ConfigureProvider", resp.Diagnostics)
+
+	return p
+}
+
+func (p *protocol) check(call string, diags []*tfprotov6.Diagnostic) {
+	p.t.Helper()
+
+	for _, d := range diags {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			p.t.Fatalf("%s: %s: %s", call, d.Summary, d.Detail)
+		}
+	}
+}
+
+// encode builds an object of the schema; missing attributes are null
+func (p *protocol) encode(schema *tfprotov6.Schema, attrs map[string]any) *tfprotov6.DynamicValue {
+	p.t.Helper()
+
+	objectType := schema.ValueType().(tftypes.Object)
+	value := tftypes.NewValue(objectType, nil)
+	if attrs != nil {
+		values := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+		for name, attrType := range objectType.AttributeTypes {
+			values[name] = tftypes.NewValue(attrType, attrs[name])
+		}
+		value = tftypes.NewValue(objectType, values)
+	}
+
+	dv, err := tfprotov6.NewDynamicValue(objectType, value)
+	if err != nil {
+		p.t.Fatalf("NewDynamicValue: %v", err)
+	}
+	return &dv
+}
+
+// decode returns the attributes of an object of the schema, nil if the
+// object is null
+func (p *protocol) decode(schema *tfprotov6.Schema, dv *tfprotov6.DynamicValue) map[string]any {
+	p.t.Helper()
+
+	value, err := dv.Unmarshal(schema.ValueType())
+	if err != nil {
+		p.t.Fatalf("Unmarshal: %v", err)
+	}
+	if value.IsNull() {
+		return nil
+	}
+
+	var values map[string]tftypes.Value
+	if err := value.As(&values); err != nil {
+		p.t.Fatalf("As: %v", err)
+	}
+
+	attrs := make(map[string]any, len(values))
+	for name, v := range values {
+		switch {
+		case v.IsNull():
+		case v.Type().Is(tftypes.String):
+			var s string
+			_ = v.As(&s)
+			attrs[name] = s
+		case v.Type().Is(tftypes.Bool):
+			var b bool
+			_ = v.As(&b)
+			attrs[name] = b
+		case v.Type().Is(tftypes.Number):
+			var n big.Float
+			_ = v.As(&n)
+			i, _ := n.Int64()
+			attrs[name] = i
+		}
+	}
+	return attrs
+}
+
+// apply plans and applies config on top of state, which is nil for a
+// create, and returns the new state
+func (p *protocol) apply(typeName string, state, config map[string]any) map[string]any {
+	p.t.Helper()
+
+	schema := p.schemas.ResourceSchemas[typeName]
+
+	// Like Terraform core, propose the prior value for unset attributes
+	proposed := make(map[string]any, len(config))
+	for name, value := range state {
+		proposed[name] = value
+	}
+	for name, value := range config {
+		proposed[name] = value
+	}
+	if state == nil {
+		proposed = config
+	}
+
+	plan, err := p.server.PlanResourceChange(p.ctx, &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         typeName,
+		PriorState:       p.encode(schema, state),
+		ProposedNewState: p.encode(schema, proposed),
+		Config:           p.encode(schema, config),
+	})
+	if err != nil {
+		p.t.Fatalf("PlanResourceChange: %v", err)
+	}
+	p.check("PlanResourceChange", plan.Diagnostics)
+	if len(plan.RequiresReplace) > 0 {
+		p.t.Fatalf("plan of %s requires replacement: %v", typeName, plan.RequiresReplace)
+	}
+
+	resp, err := p.server.ApplyResourceChange(p.ctx, &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:     typeName,
+		PriorState:   p.encode(schema, state),
+		PlannedState: plan.PlannedState,
+		Config:       p.encode(schema, config),
+	})
+	if err != nil {
+		p.t.Fatalf("ApplyResourceChange: %v", err)
+	}
+	p.check("ApplyResourceChange", resp.Diagnostics)
+
+	return p.decode(schema, resp.NewState)
+}
+
+// destroy deletes the resource in state
+func (p *protocol) destroy(typeName string, state map[string]any) {
+	p.t.Helper()
+
+	schema := p.schemas.ResourceSchemas[typeName]
+	resp, err := p.server.ApplyResourceChange(p.ctx, &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:     typeName,
+		PriorState:   p.encode(schema, state),
+		PlannedState: p.encode(schema, nil),
+		Config:       p.encode(schema, nil),
+	})
+	if err != nil {
+		p.t.Fatalf("ApplyResourceChange: %v", err)
+	}
+	p.check("ApplyResourceChange", resp.Diagnostics)
+}
+
+// read refreshes state; it returns nil once the resource is gone
+func (p *protocol) read(typeName string, state map[string]any) map[string]any {
+	p.t.Helper()
+
+	schema := p.schemas.ResourceSchemas[typeName]
+	resp, err := p.server.ReadResource(p.ctx, &tfprotov6.ReadResourceRequest{
+		TypeName:     typeName,
+		CurrentState: p.encode(schema, state),
+	})
+	if err != nil {
+		p.t.Fatalf("ReadResource: %v", err)
+	}
+	p.check("ReadResource", resp.Diagnostics)
+
+	return p.decode(schema, resp.NewState)
+}
+
+// importState imports a resource by ID and refreshes it
+func (p *protocol) importState(typeName, id string) map[string]any {
+	p.t.Helper()
+
+	resp, err := p.server.ImportResourceState(p.ctx, &tfprotov6.ImportResourceStateRequest{TypeName: typeName, ID: id})
+	if err != nil {
+		p.t.Fatalf("ImportResourceState: %v", err)
+	}
+	p.check("ImportResourceState", resp.Diagnostics)
+
+	schema := p.schemas.ResourceSchemas[typeName]
+	return p.read(typeName, p.decode(schema, resp.ImportedResources[0].State))
+}
+
+// readDataSource reads a data source with the given config
+func (p *protocol) readDataSource(typeName string, config map[string]any) map[string]any {
+	p.t.Helper()
+
+	schema := p.schemas.DataSourceSchemas[typeName]
+	resp, err := p.server.ReadDataSource(p.ctx, &tfprotov6.ReadDataSourceRequest{
+		TypeName: typeName,
+		Config:   p.encode(schema, config),
+	})
+	if err != nil {
+		p.t.Fatalf("ReadDataSource: %v", err)
+	}
+	p.check("ReadDataSource", resp.Diagnostics)
+
+	return p.decode(schema, resp.State)
+}
+
+func TestProtocolClusterLifecycle(t *testing.T) {
+	srv := newAccFakeServer(t)
+	p := newProtocol(t, srv)
+
+	cluster := p.apply("safir_cluster", nil, map[string]any{"name": "cluster"})
+	if cluster["id"] == nil || cluster["description"] != "" || cluster["created_at"] == nil {
+		t.Fatalf("created cluster state = %v", cluster)
+	}
+
+	host := p.apply("safir_cluster_host", nil, map[string]any{"cluster_id": cluster["id"], "hostname": "compute-01"})
+	if host["enabled"] != true {
+		t.Errorf("host enabled = %v, want the default true", host["enabled"])
+	}
+
+	cluster = p.apply("safir_cluster", cluster, map[string]any{"name": "cluster", "description": "changed"})
+	if cluster["description"] != "changed" {
+		t.Errorf("updated cluster state = %v", cluster)
+	}
+
+	imported := p.importState("safir_cluster_host", cluster["id"].(string)+"/"+host["id"].(string))
+	if imported["hostname"] != "compute-01" || imported["cluster_id"] != cluster["id"] {
+		t.Errorf("imported host state = %v", imported)
+	}
+
+	found := p.readDataSource("safir_cluster", map[string]any{"name": "cluster"})
+	if found["id"] != cluster["id"] || found["description"] != "changed" {
+		t.Errorf("data source state = %v", found)
+	}
+
+	// Resources deleted outside of Terraform drop out of the state
+	p.destroy("safir_cluster_host", host)
+	if state := p.read("safir_cluster_host", host); state != nil {
+		t.Errorf("state of a deleted host = %v, want none", state)
+	}
+	p.destroy("safir_cluster", cluster)
+	if srv.Count("clusters") != 0 {
+		t.Errorf("fake still has %d clusters", srv.Count("clusters"))
+	}
+}
+
+func TestProtocolWorkloadBalancingPolicy(t *testing.T) {
+	srv := newAccFakeServer(t)
+	p := newProtocol(t, srv)
+
+	cluster := p.apply("safir_cluster", nil, map[string]any{"name": "cluster"})
+	config := map[string]any{
+		"cluster_id":     cluster["id"],
+		"name":           "balancing",
+		"balancing_mode": "moderate",
+		"period":         3600,
+	}
+	policy := p.apply("safir_workload_balancing_policy", nil, config)
+	if policy["enabled"] != true || policy["cpu_balancing"] != false || policy["period"] != int64(3600) {
+		t.Errorf("created policy state = %v", policy)
+	}
+
+	config["balancing_mode"] = "aggressive"
+	config["enabled"] = false
+	policy = p.apply("safir_workload_balancing_policy", policy, config)
+	if policy["balancing_mode"] != "aggressive" || policy["enabled"] != false {
+		t.Errorf("updated policy state = %v", policy)
+	}
+
+	imported := p.importState("safir_workload_balancing_policy", policy["id"].(string))
+	for _, name := range []string{"cluster_id", "name", "balancing_mode", "period", "enabled"} {
+		if imported[name] != policy[name] {
+			t.Errorf("imported %s = %v, want %v", name, imported[name], policy[name])
+		}
+	}
+
+	found := p.readDataSource("safir_workload_balancing_policy", map[string]any{"name": "balancing"})
+	if found["id"] != policy["id"] || found["cluster_id"] != cluster["id"] {
+		t.Errorf("data source state = %v", found)
+	}
+
+	p.destroy("safir_workload_balancing_policy", policy)
+	if srv.Count("workload_balancing") != 0 {
+		t.Error("policy was not deleted")
+	}
+}
+
+func TestProtocolRejectsUnknownBalancingMode(t *testing.T) {
+	p := newProtocol(t, newAccFakeServer(t))
+
+	schema := p.schemas.ResourceSchemas["safir_workload_balancing_policy"]
+	resp, err := p.server.ValidateResourceConfig(p.ctx, &tfprotov6.ValidateResourceConfigRequest{
+		TypeName: "safir_workload_balancing_policy",
+		Config: p.encode(schema, map[string]any{
+			"cluster_id":     "cluster",
+			"name":           "balancing",
+			"balancing_mode": "extreme",
+			"period":         3600,
+		}),
+	})
+	if err != nil {
+		t.Fatalf("ValidateResourceConfig: %v", err)
+	}
+	if len(resp.Diagnostics) == 0 {
+		t.Error("unknown balancing mode passed validation")
+	}
+}
@@ -0,0 +1,265 @@
+// Package provider implements the Terraform provider for Safir Optimization
+// on top of the optimization package.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+// safirProvider is the provider implementation
+type safirProvider struct {
+	version string
+}
+
+// providerModel is the provider configuration
+type providerModel struct {
+	Cloud             types.String `tfsdk:"cloud"`
+	AuthURL           types.String `tfsdk:"auth_url"`
+	Username          types.String `tfsdk:"username"`
+	Password          types.String `tfsdk:"password"`
+	UserDomainID      types.String `tfsdk:"user_domain_id"`
+	UserDomainName    types.String `tfsdk:"user_domain_name"`
+	ProjectName       types.String `tfsdk:"project_name"`
+	ProjectDomainID   types.String `tfsdk:"project_domain_id"`
+	ProjectDomainName types.String `tfsdk:"project_domain_name"`
+	Endpoint          types.String `tfsdk:"endpoint"`
+	Token             types.String `tfsdk:"token"`
+}
+
+// New returns a function creating the provider, as expected by providerserver
+func New(version string) func() provider.Provider {
+	return func() provider.Provider {
+		return &safirProvider{version: version}
+	}
+}
+
+// Metadata implements provider.Provider
+func (p *safirProvider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "safir"
+	resp.Version = p.version
+}
+
+// Schema implements provider.Provider
+func (p *safirProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp *provider.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages Safir Optimization clusters, hosts, excluded VMs and policies. " +
+			"Without auth_url or cloud the credentials are read from the OS_* environment variables, like the openstack CLI.",
+		Attributes: map[string]schema.Attribute{
+			"cloud": schema.StringAttribute{
+				Description: "Entry of clouds.yaml to authenticate with. Defaults to OS_CLOUD.",
+				Optional:    true,
+			},
+			"auth_url": schema.StringAttribute{
+				Description: "Keystone v3 endpoint.",
+				Optional:    true,
+			},
+			"username": schema.StringAttribute{
+				Optional: true,
+			},
+			"password": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+			},
+			"user_domain_id": schema.StringAttribute{
+				Optional: true,
+			},
+			"user_domain_name": schema.StringAttribute{
+				Optional: true,
+			},
+			"project_name": schema.StringAttribute{
+				Optional: true,
+			},
+			"project_domain_id": schema.StringAttribute{
+				Optional: true,
+			},
+			"project_domain_name": schema.StringAttribute{
+				Optional: true,
+			},
+			"endpoint": schema.StringAttribute{
+				Description: "Safir Optimization endpoint, skips the catalog lookup. Defaults to SAFIR_ENDPOINT.",
+				Optional:    true,
+			},
+			"token": schema.StringAttribute{
+				Description: "Keystone token to use with endpoint. Defaults to OS_TOKEN.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+		},
+	}
+}
+
+// Configure implements provider.Provider
+func (p *safirProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	var config providerModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, err := config.newClient()
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create Safir client", err.Error())
+		return
+	}
+
+	resp.DataSourceData = client
+	resp.ResourceData = client
+}
+
+// newClient creates the client like the safir CLI: directly with endpoint
+// and token, or through Keystone with the configured, clouds.yaml or
+// environment credentials
+func (m *providerModel) newClient() (*optimization.Client, error) {
+	endpoint := valueOrEnv(m.Endpoint, "SAFIR_ENDPOINT")
+	token := valueOrEnv(m.Token, "OS_TOKEN")
+	if endpoint != "" && token != "" {
+		return optimization.NewClientWithToken(endpoint, token), nil
+	}
+
+	var authOpts *common.AuthOptions
+	var err error
+	switch cloud := valueOrEnv(m.Cloud, "OS_CLOUD"); {
+	case m.AuthURL.ValueString() != "":
+		authOpts = m.authOptions()
+	case cloud != "":
+		authOpts, err = common.AuthOptionsFromCloud(cloud)
+	default:
+		authOpts, err = common.AuthOptionsFromEnv()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load credentials: %w", err)
+	}
+
+	auth, err := common.NewAuthenticator(authOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	if endpoint != "" {
+		token, err := auth.GetToken()
+		if err != nil {
+			return nil, err
+		}
+		return optimization.NewClientWithToken(endpoint, token), nil
+	}
+
+	return optimization.NewClientWithAuthenticator(auth)
+}
+
+func (m *providerModel) authOptions() *common.AuthOptions {
+	opts := &common.AuthOptions{
+		IdentityEndpoint: m.AuthURL.ValueString(),
+		Username:         m.Username.ValueString(),
+		Password:         m.Password.ValueString(),
+		DomainID:         m.UserDomainID.ValueString(),
+		DomainName:       m.UserDomainName.ValueString(),
+		AllowReauth:      true,
+	}
+
+	if m.ProjectName.ValueString() != "" {
+		opts.Scope = &gophercloud.AuthScope{
+			ProjectName: m.ProjectName.ValueString(),
+			DomainID:    m.ProjectDomainID.ValueString(),
+			DomainName:  m.ProjectDomainName.ValueString(),
+		}
+		if opts.Scope.DomainID == "" && opts.Scope.DomainName == "" {
+			opts.Scope.DomainID = opts.DomainID
+			opts.Scope.DomainName = opts.DomainName
+		}
+	}
+
+	return opts
+}
+
+func valueOrEnv(value types.String, env string) string {
+	if !value.IsNull() {
+		return value.ValueString()
+	}
+	return os.Getenv(env)
+}
+
+// Resources implements provider.Provider
+func (p *safirProvider) Resources(_ context.Context) []func() resource.Resource {
+	return []func() resource.Resource{
+		newClusterResource,
+		newClusterHostResource,
+		newClusterExcludedVMResource,
+		newWorkloadBalancingPolicyResource,
+		newWorkloadConsolidationPolicyResource,
+		newHostMaintenancePolicyResource,
+	}
+}
+
+// DataSources implements provider.Provider
+func (p *safirProvider) DataSources(_ context.Context) []func() datasource.DataSource {
+	return []func() datasource.DataSource{
+		newClusterDataSource,
+		newClusterHostDataSource,
+		newWorkloadBalancingPolicyDataSource,
+		newWorkloadConsolidationPolicyDataSource,
+		newHostMaintenancePolicyDataSource,
+	}
+}
+
+// resourceBase gives a resource access to the client
+type resourceBase struct {
+	client *optimization.Client
+}
+
+// Configure implements resource.ResourceWithConfigure
+func (b *resourceBase) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	b.client = providerClient(req.ProviderData, &resp.Diagnostics)
+}
+
+// dataSourceBase gives a data source access to the client
+type dataSourceBase struct {
+	client *optimization.Client
+}
+
+// Configure implements datasource.DataSourceWithConfigure
+func (b *dataSourceBase) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	b.client = providerClient(req.ProviderData, &resp.Diagnostics)
+}
+
+// providerClient returns the client set by Configure. Provider data is nil
+// while Terraform validates the configuration.
+func providerClient(data any, diags *diag.Diagnostics) *optimization.Client {
+	if data == nil {
+		return nil
+	}
+
+	client, ok := data.(*optimization.Client)
+	if !ok {
+		diags.AddError("Unexpected provider data", fmt.Sprintf("expected *optimization.Client, got %T", data))
+		return nil
+	}
+	return client
+}
+
+// importNestedID imports a resource of a cluster from an ID of the form
+// "<cluster_id>/<id>"
+func importNestedID(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	clusterID, id, ok := strings.Cut(req.ID, "/")
+	if !ok || clusterID == "" || id == "" {
+		resp.Diagnostics.AddError("Invalid import ID", fmt.Sprintf("expected <cluster_id>/<id>, got %q", req.ID))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("cluster_id"), clusterID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+
+	"github.com/overwatch144/golang-safirclient/fakesafir"
+)
+
+// testAccProtoV6ProviderFactories runs the provider in process for the
+// acceptance tests
+var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"safir": providerserver.NewProtocol6WithError(New("test")()),
+}
+
+// newAccFakeServer starts a fake Safir for one acceptance test. The
+// acceptance tests need TF_ACC and a terraform binary, but no cloud.
+func newAccFakeServer(t *testing.T) *fakesafir.Server {
+	t.Helper()
+
+	srv := fakesafir.NewServer()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// providerConfig is the provider block authenticating with the fake
+func providerConfig(srv *fakesafir.Server) string {
+	return fmt.Sprintf(`
+provider "safir" {
+  auth_url       = %q
+  username       = %q
+  password       = %q
+  user_domain_id = %q
+  project_name   = %q
+}
+`, srv.IdentityEndpoint(), fakesafir.Username, fakesafir.Password, fakesafir.DomainID, fakesafir.ProjectName)
+}
@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+)
+
+// workloadBalancingPolicyDataSource looks up a workload balancing policy by name
+type workloadBalancingPolicyDataSource struct {
+	dataSourceBase
+}
+
+func newWorkloadBalancingPolicyDataSource() datasource.DataSource {
+	return &workloadBalancingPolicyDataSource{}
+}
+
+// Metadata implements datasource.DataSource
+func (d *workloadBalancingPolicyDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workload_balancing_policy"
+}
+
+// Schema implements datasource.DataSource
+func (d *workloadBalancingPolicyDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Looks up a Safir workload balancing policy by name, optionally within one cluster. The name must match exactly one policy.",
+		Attributes: map[string]schema.Attribute{
+			"name":             schema.StringAttribute{Required: true},
+			"cluster_id":       schema.StringAttribute{Optional: true, Computed: true},
+			"id":               schema.StringAttribute{Computed: true},
+			"balancing_mode":   schema.StringAttribute{Computed: true},
+			"cpu_balancing":    schema.BoolAttribute{Computed: true},
+			"memory_balancing": schema.BoolAttribute{Computed: true},
+			"period":           schema.Int64Attribute{Computed: true},
+			"enabled":          schema.BoolAttribute{Computed: true},
+			"created_at":       schema.StringAttribute{Computed: true},
+			"updated_at":       schema.StringAttribute{Computed: true},
+		},
+	}
+}
+
+// Read implements datasource.DataSource
+func (d *workloadBalancingPolicyDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config workloadBalancingPolicyModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policy, err := d.client.FindWorkloadBalancingPolicyByName(config.ClusterID.ValueStringPointer(), config.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to look up workload balancing policy", err.Error())
+		return
+	}
+
+	config.fromAPI(policy)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
@@ -0,0 +1,207 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+// workloadBalancingPolicyResource manages a Safir workload balancing policy
+type workloadBalancingPolicyResource struct {
+	resourceBase
+}
+
+type workloadBalancingPolicyModel struct {
+	ID              types.String `tfsdk:"id"`
+	ClusterID       types.String `tfsdk:"cluster_id"`
+	Name            types.String `tfsdk:"name"`
+	BalancingMode   types.String `tfsdk:"balancing_mode"`
+	CPUBalancing    types.Bool   `tfsdk:"cpu_balancing"`
+	MemoryBalancing types.Bool   `tfsdk:"memory_balancing"`
+	Period          types.Int64  `tfsdk:"period"`
+	Enabled         types.Bool   `tfsdk:"enabled"`
+	CreatedAt       types.String `tfsdk:"created_at"`
+	UpdatedAt       types.String `tfsdk:"updated_at"`
+}
+
+func newWorkloadBalancingPolicyResource() resource.Resource {
+	return &workloadBalancingPolicyResource{}
+}
+
+func (m *workloadBalancingPolicyModel) fromAPI(policy *optimization.WorkloadBalancingPolicy) {
+	m.ID = types.StringValue(policy.ID)
+	m.ClusterID = types.StringValue(policy.ClusterID)
+	m.Name = types.StringValue(policy.Name)
+	m.BalancingMode = types.StringValue(string(policy.BalancingMode))
+	m.CPUBalancing = types.BoolValue(policy.CPUBalancing)
+	m.MemoryBalancing = types.BoolValue(policy.MemoryBalancing)
+	m.Period = types.Int64Value(int64(policy.Period))
+	m.Enabled = types.BoolValue(policy.Enabled)
+	m.CreatedAt = types.StringValue(policy.CreatedAt)
+	m.UpdatedAt = types.StringValue(policy.UpdatedAt)
+}
+
+// Metadata implements resource.Resource
+func (r *workloadBalancingPolicyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workload_balancing_policy"
+}
+
+// Schema implements resource.Resource
+func (r *workloadBalancingPolicyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "A Safir workload balancing policy. Import by policy ID.",
+		Attributes: map[string]schema.Attribute{
+			"id": idAttribute(),
+			"cluster_id": schema.StringAttribute{
+				Required: true,
+			},
+			"name": schema.StringAttribute{
+				Required: true,
+			},
+			"balancing_mode": schema.StringAttribute{
+				Description: "One of conservative, moderate or aggressive.",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(balancingModes()...),
+				},
+			},
+			"cpu_balancing": schema.BoolAttribute{
+				Description: "Balance CPU load.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"memory_balancing": schema.BoolAttribute{
+				Description: "Balance memory load.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"period": schema.Int64Attribute{
+				Description: "Seconds between two runs of the policy.",
+				Required:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"enabled": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(true),
+			},
+			"created_at": createdAtAttribute(),
+			"updated_at": updatedAtAttribute(),
+		},
+	}
+}
+
+// Create implements resource.Resource
+func (r *workloadBalancingPolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan workloadBalancingPolicyModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policy, err := r.client.CreateWorkloadBalancingPolicy(&optimization.WorkloadBalancingPolicyCreate{
+		ClusterID:       plan.ClusterID.ValueString(),
+		Name:            plan.Name.ValueString(),
+		BalancingMode:   optimization.BalancingMode(plan.BalancingMode.ValueString()),
+		CPUBalancing:    plan.CPUBalancing.ValueBool(),
+		MemoryBalancing: plan.MemoryBalancing.ValueBool(),
+		Period:          int(plan.Period.ValueInt64()),
+		Enabled:         plan.Enabled.ValueBool(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create workload balancing policy", err.Error())
+		return
+	}
+
+	plan.fromAPI(policy)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read implements resource.Resource
+func (r *workloadBalancingPolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state workloadBalancingPolicyModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policy, err := r.client.GetWorkloadBalancingPolicy(state.ID.ValueString())
+	if common.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read workload balancing policy", err.Error())
+		return
+	}
+
+	state.fromAPI(policy)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update implements resource.Resource
+func (r *workloadBalancingPolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan workloadBalancingPolicyModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policy, err := r.client.UpdateWorkloadBalancingPolicy(plan.ID.ValueString(), &optimization.WorkloadBalancingPolicyUpdate{
+		ClusterID:       plan.ClusterID.ValueStringPointer(),
+		Name:            plan.Name.ValueStringPointer(),
+		BalancingMode:   common.Ptr(optimization.BalancingMode(plan.BalancingMode.ValueString())),
+		CPUBalancing:    plan.CPUBalancing.ValueBoolPointer(),
+		MemoryBalancing: plan.MemoryBalancing.ValueBoolPointer(),
+		Period:          common.Ptr(int(plan.Period.ValueInt64())),
+		Enabled:         plan.Enabled.ValueBoolPointer(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update workload balancing policy", err.Error())
+		return
+	}
+
+	plan.fromAPI(policy)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete implements resource.Resource
+func (r *workloadBalancingPolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state workloadBalancingPolicyModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteWorkloadBalancingPolicy(state.ID.ValueString()); err != nil && !common.IsNotFound(err) {
+		resp.Diagnostics.AddError("Failed to delete workload balancing policy", err.Error())
+	}
+}
+
+// ImportState implements resource.ResourceWithImportState
+func (r *workloadBalancingPolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func balancingModes() []string {
+	supported := optimization.BalancingModes()
+	modes := make([]string, len(supported))
+	for i, mode := range supported {
+		modes[i] = string(mode)
+	}
+	return modes
+}
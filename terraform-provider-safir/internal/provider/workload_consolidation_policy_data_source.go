@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+)
+
+// workloadConsolidationPolicyDataSource looks up a workload consolidation policy by name
+type workloadConsolidationPolicyDataSource struct {
+	dataSourceBase
+}
+
+func newWorkloadConsolidationPolicyDataSource() datasource.DataSource {
+	return &workloadConsolidationPolicyDataSource{}
+}
+
+// Metadata implements datasource.DataSource
+func (d *workloadConsolidationPolicyDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workload_consolidation_policy"
+}
+
+// Schema implements datasource.DataSource
+func (d *workloadConsolidationPolicyDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Looks up a Safir workload consolidation policy by name, optionally within one cluster. The name must match exactly one policy.",
+		Attributes: map[string]schema.Attribute{
+			"name":       schema.StringAttribute{Required: true},
+			"cluster_id": schema.StringAttribute{Optional: true, Computed: true},
+			"id":         schema.StringAttribute{Computed: true},
+			"period":     schema.Int64Attribute{Computed: true},
+			"enabled":    schema.BoolAttribute{Computed: true},
+			"created_at": schema.StringAttribute{Computed: true},
+			"updated_at": schema.StringAttribute{Computed: true},
+		},
+	}
+}
+
+// Read implements datasource.DataSource
+func (d *workloadConsolidationPolicyDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config workloadConsolidationPolicyModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policy, err := d.client.FindWorkloadConsolidationPolicyByName(config.ClusterID.ValueStringPointer(), config.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to look up workload consolidation policy", err.Error())
+		return
+	}
+
+	config.fromAPI(policy)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
@@ -0,0 +1,166 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/overwatch144/golang-safirclient/common"
+	"github.com/overwatch144/golang-safirclient/optimization"
+)
+
+// workloadConsolidationPolicyResource manages a Safir workload consolidation policy
+type workloadConsolidationPolicyResource struct {
+	resourceBase
+}
+
+type workloadConsolidationPolicyModel struct {
+	ID        types.String `tfsdk:"id"`
+	ClusterID types.String `tfsdk:"cluster_id"`
+	Name      types.String `tfsdk:"name"`
+	Period    types.Int64  `tfsdk:"period"`
+	Enabled   types.Bool   `tfsdk:"enabled"`
+	CreatedAt types.String `tfsdk:"created_at"`
+	UpdatedAt types.String `tfsdk:"updated_at"`
+}
+
+func newWorkloadConsolidationPolicyResource() resource.Resource {
+	return &workloadConsolidationPolicyResource{}
+}
+
+func (m *workloadConsolidationPolicyModel) fromAPI(policy *optimization.WorkloadConsolidationPolicy) {
+	m.ID = types.StringValue(policy.ID)
+	m.ClusterID = types.StringValue(policy.ClusterID)
+	m.Name = types.StringValue(policy.Name)
+	m.Period = types.Int64Value(int64(policy.Period))
+	m.Enabled = types.BoolValue(policy.Enabled)
+	m.CreatedAt = types.StringValue(policy.CreatedAt)
+	m.UpdatedAt = types.StringValue(policy.UpdatedAt)
+}
+
+// Metadata implements resource.Resource
+func (r *workloadConsolidationPolicyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workload_consolidation_policy"
+}
+
+// Schema implements resource.Resource
+func (r *workloadConsolidationPolicyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "A Safir workload consolidation policy. Import by policy ID.",
+		Attributes: map[string]schema.Attribute{
+			"id": idAttribute(),
+			"cluster_id": schema.StringAttribute{
+				Required: true,
+			},
+			"name": schema.StringAttribute{
+				Required: true,
+			},
+			"period": schema.Int64Attribute{
+				Description: "Seconds between two runs of the policy.",
+				Required:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"enabled": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(true),
+			},
+			"created_at": createdAtAttribute(),
+			"updated_at": updatedAtAttribute(),
+		},
+	}
+}
+
+// Create implements resource.Resource
+func (r *workloadConsolidationPolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan workloadConsolidationPolicyModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policy, err := r.client.CreateWorkloadConsolidationPolicy(&optimization.WorkloadConsolidationPolicyCreate{
+		ClusterID: plan.ClusterID.ValueString(),
+		Name:      plan.Name.ValueString(),
+		Period:    int(plan.Period.ValueInt64()),
+		Enabled:   plan.Enabled.ValueBool(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create workload consolidation policy", err.Error())
+		return
+	}
+
+	plan.fromAPI(policy)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read implements resource.Resource
+func (r *workloadConsolidationPolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state workloadConsolidationPolicyModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policy, err := r.client.GetWorkloadConsolidationPolicy(state.ID.ValueString())
+	if common.IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read workload consolidation policy", err.Error())
+		return
+	}
+
+	state.fromAPI(policy)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update implements resource.Resource
+func (r *workloadConsolidationPolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan workloadConsolidationPolicyModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policy, err := r.client.UpdateWorkloadConsolidationPolicy(plan.ID.ValueString(), &optimization.WorkloadConsolidationPolicyUpdate{
+		ClusterID: plan.ClusterID.ValueStringPointer(),
+		Name:      plan.Name.ValueStringPointer(),
+		Period:    common.Ptr(int(plan.Period.ValueInt64())),
+		Enabled:   plan.Enabled.ValueBoolPointer(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update workload consolidation policy", err.Error())
+		return
+	}
+
+	plan.fromAPI(policy)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete implements resource.Resource
+func (r *workloadConsolidationPolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state workloadConsolidationPolicyModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteWorkloadConsolidationPolicy(state.ID.ValueString()); err != nil && !common.IsNotFound(err) {
+		resp.Diagnostics.AddError("Failed to delete workload consolidation policy", err.Error())
+	}
+}
+
+// ImportState implements resource.ResourceWithImportState
+func (r *workloadConsolidationPolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
@@ -0,0 +1,30 @@
+// Command terraform-provider-safir is a Terraform provider for the Safir
+// Optimization API.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+
+	"github.com/overwatch144/golang-safirclient/terraform-provider-safir/internal/provider"
+)
+
+// version is set by the release build
+var version = "dev"
+
+func main() {
+	var debug bool
+	flag.BoolVar(&debug, "debug", false, "run the provider with support for debuggers like delve")
+	flag.Parse()
+
+	err := providerserver.Serve(context.Background(), provider.New(version), providerserver.ServeOpts{
+		Address: "registry.terraform.io/overwatch144/safir",
+		Debug:   debug,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+}